@@ -0,0 +1,304 @@
+// Package account 基于 go-ethereum keystore 管理加密的签名账户
+//
+// 私钥以 Web3 Secret Storage 格式 (scrypt + AES-128-CTR) 加密保存在 keystore 目录中, 每个账户一个文件,
+// 与 geth / Clef 的 keystore 兼容。签名时通过 --from 选择账户, 只在需要签名时解密私钥;
+// 环境文件中的明文 PRIVATE_KEY 只作为兼容方案, 需要通过 --from env 显式选择, 使用时会输出警告。
+// 所有命令通过 Signer 接口签名, 也可以通过 --signer-url 交给 Clef 等外部签名服务签名
+package account
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"task1/util"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/console/prompt"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// FromEnv --from 的特殊值, 表示使用环境文件中的 PRIVATE_KEY
+const FromEnv = "env"
+
+// DefaultKeystoreDir 未通过 --keystore 或 KEYSTORE_DIR 指定时使用的 keystore 目录
+const DefaultKeystoreDir = "~/.task1_keystore"
+
+var (
+	// ErrNoAccount 没有可用的签名账户
+	ErrNoAccount = errors.New("没有可用的签名账户")
+	// ErrAmbiguousAccount keystore 中有多个账户且没有通过 --from 指定
+	ErrAmbiguousAccount = errors.New("需要通过 --from 指定签名账户")
+)
+
+// Config 账户配置
+type Config struct {
+	KeystoreDir  string  // keystore 目录, 为空时使用 DefaultKeystoreDir
+	From         string  // 签名账户地址, 或 FromEnv 表示使用环境文件中的 PRIVATE_KEY
+	PasswordFile string  // 密码文件, 读取第一行作为密码, 为空时在终端提示输入
+	AccountIndex *uint32 // 使用助记词派生的第 i 个账户签名, 为 nil 表示不使用助记词
+	HDPath       string  // 派生基础路径, 为空时使用 DefaultHDPath
+	SignerURL    string  // Clef 兼容的外部签名服务地址, 为空时使用本地账户
+}
+
+// config 通过命令行参数指定的账户配置
+var config Config
+
+// SetConfig 设置签名账户配置, 并释放按之前配置缓存的签名器
+func SetConfig(c Config) {
+	CloseSigner()
+	config = c
+}
+
+// CurrentConfig 返回当前的账户配置
+func CurrentConfig() Config {
+	return config
+}
+
+// keystoreDir 返回展开 ~ 后的 keystore 目录
+func keystoreDir() string {
+	dir := config.KeystoreDir
+	if dir == "" {
+		dir = DefaultKeystoreDir
+	}
+	return util.ExpandHome(dir)
+}
+
+// Open 打开 keystore 目录, 不存在时创建
+func Open() (*keystore.KeyStore, error) {
+	dir := keystoreDir()
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("%w: 创建 keystore 目录 %s 失败: %w", util.ErrConfig, dir, err)
+	}
+	return keystore.NewKeyStore(dir, keystore.StandardScryptN, keystore.StandardScryptP), nil
+}
+
+// List 返回 keystore 中的全部账户, 按文件名排序
+func List() ([]accounts.Account, error) {
+	ks, err := Open()
+	if err != nil {
+		return nil, err
+	}
+	return ks.Accounts(), nil
+}
+
+// New 生成新的随机私钥并加密保存到 keystore
+func New(passphrase string) (accounts.Account, error) {
+	ks, err := Open()
+	if err != nil {
+		return accounts.Account{}, err
+	}
+	acct, err := ks.NewAccount(passphrase)
+	if err != nil {
+		return accounts.Account{}, fmt.Errorf("创建账户失败: %w", err)
+	}
+	util.Logf("已创建账户 %s, keystore 文件: %s", acct.Address.Hex(), acct.URL.Path)
+	return acct, nil
+}
+
+// ImportKey 将明文私钥加密后导入 keystore
+func ImportKey(key *ecdsa.PrivateKey, passphrase string) (accounts.Account, error) {
+	ks, err := Open()
+	if err != nil {
+		return accounts.Account{}, err
+	}
+	acct, err := ks.ImportECDSA(key, passphrase)
+	if err != nil {
+		return accounts.Account{}, fmt.Errorf("导入账户失败: %w", err)
+	}
+	util.Logf("已导入账户 %s, keystore 文件: %s", acct.Address.Hex(), acct.URL.Path)
+	return acct, nil
+}
+
+// ImportJSON 导入其他工具 (geth、MetaMask 等) 导出的 keystore 文件, 使用 newPassphrase 重新加密
+func ImportJSON(keyJSON []byte, passphrase, newPassphrase string) (accounts.Account, error) {
+	ks, err := Open()
+	if err != nil {
+		return accounts.Account{}, err
+	}
+	acct, err := ks.Import(keyJSON, passphrase, newPassphrase)
+	if err != nil {
+		return accounts.Account{}, fmt.Errorf("导入 keystore 文件失败: %w", err)
+	}
+	util.Logf("已导入账户 %s, keystore 文件: %s", acct.Address.Hex(), acct.URL.Path)
+	return acct, nil
+}
+
+// Export 导出账户的 keystore 文件, 使用 newPassphrase 重新加密
+func Export(address common.Address, passphrase, newPassphrase string) ([]byte, error) {
+	ks, acct, err := find(address)
+	if err != nil {
+		return nil, err
+	}
+	keyJSON, err := ks.Export(acct, passphrase, newPassphrase)
+	if err != nil {
+		return nil, fmt.Errorf("导出账户 %s 失败: %w", address.Hex(), err)
+	}
+	return keyJSON, nil
+}
+
+// find 在 keystore 中查找账户
+func find(address common.Address) (*keystore.KeyStore, accounts.Account, error) {
+	ks, err := Open()
+	if err != nil {
+		return nil, accounts.Account{}, err
+	}
+	acct, err := ks.Find(accounts.Account{Address: address})
+	if err != nil {
+		return nil, accounts.Account{}, fmt.Errorf("%w: keystore %s 中没有账户 %s", ErrNoAccount, keystoreDir(), address.Hex())
+	}
+	return ks, acct, nil
+}
+
+// ParseAddress 解析账户地址
+func ParseAddress(address string) (common.Address, error) {
+	if !common.IsHexAddress(address) {
+		return common.Address{}, fmt.Errorf("%w: 账户地址格式错误: %s", util.ErrInvalidArgument, address)
+	}
+	return common.HexToAddress(address), nil
+}
+
+// Address 返回当前选择的签名账户地址, 不解密私钥
+// --from 指定的地址可以不在 keystore 中, 例如在联网机器上为冷钱包构建交易
+func Address(ctx context.Context) (common.Address, error) {
+	from := strings.TrimSpace(config.From)
+	switch {
+	case config.AccountIndex != nil, config.SignerURL != "" && from == "":
+		// 助记词账户需要解密助记词, 签名服务需要查询唯一的账户, 直接创建签名器供之后签名时复用
+		signer, err := LoadSigner(ctx)
+		if err != nil {
+			return common.Address{}, err
+		}
+		return signer.Address(), nil
+	case from == FromEnv:
+		key, err := envKey()
+		if err != nil {
+			return common.Address{}, err
+		}
+		return crypto.PubkeyToAddress(key.PublicKey), nil
+	case from == "":
+		acct, err := defaultAccount()
+		if err != nil {
+			return common.Address{}, err
+		}
+		return acct.Address, nil
+	}
+	return ParseAddress(from)
+}
+
+// loadLocalSigner 创建当前选择的本地账户签名器
+//
+// 账户选择顺序:
+//
+//  1. --account-index i: 助记词派生的第 i 个账户, 同时指定 --from 时校验地址一致
+//  2. --from env: 环境文件中的 PRIVATE_KEY
+//  3. --from <地址>: keystore 中的对应账户
+//  4. 未指定 --from: keystore 中只有一个账户时使用该账户
+//
+// PRIVATE_KEY 只在 --from env 时使用, 并输出警告
+func loadLocalSigner() (Signer, error) {
+	if config.AccountIndex != nil {
+		acct, err := hdAccount()
+		if err != nil {
+			return nil, err
+		}
+		return NewKeySigner(acct.key, fmt.Sprintf("助记词派生账户 #%d (%s)", acct.Index, acct.Path)), nil
+	}
+	from := strings.TrimSpace(config.From)
+	switch from {
+	case FromEnv:
+		return envSigner()
+	case "":
+		acct, err := defaultAccount()
+		if err != nil {
+			return nil, err
+		}
+		ks, err := Open()
+		if err != nil {
+			return nil, err
+		}
+		return NewKeystoreSigner(ks, *acct)
+	}
+
+	address, err := ParseAddress(from)
+	if err != nil {
+		return nil, err
+	}
+	ks, acct, err := find(address)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w, 请先执行 account import 导入, 环境文件中的 PRIVATE_KEY 可通过 account import --from-env 导入或 --from env 使用", util.ErrConfig, err)
+	}
+	return NewKeystoreSigner(ks, acct)
+}
+
+// defaultAccount 未指定 --from 时选择 keystore 中唯一的账户
+func defaultAccount() (*accounts.Account, error) {
+	accts, err := List()
+	if err != nil {
+		return nil, err
+	}
+	switch len(accts) {
+	case 0:
+		return nil, fmt.Errorf("%w: %w, keystore %s 中没有账户, 请执行 account new / account import 创建, 环境文件中的 PRIVATE_KEY 可通过 account import --from-env 导入或 --from env 使用",
+			util.ErrConfig, ErrNoAccount, keystoreDir())
+	case 1:
+		return &accts[0], nil
+	}
+	return nil, fmt.Errorf("%w: %w, keystore 中有 %d 个账户 (可通过 account list 查看)", util.ErrConfig, ErrAmbiguousAccount, len(accts))
+}
+
+// envKey 解析环境文件中的 PRIVATE_KEY
+func envKey() (*ecdsa.PrivateKey, error) {
+	hexKey := strings.TrimSpace(util.LoadEnv("<PRIVATE_KEY>"))
+	if hexKey == "" {
+		return nil, fmt.Errorf("%w: %w, 请执行 account new / account import 创建 keystore 账户", util.ErrConfig, ErrNoAccount)
+	}
+	return util.LoadPrivateKey()
+}
+
+// envSigner 使用环境文件中的明文私钥签名, 并提示迁移到 keystore
+func envSigner() (Signer, error) {
+	key, err := envKey()
+	if err != nil {
+		return nil, err
+	}
+	util.Logf("警告: 使用环境文件中的明文私钥 PRIVATE_KEY (%s) 签名, 建议执行 account import --from-env 导入加密的 keystore 后从环境文件中删除",
+		crypto.PubkeyToAddress(key.PublicKey).Hex())
+	return NewKeySigner(key, "环境文件私钥"), nil
+}
+
+// Passphrase 读取账户密码: 指定了密码文件时读取第一行, 否则在终端提示输入 (不回显)
+// confirm 为 true 时要求输入两次, 用于设置新密码
+func Passphrase(message string, confirm bool) (string, error) {
+	if config.PasswordFile != "" {
+		return readPasswordFile(config.PasswordFile)
+	}
+	passphrase, err := prompt.Stdin.PromptPassword(message)
+	if err != nil {
+		return "", fmt.Errorf("%w: 读取密码失败: %w", util.ErrInvalidArgument, err)
+	}
+	if confirm {
+		again, err := prompt.Stdin.PromptPassword("请再次输入密码: ")
+		if err != nil {
+			return "", fmt.Errorf("%w: 读取密码失败: %w", util.ErrInvalidArgument, err)
+		}
+		if again != passphrase {
+			return "", fmt.Errorf("%w: 两次输入的密码不一致", util.ErrInvalidArgument)
+		}
+	}
+	return passphrase, nil
+}
+
+// readPasswordFile 读取密码文件的第一行, 去掉行尾换行符
+func readPasswordFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("%w: 读取密码文件失败: %w", util.ErrConfig, err)
+	}
+	line, _, _ := strings.Cut(string(data), "\n")
+	return strings.TrimRight(line, "\r"), nil
+}
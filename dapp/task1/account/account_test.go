@@ -0,0 +1,49 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"task1/util"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/viper"
+)
+
+// hardhatKey Hardhat 默认助记词的第一个账户
+const hardhatKey = "0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
+
+var hardhatAddress = common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+
+// TestEnvKeyRequiresFromEnv keystore 中没有账户时不会回退到 PRIVATE_KEY, 只有 --from env 才使用
+func TestEnvKeyRequiresFromEnv(t *testing.T) {
+	ctx := context.Background()
+	viper.Set("PRIVATE_KEY", hardhatKey)
+	t.Cleanup(func() { viper.Set("PRIVATE_KEY", "") })
+	dir := t.TempDir()
+	t.Cleanup(func() { SetConfig(Config{}) })
+
+	for _, from := range []string{"", hardhatAddress.Hex()} {
+		SetConfig(Config{KeystoreDir: dir, From: from})
+		if _, err := LoadSigner(ctx); !errors.Is(err, util.ErrConfig) {
+			t.Errorf("--from %q 的签名器错误为 %v, 预期 %v", from, err, util.ErrConfig)
+		}
+	}
+	SetConfig(Config{KeystoreDir: dir})
+	if _, err := Address(ctx); !errors.Is(err, ErrNoAccount) {
+		t.Errorf("未指定 --from 时的地址错误为 %v, 预期 %v", err, ErrNoAccount)
+	}
+
+	SetConfig(Config{KeystoreDir: dir, From: FromEnv})
+	address, err := Address(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := LoadSigner(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if address != hardhatAddress || signer.Address() != hardhatAddress {
+		t.Errorf("--from env 的账户为 %s / %s, 预期 %s", address.Hex(), signer.Address().Hex(), hardhatAddress.Hex())
+	}
+}
@@ -0,0 +1,243 @@
+package account
+
+import (
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"task1/util"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/tyler-smith/go-bip39"
+)
+
+// DefaultHDPath BIP-44 以太坊账户的默认基础路径, 第 i 个账户为 m/44'/60'/0'/0/i
+const DefaultHDPath = "m/44'/60'/0'/0"
+
+// hardenedKeyStart 强化派生的起始索引 2^31, 更小的索引为普通派生
+const hardenedKeyStart = 0x80000000
+
+// mnemonicFile keystore 目录下加密保存助记词的文件
+// 放在子目录中, 避免被 keystore 当作账户文件扫描
+var mnemonicFile = filepath.Join("hd", "mnemonic.json")
+
+// ErrNoMnemonic 没有配置助记词
+var ErrNoMnemonic = errors.New("没有配置助记词")
+
+// NewMnemonic 生成新的 BIP-39 英文助记词, words 为 12、15、18、21 或 24
+func NewMnemonic(words int) (string, error) {
+	if words < 12 || words > 24 || words%3 != 0 {
+		return "", fmt.Errorf("%w: 助记词单词数只能是 12、15、18、21 或 24: %d", util.ErrInvalidArgument, words)
+	}
+	entropy, err := bip39.NewEntropy(words / 3 * 32)
+	if err != nil {
+		return "", fmt.Errorf("生成随机数失败: %w", err)
+	}
+	return bip39.NewMnemonic(entropy)
+}
+
+// NormalizeMnemonic 合并多余的空白并校验助记词的单词和校验和
+func NormalizeMnemonic(mnemonic string) (string, error) {
+	mnemonic = strings.Join(strings.Fields(strings.ToLower(mnemonic)), " ")
+	if _, err := bip39.EntropyFromMnemonic(mnemonic); err != nil {
+		return "", fmt.Errorf("%w: 无效的助记词: %w", util.ErrInvalidArgument, err)
+	}
+	return mnemonic, nil
+}
+
+// ParseHDPath 解析派生基础路径, 为空时使用 DefaultHDPath
+func ParseHDPath(path string) (accounts.DerivationPath, error) {
+	if path == "" {
+		path = DefaultHDPath
+	}
+	base, err := accounts.ParseDerivationPath(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: 派生路径格式错误 %q: %w", util.ErrInvalidArgument, path, err)
+	}
+	return base, nil
+}
+
+// HDPath 返回基础路径下第 index 个账户的派生路径
+func HDPath(base accounts.DerivationPath, index uint32) accounts.DerivationPath {
+	path := make(accounts.DerivationPath, len(base), len(base)+1)
+	copy(path, base)
+	return append(path, index)
+}
+
+// DeriveKey 按 BIP-39 从助记词和可选密码生成种子, 再按 BIP-32 派生 path 对应的私钥
+func DeriveKey(mnemonic, passphrase string, path accounts.DerivationPath) (*ecdsa.PrivateKey, error) {
+	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("%w: 无效的助记词: %w", util.ErrInvalidArgument, err)
+	}
+	return DeriveKeyFromSeed(seed, path)
+}
+
+// DeriveKeyFromSeed 按 BIP-32 从种子派生 path 对应的私钥
+func DeriveKeyFromSeed(seed []byte, path accounts.DerivationPath) (*ecdsa.PrivateKey, error) {
+	// 主私钥: HMAC-SHA512(key = "Bitcoin seed", data = seed)
+	mac := hmac.New(sha512.New, []byte("Bitcoin seed"))
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+	key, chainCode := sum[:32], sum[32:]
+
+	n := crypto.S256().Params().N
+	for _, index := range path {
+		// 强化派生使用私钥, 普通派生使用压缩公钥
+		var data []byte
+		if index >= hardenedKeyStart {
+			data = append([]byte{0}, key...)
+		} else {
+			private, err := crypto.ToECDSA(key)
+			if err != nil {
+				return nil, err
+			}
+			data = crypto.CompressPubkey(&private.PublicKey)
+		}
+		data = binary.BigEndian.AppendUint32(data, index)
+
+		mac := hmac.New(sha512.New, chainCode)
+		mac.Write(data)
+		sum := mac.Sum(nil)
+		il := new(big.Int).SetBytes(sum[:32])
+		child := new(big.Int).Add(il, new(big.Int).SetBytes(key))
+		child.Mod(child, n)
+		// 概率低于 2^-127, BIP-32 规定跳过该索引, 这里直接报错
+		if il.Cmp(n) >= 0 || child.Sign() == 0 {
+			return nil, fmt.Errorf("派生路径 %s 的索引 %d 无效, 请使用下一个索引", path, index)
+		}
+		key, chainCode = child.FillBytes(make([]byte, 32)), sum[32:]
+	}
+	return crypto.ToECDSA(key)
+}
+
+// mnemonicJSON 加密保存的助记词, 与 keystore 账户文件使用相同的 scrypt + AES-128-CTR 加密
+type mnemonicJSON struct {
+	Version int                 `json:"version"`
+	Crypto  keystore.CryptoJSON `json:"crypto"`
+}
+
+// SaveMnemonic 使用密码加密助记词并保存到 keystore 目录, 覆盖已有的助记词
+func SaveMnemonic(mnemonic, password string) (string, error) {
+	mnemonic, err := NormalizeMnemonic(mnemonic)
+	if err != nil {
+		return "", err
+	}
+	encrypted, err := keystore.EncryptDataV3([]byte(mnemonic), []byte(password), keystore.StandardScryptN, keystore.StandardScryptP)
+	if err != nil {
+		return "", fmt.Errorf("加密助记词失败: %w", err)
+	}
+	data, err := json.Marshal(mnemonicJSON{Version: 3, Crypto: encrypted})
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(keystoreDir(), mnemonicFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return "", fmt.Errorf("%w: 创建助记词目录失败: %w", util.ErrConfig, err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return "", fmt.Errorf("保存助记词失败: %w", err)
+	}
+	util.Logf("助记词已加密保存到 %s", path)
+	return path, nil
+}
+
+// LoadMnemonic 读取助记词: 优先解密 keystore 目录中保存的助记词, 其次使用环境文件中的明文 MNEMONIC (输出警告)
+func LoadMnemonic() (string, error) {
+	path := filepath.Join(keystoreDir(), mnemonicFile)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		mnemonic := strings.TrimSpace(util.LoadEnv("<MNEMONIC>"))
+		if mnemonic == "" {
+			return "", fmt.Errorf("%w: %w, 请执行 account mnemonic new / account mnemonic import", util.ErrConfig, ErrNoMnemonic)
+		}
+		util.Logf("警告: 使用环境文件中的明文助记词 MNEMONIC, 建议执行 account mnemonic import --from-env 加密保存后从环境文件中删除")
+		return NormalizeMnemonic(mnemonic)
+	}
+	if err != nil {
+		return "", fmt.Errorf("%w: 读取助记词失败: %w", util.ErrConfig, err)
+	}
+	var file mnemonicJSON
+	if err := json.Unmarshal(data, &file); err != nil {
+		return "", fmt.Errorf("%w: 解析助记词文件 %s 失败: %w", util.ErrConfig, path, err)
+	}
+	password, err := Passphrase("请输入助记词密码: ", false)
+	if err != nil {
+		return "", err
+	}
+	mnemonic, err := keystore.DecryptDataV3(file.Crypto, password)
+	if err != nil {
+		return "", fmt.Errorf("%w: 解密助记词失败: %w", util.ErrConfig, err)
+	}
+	return string(mnemonic), nil
+}
+
+// HDAccount 从助记词派生的账户
+type HDAccount struct {
+	Index   uint32
+	Path    accounts.DerivationPath
+	Address common.Address
+	key     *ecdsa.PrivateKey
+}
+
+// MaxDeriveCount 一次最多派生的账户数量
+const MaxDeriveCount = 1000
+
+// Derive 从配置的助记词派生 [start, start+count) 范围内的账户
+// 账户索引是非强化的派生路径分量, 必须小于 2^31
+func Derive(start, count uint32) ([]HDAccount, error) {
+	if count > MaxDeriveCount {
+		return nil, fmt.Errorf("%w: 一次最多派生 %d 个账户, 实际请求 %d 个", util.ErrInvalidArgument, MaxDeriveCount, count)
+	}
+	if uint64(start)+uint64(count) > hardenedKeyStart {
+		return nil, fmt.Errorf("%w: 账户索引范围 [%d, %d) 超出非强化索引的上限 %d", util.ErrInvalidArgument, start, uint64(start)+uint64(count), hardenedKeyStart)
+	}
+	base, err := ParseHDPath(config.HDPath)
+	if err != nil {
+		return nil, err
+	}
+	mnemonic, err := LoadMnemonic()
+	if err != nil {
+		return nil, err
+	}
+	// BIP-39 种子的计算需要 2048 轮 PBKDF2, 只计算一次
+	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, util.LoadEnv("<MNEMONIC_PASSPHRASE>"))
+	if err != nil {
+		return nil, fmt.Errorf("%w: 无效的助记词: %w", util.ErrConfig, err)
+	}
+	derived := make([]HDAccount, 0, count)
+	for i := start; i < start+count; i++ {
+		path := HDPath(base, i)
+		key, err := DeriveKeyFromSeed(seed, path)
+		if err != nil {
+			return nil, err
+		}
+		derived = append(derived, HDAccount{Index: i, Path: path, Address: crypto.PubkeyToAddress(key.PublicKey), key: key})
+	}
+	return derived, nil
+}
+
+// hdAccount 派生 --account-index 选择的账户, 同时指定了 --from 时校验地址一致
+func hdAccount() (*HDAccount, error) {
+	derived, err := Derive(*config.AccountIndex, 1)
+	if err != nil {
+		return nil, err
+	}
+	acct := &derived[0]
+	if from := strings.TrimSpace(config.From); from != "" && from != FromEnv {
+		if address, err := ParseAddress(from); err == nil && address != acct.Address {
+			return nil, fmt.Errorf("%w: 派生账户 %s (%s) 与 --from %s 不一致", util.ErrInvalidArgument, acct.Address.Hex(), acct.Path, from)
+		}
+	}
+	return acct, nil
+}
@@ -0,0 +1,150 @@
+package account
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"task1/util"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/tyler-smith/go-bip39"
+)
+
+// BIP-39 官方测试向量 (https://github.com/trezor/python-mnemonic/blob/master/vectors.json), 密码为 TREZOR
+var bip39Vectors = []struct {
+	entropy  string
+	mnemonic string
+	seed     string
+}{
+	{
+		"00000000000000000000000000000000",
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+		"c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04",
+	},
+	{
+		"7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f",
+		"legal winner thank year wave sausage worth useful legal winner thank yellow",
+		"2e8905819b8723fe2c1d161860e5ee1830318dbf49a83bd451cfb8440c28bd6fa457fe1296106559a3c80937a1c1069be3a3a5bd381ee6260e8d9739fce1f607",
+	},
+	{
+		"80808080808080808080808080808080",
+		"letter advice cage absurd amount doctor acoustic avoid letter advice cage above",
+		"d71de856f81a8acc65e6fc851a38d4d7ec216fd0796d0a6827a3ad6ed5511a30fa280f12eb2e47ed2ac03b5c462a0358d18d69fe4f985ec81778c1b370b652a8",
+	},
+	{
+		"ffffffffffffffffffffffffffffffff",
+		"zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo wrong",
+		"ac27495480225222079d7be181583751e86f571027b0497b5b5d11218e0a8a13332572917f0f8e5a589620c6f15b11c61dee327651a14c34e18231052e48c069",
+	},
+}
+
+func TestBIP39Vectors(t *testing.T) {
+	for _, v := range bip39Vectors {
+		entropy, _ := hex.DecodeString(v.entropy)
+		mnemonic, err := bip39.NewMnemonic(entropy)
+		if err != nil {
+			t.Fatalf("生成助记词失败: %v", err)
+		}
+		if mnemonic != v.mnemonic {
+			t.Errorf("熵 %s 的助记词为 %q, 预期 %q", v.entropy, mnemonic, v.mnemonic)
+		}
+		// 多余的空白和大写在保存前被规范化
+		messy := " " + strings.ToUpper(strings.ReplaceAll(v.mnemonic, " ", " \t ")) + "\n"
+		normalized, err := NormalizeMnemonic(messy)
+		if err != nil || normalized != v.mnemonic {
+			t.Errorf("规范化助记词结果为 %q, %v, 预期 %q", normalized, err, v.mnemonic)
+		}
+		seed, err := bip39.NewSeedWithErrorChecking(v.mnemonic, "TREZOR")
+		if err != nil {
+			t.Fatalf("生成种子失败: %v", err)
+		}
+		if got := hex.EncodeToString(seed); got != v.seed {
+			t.Errorf("助记词 %q 的种子为 %s, 预期 %s", v.mnemonic, got, v.seed)
+		}
+	}
+}
+
+func TestNormalizeMnemonicChecksum(t *testing.T) {
+	// 最后一个单词包含校验和, 替换后校验失败
+	if _, err := NormalizeMnemonic("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon"); err == nil {
+		t.Error("校验和错误的助记词应校验失败")
+	}
+	if _, err := NormalizeMnemonic("abandon abandon abandon"); err == nil {
+		t.Error("单词数错误的助记词应校验失败")
+	}
+}
+
+// BIP-32 官方测试向量 1 (https://github.com/bitcoin/bips/blob/master/bip-0032.mediawiki#test-vector-1)
+func TestDeriveKeyFromSeedBIP32Vector(t *testing.T) {
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	// 强化派生的索引 i'
+	h := func(i uint32) uint32 { return 0x80000000 + i }
+	tests := []struct {
+		path accounts.DerivationPath
+		key  string
+	}{
+		{accounts.DerivationPath{}, "e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35"},
+		{accounts.DerivationPath{h(0)}, "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea"},
+		{accounts.DerivationPath{h(0), 1}, "3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368"},
+		{accounts.DerivationPath{h(0), 1, h(2)}, "cbce0d719ecf7431d88e6a89fa1483e02e35092af60c042b1df2ff59fa424dca"},
+		{accounts.DerivationPath{h(0), 1, h(2), 2}, "0f479245fb19a38a1954c5c7c0ebab2f9bdfd96a17563ef28a6a4b1a2a764ef4"},
+		{accounts.DerivationPath{h(0), 1, h(2), 2, 1000000000}, "471b76e389e528d6de6d816857e012c5455051cad6660850e58372a6c3e6e7c8"},
+	}
+	for _, tt := range tests {
+		key, err := DeriveKeyFromSeed(seed, tt.path)
+		if err != nil {
+			t.Fatalf("派生 %s 失败: %v", tt.path, err)
+		}
+		if got := hex.EncodeToString(crypto.FromECDSA(key)); got != tt.key {
+			t.Errorf("%s 的私钥为 %s, 预期 %s", tt.path, got, tt.key)
+		}
+	}
+}
+
+// Hardhat / Anvil 默认助记词派生的账户
+func TestDeriveKeyHardhatAccounts(t *testing.T) {
+	const mnemonic = "test test test test test test test test test test test junk"
+	base, err := ParseHDPath("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []string{
+		"0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266",
+		"0x70997970C51812dc3A010C7d01b50e0d17dc79C8",
+	} {
+		path := HDPath(base, uint32(i))
+		if got := path.String(); got != fmt.Sprintf("m/44'/60'/0'/0/%d", i) {
+			t.Errorf("第 %d 个账户的派生路径为 %s", i, got)
+		}
+		key, err := DeriveKey(mnemonic, "", path)
+		if err != nil {
+			t.Fatalf("派生 %s 失败: %v", path, err)
+		}
+		if got := crypto.PubkeyToAddress(key.PublicKey).Hex(); got != want {
+			t.Errorf("%s 的地址为 %s, 预期 %s", path, got, want)
+		}
+	}
+	// 基础路径不能被 HDPath 修改
+	if got := base.String(); got != DefaultHDPath {
+		t.Errorf("基础路径被修改为 %s", got)
+	}
+}
+
+func TestDeriveRange(t *testing.T) {
+	tests := []struct {
+		start, count uint32
+	}{
+		{0, MaxDeriveCount + 1},
+		{hardenedKeyStart - 1, 2},
+		{hardenedKeyStart, 1},
+		{^uint32(0), 2}, // start+count 在 uint32 中回绕
+	}
+	for _, tt := range tests {
+		if _, err := Derive(tt.start, tt.count); !errors.Is(err, util.ErrInvalidArgument) {
+			t.Errorf("Derive(%d, %d) 的错误为 %v, 预期 %v", tt.start, tt.count, err, util.ErrInvalidArgument)
+		}
+	}
+}
@@ -0,0 +1,280 @@
+package account
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"sync"
+	"task1/errs"
+	"task1/util"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// Signer 交易签名器, 所有发送交易的命令都通过 Signer 签名, 不直接接触私钥
+type Signer interface {
+	// Address 返回签名账户地址
+	Address() common.Address
+	// SignTx 使用 chainID 对交易签名, 返回签名后的交易
+	SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+	// String 返回签名器的描述, 用于日志
+	String() string
+}
+
+var (
+	_ Signer = (*KeySigner)(nil)
+	_ Signer = (*KeystoreSigner)(nil)
+	_ Signer = (*ExternalSigner)(nil)
+)
+
+// ErrSignerMismatch 签名结果与请求不一致, 例如外部签名服务修改了交易内容或使用了其他账户签名
+var ErrSignerMismatch = errors.New("签名结果与请求不一致")
+
+// KeySigner 使用进程内的私钥签名, 用于环境文件中的 PRIVATE_KEY 和助记词派生的账户
+type KeySigner struct {
+	key         *ecdsa.PrivateKey
+	address     common.Address
+	description string
+}
+
+// NewKeySigner 使用私钥创建签名器, description 描述私钥来源
+func NewKeySigner(key *ecdsa.PrivateKey, description string) *KeySigner {
+	return &KeySigner{key: key, address: crypto.PubkeyToAddress(key.PublicKey), description: description}
+}
+
+func (s *KeySigner) Address() common.Address { return s.address }
+
+func (s *KeySigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return types.SignTx(tx, types.LatestSignerForChainID(chainID), s.key)
+}
+
+func (s *KeySigner) String() string { return s.description + " " + s.address.Hex() }
+
+// KeystoreSigner 使用 keystore 中的加密账户签名, 每次签名时用密码解密, 私钥不常驻内存
+type KeystoreSigner struct {
+	ks         *keystore.KeyStore
+	account    accounts.Account
+	passphrase string
+}
+
+// NewKeystoreSigner 读取密码并创建 keystore 账户签名器, 创建时校验密码
+func NewKeystoreSigner(ks *keystore.KeyStore, acct accounts.Account) (*KeystoreSigner, error) {
+	passphrase, err := Passphrase(fmt.Sprintf("请输入账户 %s 的密码: ", acct.Address.Hex()), false)
+	if err != nil {
+		return nil, err
+	}
+	// 先解锁再锁定, 在构建交易之前发现密码错误
+	if err := ks.Unlock(acct, passphrase); err != nil {
+		return nil, fmt.Errorf("%w: 解密账户 %s 失败: %w", util.ErrConfig, acct.Address.Hex(), err)
+	}
+	ks.Lock(acct.Address)
+	return &KeystoreSigner{ks: ks, account: acct, passphrase: passphrase}, nil
+}
+
+func (s *KeystoreSigner) Address() common.Address { return s.account.Address }
+
+func (s *KeystoreSigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return s.ks.SignTxWithPassphrase(s.account, s.passphrase, tx, chainID)
+}
+
+func (s *KeystoreSigner) String() string { return "keystore 账户 " + s.account.Address.Hex() }
+
+// ExternalSigner 通过 Clef 兼容的 JSON-RPC 接口 (account_list / account_signTransaction) 请求外部签名服务签名
+// 私钥保存在签名服务中, 签名服务可以按自己的规则审批或拒绝请求
+type ExternalSigner struct {
+	client  *rpc.Client
+	url     string
+	address common.Address
+}
+
+// NewExternalSigner 连接外部签名服务, from 为零地址时使用签名服务中唯一的账户
+func NewExternalSigner(ctx context.Context, url string, from common.Address) (*ExternalSigner, error) {
+	client, err := rpc.DialContext(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("%w: 连接签名服务 %s 失败: %w", util.ErrConfig, url, err)
+	}
+	var addresses []common.Address
+	if err := client.CallContext(ctx, &addresses, "account_list"); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("获取签名服务 %s 的账户失败: %w", url, errs.Classify(err))
+	}
+	switch {
+	case from != (common.Address{}) && !slices.Contains(addresses, from):
+		client.Close()
+		return nil, fmt.Errorf("%w: 签名服务 %s 中没有账户 %s", util.ErrConfig, url, from.Hex())
+	case from == (common.Address{}) && len(addresses) != 1:
+		client.Close()
+		return nil, fmt.Errorf("%w: %w, 签名服务 %s 中有 %d 个账户", util.ErrConfig, ErrAmbiguousAccount, url, len(addresses))
+	case from == (common.Address{}):
+		from = addresses[0]
+	}
+	return &ExternalSigner{client: client, url: url, address: from}, nil
+}
+
+func (s *ExternalSigner) Address() common.Address { return s.address }
+
+// signTransactionResult account_signTransaction 的返回值
+type signTransactionResult struct {
+	Raw hexutil.Bytes      `json:"raw"`
+	Tx  *types.Transaction `json:"tx"`
+}
+
+func (s *ExternalSigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	data := hexutil.Bytes(tx.Data())
+	args := apitypes.SendTxArgs{
+		From:    common.NewMixedcaseAddress(s.address),
+		Gas:     hexutil.Uint64(tx.Gas()),
+		Value:   hexutil.Big(*tx.Value()),
+		Nonce:   hexutil.Uint64(tx.Nonce()),
+		Input:   &data,
+		ChainID: (*hexutil.Big)(chainID),
+	}
+	if tx.To() != nil {
+		to := common.NewMixedcaseAddress(*tx.To())
+		args.To = &to
+	}
+	switch tx.Type() {
+	case types.LegacyTxType:
+		args.GasPrice = (*hexutil.Big)(tx.GasPrice())
+	case types.AccessListTxType:
+		accessList := tx.AccessList()
+		args.GasPrice, args.AccessList = (*hexutil.Big)(tx.GasPrice()), &accessList
+	case types.DynamicFeeTxType:
+		accessList := tx.AccessList()
+		args.MaxFeePerGas, args.MaxPriorityFeePerGas, args.AccessList = (*hexutil.Big)(tx.GasFeeCap()), (*hexutil.Big)(tx.GasTipCap()), &accessList
+	default:
+		return nil, fmt.Errorf("%w: 外部签名服务不支持类型为 %d 的交易", util.ErrInvalidArgument, tx.Type())
+	}
+
+	var result signTransactionResult
+	if err := s.client.CallContext(ctx, &result, "account_signTransaction", args); err != nil {
+		return nil, fmt.Errorf("签名服务 %s 拒绝签名: %w", s.url, errs.Classify(err))
+	}
+	signed := result.Tx
+	if signed == nil && len(result.Raw) > 0 {
+		signed = new(types.Transaction)
+		if err := signed.UnmarshalBinary(result.Raw); err != nil {
+			return nil, fmt.Errorf("解码签名服务返回的交易失败: %w", err)
+		}
+	}
+	if signed == nil {
+		return nil, fmt.Errorf("%w: 签名服务 %s 没有返回交易", ErrSignerMismatch, s.url)
+	}
+	return signed, verifySigned(tx, signed, chainID, s.address)
+}
+
+func (s *ExternalSigner) String() string {
+	return "外部签名服务 " + s.url + " 账户 " + s.address.Hex()
+}
+
+// Close 关闭与签名服务的连接
+func (s *ExternalSigner) Close() { s.client.Close() }
+
+// verifySigned 校验签名后的交易与请求签名的交易内容一致且由 from 签名, 不信任签名服务的返回值
+func verifySigned(tx, signed *types.Transaction, chainID *big.Int, from common.Address) error {
+	signer := types.LatestSignerForChainID(chainID)
+	if signer.Hash(tx) != signer.Hash(signed) {
+		return fmt.Errorf("%w: 签名服务返回的交易内容被修改", ErrSignerMismatch)
+	}
+	sender, err := types.Sender(signer, signed)
+	if err != nil {
+		return fmt.Errorf("%w: 无法恢复签名账户: %w", ErrSignerMismatch, err)
+	}
+	if sender != from {
+		return fmt.Errorf("%w: 交易由 %s 签名, 预期为 %s", ErrSignerMismatch, sender.Hex(), from.Hex())
+	}
+	return nil
+}
+
+// NewUnsignedTransactor 创建只构建交易的合约绑定交易参数, 绑定代码返回未签名的交易且不发送,
+// 由 util.BuildAndSend 使用 Sign 签名后广播
+func NewUnsignedTransactor(ctx context.Context, from common.Address) *bind.TransactOpts {
+	return &bind.TransactOpts{
+		From: from,
+		Signer: func(address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			return tx, nil
+		},
+		Context: ctx,
+		NoSend:  true,
+	}
+}
+
+// Sign 返回 util.BuildAndSend 使用的签名函数, 第一次签名时才通过 LoadSigner 创建签名器,
+// --dry-run 模式下不会签名, 因此不会提示输入密码或请求外部签名服务审批
+func Sign(from common.Address, chainID *big.Int) util.SignFunc {
+	return func(ctx context.Context, tx *types.Transaction) (*types.Transaction, error) {
+		signer, err := LoadSigner(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if signer.Address() != from {
+			return nil, fmt.Errorf("%w: 签名账户 %s 与交易发送方 %s 不一致", util.ErrConfig, signer.Address().Hex(), from.Hex())
+		}
+		signed, err := signer.SignTx(ctx, tx, chainID)
+		if err != nil {
+			return nil, fmt.Errorf("交易签名失败: %w", err)
+		}
+		return signed, nil
+	}
+}
+
+var (
+	signerMu sync.Mutex
+	// cachedSigner LoadSigner 解析的签名器, 同一进程内复用,
+	// 避免一条命令发送多笔交易时重复输入密码或重复连接签名服务
+	cachedSigner Signer
+)
+
+// LoadSigner 按 --signer-url / --account-index / --from 创建签名器
+//
+// 指定了 --signer-url 时使用外部签名服务, 否则按 loadLocalSigner 的顺序选择本地账户;
+// 签名器在进程内缓存, 修改账户配置或调用 CloseSigner 后重新创建
+func LoadSigner(ctx context.Context) (Signer, error) {
+	signerMu.Lock()
+	defer signerMu.Unlock()
+	if cachedSigner != nil {
+		return cachedSigner, nil
+	}
+	var signer Signer
+	var err error
+	switch {
+	case config.SignerURL != "":
+		var from common.Address
+		if f := strings.TrimSpace(config.From); f != "" {
+			if from, err = ParseAddress(f); err != nil {
+				return nil, err
+			}
+		}
+		signer, err = NewExternalSigner(ctx, config.SignerURL, from)
+	default:
+		signer, err = loadLocalSigner()
+	}
+	if err != nil {
+		return nil, err
+	}
+	util.Logf("使用 %s 签名", signer)
+	cachedSigner = signer
+	return signer, nil
+}
+
+// CloseSigner 释放缓存的签名器, 关闭与外部签名服务的连接
+func CloseSigner() {
+	signerMu.Lock()
+	defer signerMu.Unlock()
+	if external, ok := cachedSigner.(*ExternalSigner); ok {
+		external.Close()
+	}
+	cachedSigner = nil
+}
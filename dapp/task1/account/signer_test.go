@@ -0,0 +1,261 @@
+package account
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"net/http/httptest"
+	"task1/util"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// fakeClef 代替 Clef 的本地签名服务, 实现 account_list 和 account_signTransaction
+type fakeClef struct {
+	key    *ecdsa.PrivateKey
+	signer *ecdsa.PrivateKey         // 实际签名使用的私钥, 与 key 不同时模拟用其他账户签名
+	tamper func(*types.DynamicFeeTx) // 签名前修改交易, 模拟签名服务篡改交易内容
+	reject bool                      // 拒绝所有签名请求, 模拟用户在 Clef 中拒绝
+	list   func() []common.Address   // 自定义 account_list 的返回值
+	calls  []apitypes.SendTxArgs     // 收到的签名请求
+}
+
+// List account_list
+func (c *fakeClef) List(ctx context.Context) ([]common.Address, error) {
+	if c.list != nil {
+		return c.list(), nil
+	}
+	return []common.Address{crypto.PubkeyToAddress(c.key.PublicKey)}, nil
+}
+
+// SignTransaction account_signTransaction
+func (c *fakeClef) SignTransaction(ctx context.Context, args apitypes.SendTxArgs) (*signTransactionResult, error) {
+	c.calls = append(c.calls, args)
+	if c.reject {
+		return nil, errors.New("request denied")
+	}
+	tx, err := args.ToTransaction()
+	if err != nil {
+		return nil, err
+	}
+	if c.tamper != nil {
+		inner := &types.DynamicFeeTx{
+			ChainID: tx.ChainId(), Nonce: tx.Nonce(), GasTipCap: tx.GasTipCap(), GasFeeCap: tx.GasFeeCap(),
+			Gas: tx.Gas(), To: tx.To(), Value: tx.Value(), Data: tx.Data(), AccessList: tx.AccessList(),
+		}
+		c.tamper(inner)
+		tx = types.NewTx(inner)
+	}
+	key := c.key
+	if c.signer != nil {
+		key = c.signer
+	}
+	signed, err := types.SignTx(tx, types.LatestSignerForChainID((*big.Int)(args.ChainID)), key)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := signed.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return &signTransactionResult{Raw: raw, Tx: signed}, nil
+}
+
+// startFakeClef 启动签名服务, 返回服务地址
+func startFakeClef(t *testing.T, clef *fakeClef) string {
+	t.Helper()
+	server := rpc.NewServer()
+	if err := server.RegisterName("account", clef); err != nil {
+		t.Fatal(err)
+	}
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(func() {
+		httpServer.Close()
+		server.Stop()
+	})
+	return httpServer.URL
+}
+
+func newKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func testTx() *types.Transaction {
+	to := common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+	return types.NewTx(&types.DynamicFeeTx{
+		ChainID:   big.NewInt(1337),
+		Nonce:     7,
+		GasTipCap: big.NewInt(1e9),
+		GasFeeCap: big.NewInt(30e9),
+		Gas:       21000,
+		To:        &to,
+		Value:     big.NewInt(1e18),
+	})
+}
+
+func TestExternalSignerSignTx(t *testing.T) {
+	ctx := context.Background()
+	clef := &fakeClef{key: newKey(t)}
+	from := crypto.PubkeyToAddress(clef.key.PublicKey)
+	url := startFakeClef(t, clef)
+
+	// 未指定账户时使用签名服务中唯一的账户
+	signer, err := NewExternalSigner(ctx, url, common.Address{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer signer.Close()
+	if signer.Address() != from {
+		t.Fatalf("签名账户为 %s, 预期 %s", signer.Address().Hex(), from.Hex())
+	}
+
+	chainID := big.NewInt(1337)
+	tx := testTx()
+	signed, err := signer.SignTx(ctx, tx, chainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender, err := types.Sender(types.LatestSignerForChainID(chainID), signed)
+	if err != nil || sender != from {
+		t.Fatalf("交易签名账户为 %s, %v, 预期 %s", sender.Hex(), err, from.Hex())
+	}
+	if signed.Nonce() != tx.Nonce() || signed.Value().Cmp(tx.Value()) != 0 || signed.GasFeeCap().Cmp(tx.GasFeeCap()) != 0 {
+		t.Fatalf("签名后的交易内容与请求不一致: %+v", signed)
+	}
+
+	// 请求中的交易字段
+	if len(clef.calls) != 1 {
+		t.Fatalf("签名服务收到 %d 个请求, 预期 1 个", len(clef.calls))
+	}
+	args := clef.calls[0]
+	if args.From.Address() != from || args.To == nil || args.To.Address() != *tx.To() || uint64(args.Nonce) != tx.Nonce() ||
+		args.MaxFeePerGas.ToInt().Cmp(tx.GasFeeCap()) != 0 || args.GasPrice != nil || args.ChainID.ToInt().Cmp(chainID) != 0 {
+		t.Fatalf("签名请求参数错误: %s", args)
+	}
+}
+
+func TestExternalSignerRejectsBadSignature(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name string
+		clef func(key *ecdsa.PrivateKey) *fakeClef
+		err  error
+	}{
+		{
+			name: "篡改交易内容",
+			clef: func(key *ecdsa.PrivateKey) *fakeClef {
+				return &fakeClef{key: key, tamper: func(tx *types.DynamicFeeTx) {
+					tx.To = &common.Address{0xba, 0xd}
+				}}
+			},
+			err: ErrSignerMismatch,
+		},
+		{
+			name: "修改金额",
+			clef: func(key *ecdsa.PrivateKey) *fakeClef {
+				return &fakeClef{key: key, tamper: func(tx *types.DynamicFeeTx) {
+					tx.Value = new(big.Int).Mul(tx.Value, big.NewInt(10))
+				}}
+			},
+			err: ErrSignerMismatch,
+		},
+		{
+			name: "使用其他账户签名",
+			clef: func(key *ecdsa.PrivateKey) *fakeClef {
+				return &fakeClef{key: key, signer: newKey(t)}
+			},
+			err: ErrSignerMismatch,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := newKey(t)
+			signer, err := NewExternalSigner(ctx, startFakeClef(t, tt.clef(key)), crypto.PubkeyToAddress(key.PublicKey))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer signer.Close()
+			if _, err := signer.SignTx(ctx, testTx(), big.NewInt(1337)); !errors.Is(err, tt.err) {
+				t.Fatalf("签名错误为 %v, 预期 %v", err, tt.err)
+			}
+		})
+	}
+
+	// 用户在签名服务中拒绝
+	key := newKey(t)
+	signer, err := NewExternalSigner(ctx, startFakeClef(t, &fakeClef{key: key, reject: true}), common.Address{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer signer.Close()
+	if _, err := signer.SignTx(ctx, testTx(), big.NewInt(1337)); err == nil || errors.Is(err, ErrSignerMismatch) {
+		t.Fatalf("签名服务拒绝时的错误为 %v", err)
+	}
+}
+
+func TestNewExternalSignerAccounts(t *testing.T) {
+	ctx := context.Background()
+	a, b := newKey(t), newKey(t)
+	clef := &fakeClef{key: a, list: func() []common.Address {
+		return []common.Address{crypto.PubkeyToAddress(a.PublicKey), crypto.PubkeyToAddress(b.PublicKey)}
+	}}
+	url := startFakeClef(t, clef)
+
+	// 多个账户时需要指定 --from
+	if _, err := NewExternalSigner(ctx, url, common.Address{}); !errors.Is(err, ErrAmbiguousAccount) {
+		t.Fatalf("多个账户时的错误为 %v, 预期 %v", err, ErrAmbiguousAccount)
+	}
+	if _, err := NewExternalSigner(ctx, url, common.HexToAddress("0x01")); !errors.Is(err, util.ErrConfig) {
+		t.Fatalf("账户不存在时的错误为 %v, 预期 %v", err, util.ErrConfig)
+	}
+	signer, err := NewExternalSigner(ctx, url, crypto.PubkeyToAddress(b.PublicKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer.Close()
+}
+
+func TestLoadSignerCached(t *testing.T) {
+	ctx := context.Background()
+	key := newKey(t)
+	url := startFakeClef(t, &fakeClef{key: key})
+	SetConfig(Config{SignerURL: url})
+	t.Cleanup(func() { SetConfig(Config{}) })
+
+	first, err := LoadSigner(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	address, err := Address(ctx)
+	if err != nil || address != crypto.PubkeyToAddress(key.PublicKey) {
+		t.Fatalf("签名账户为 %s, %v", address.Hex(), err)
+	}
+	second, err := LoadSigner(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != second {
+		t.Fatal("同一进程内应复用签名器")
+	}
+
+	// 修改配置后重新创建
+	SetConfig(Config{SignerURL: url})
+	third, err := LoadSigner(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if third == first {
+		t.Fatal("修改配置后应重新创建签名器")
+	}
+}
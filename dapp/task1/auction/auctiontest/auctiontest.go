@@ -0,0 +1,259 @@
+// Package auctiontest 在 simulated.Backend 上部署 solidity/task3 的拍卖合约, 供 auction 和 task2 的测试使用
+//
+// 合约字节码由 testdata/compile.js 编译, OpenZeppelin 和 Chainlink 依赖使用 testdata/lib 中的测试用替代实现,
+// 只能用于测试. 链上账户同时导入临时 keystore, 通过 Use 切换 task1 的签名账户
+package auctiontest
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"embed"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"task1/account"
+	"task1/auction"
+	"task1/proxy"
+	"task1/util"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+//go:embed testdata/*.bin
+var bins embed.FS
+
+// 预言机价格, 8 位小数, 与 task3 部署脚本中的 MockV3Aggregator 相同
+var (
+	ETHPrice  = big.NewInt(2000e8)
+	USDCPrice = big.NewInt(1e8)
+)
+
+// aggregatorABI MockV3Aggregator 的构造函数
+const aggregatorABI = `[{"inputs":[{"name":"_decimals","type":"uint8"},{"name":"_initialAnswer","type":"int256"}],"stateMutability":"nonpayable","type":"constructor"}]`
+
+const password = "auctiontest"
+
+// Chain 部署了拍卖合约的模拟链
+type Chain struct {
+	Backend  *simulated.Backend
+	Client   *Client
+	Accounts []common.Address // Accounts[0] 部署合约, 是拍卖合约和 NFT 合约的所有者
+	Auction  common.Address
+	NFT      common.Address
+	USDC     common.Address
+
+	keys     []*ecdsa.PrivateKey
+	keystore string
+	password string
+}
+
+// New 创建 n 个有余额的账户 (至少 1 个), 部署 MockUSDC、两个价格预言机以及代理后的 NFTAuction 和 MyNFT 并初始化,
+// 测试结束时关闭模拟链并恢复 task1 的账户、nonce 和等待配置
+func New(t testing.TB, n int) *Chain {
+	t.Helper()
+	c := &Chain{keystore: filepath.Join(t.TempDir(), "keystore")}
+	ks := keystore.NewKeyStore(c.keystore, keystore.LightScryptN, keystore.LightScryptP)
+	alloc := types.GenesisAlloc{}
+	for range max(n, 1) {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ks.ImportECDSA(key, password); err != nil {
+			t.Fatal(err)
+		}
+		address := crypto.PubkeyToAddress(key.PublicKey)
+		alloc[address] = types.Account{Balance: new(big.Int).Mul(big.NewInt(1000), big.NewInt(params.Ether))}
+		c.keys = append(c.keys, key)
+		c.Accounts = append(c.Accounts, address)
+	}
+	c.password = filepath.Join(t.TempDir(), "password")
+	if err := os.WriteFile(c.password, []byte(password+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	c.Backend = simulated.NewBackend(alloc)
+	c.Client = &Client{Client: c.Backend.Client(), backend: c.Backend}
+	wait := util.DefaultWaitConfig()
+	util.SetWaitConfig(util.WaitConfig{Confirmations: 1, Timeout: 10 * time.Second, PollInterval: 10 * time.Millisecond})
+	util.SetNonceStore("")
+	t.Cleanup(func() {
+		account.SetConfig(account.Config{})
+		util.SetWaitConfig(wait)
+		util.SetNonceStore("")
+		c.Backend.Close()
+	})
+
+	c.USDC = c.deploy(t, "MockUSDC", auction.MockUSDCMetaData.ABI)
+	c.NFT = c.deployProxy(t, "MyNFT", auction.MyNFTMetaData.ABI, "__MyNFT_init", "MyNFT", "MNFT")
+	c.Auction = c.deployProxy(t, "NFTAuction", auction.NFTAuctionMetaData.ABI, "__NFTAuction_init", c.USDC)
+	ethFeed := c.deploy(t, "MockV3Aggregator", aggregatorABI, uint8(8), ETHPrice)
+	usdcFeed := c.deploy(t, "MockV3Aggregator", aggregatorABI, uint8(8), USDCPrice)
+
+	contract, err := auction.NewNFTAuction(c.Auction, c.Client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// ConvertPrice.Convert: 0 为 ETH_TO_USD, 1 为 USDC_TO_USD
+	c.send(t, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return contract.AggregatorV3Interface(opts, 0, ethFeed)
+	})
+	c.send(t, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return contract.AggregatorV3Interface(opts, 1, usdcFeed)
+	})
+	return c
+}
+
+// Use 将 task1 的签名账户切换为 Accounts[i]
+func (c *Chain) Use(i int) {
+	account.SetConfig(account.Config{KeystoreDir: c.keystore, From: c.Accounts[i].Hex(), PasswordFile: c.password})
+}
+
+// MintNFT 由合约所有者向 to 铸造 NFT
+func (c *Chain) MintNFT(t testing.TB, to common.Address, tokenID int64) {
+	t.Helper()
+	nft, err := auction.NewMyNFT(c.NFT, c.Client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.send(t, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return nft.MintNFT(opts, to, big.NewInt(tokenID), "ipfs://auctiontest")
+	})
+}
+
+// MintUSDC 向 to 铸造 MockUSDC
+func (c *Chain) MintUSDC(t testing.TB, to common.Address, amount *big.Int) {
+	t.Helper()
+	usdc, err := auction.NewMockUSDC(c.USDC, c.Client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.send(t, func(opts *bind.TransactOpts) (*types.Transaction, error) { return usdc.Mint(opts, to, amount) })
+}
+
+// AdjustTime 将链上时间向后调整 d 并出一个块
+func (c *Chain) AdjustTime(t testing.TB, d time.Duration) {
+	t.Helper()
+	if err := c.Backend.AdjustTime(d); err != nil {
+		t.Fatal(err)
+	}
+	c.Backend.Commit()
+}
+
+// deploy 由 Accounts[0] 部署 testdata 中的合约字节码
+func (c *Chain) deploy(t testing.TB, name, rawABI string, args ...any) common.Address {
+	t.Helper()
+	bin, err := bins.ReadFile("testdata/" + name + ".bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := abi.JSON(strings.NewReader(rawABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var address common.Address
+	c.send(t, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		var tx *types.Transaction
+		address, tx, _, err = bind.DeployContract(opts, parsed, common.FromHex(strings.TrimSpace(string(bin))), c.Client, args...)
+		return tx, err
+	})
+	return address
+}
+
+// deployProxy 与 task3 的 upgrades.deployProxy 相同: 部署实现合约和内置的 ERC1967 代理, 在代理的构造函数中调用初始化方法,
+// 嵌套的 initializer (如 __NFTAuction_init 调用 __ConvertPrice_init) 只能在构造期间执行. 返回代理地址
+func (c *Chain) deployProxy(t testing.TB, name, rawABI, initializer string, args ...any) common.Address {
+	t.Helper()
+	implementation := c.deploy(t, name, rawABI)
+	parsed, err := abi.JSON(strings.NewReader(rawABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := parsed.Pack(initializer, args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	artifact, err := proxy.Artifact("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, err := artifact.Link(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var address common.Address
+	c.send(t, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		var tx *types.Transaction
+		address, tx, _, err = bind.DeployContract(opts, *artifact.ABI, code, c.Client, implementation, data)
+		return tx, err
+	})
+	return address
+}
+
+// Transactor 返回由 Accounts[i] 直接签名的交易选项, 不经过 task1 的账户和 nonce 管理
+// 设置 NoSend 后可以只构建交易, 再通过 Backend.Client() 发送多笔交易后由 Backend.Commit() 打包到同一个区块
+func (c *Chain) Transactor(t testing.TB, i int) *bind.TransactOpts {
+	t.Helper()
+	opts, err := bind.NewKeyedTransactorWithChainID(c.keys[i], params.AllDevChainProtocolChanges.ChainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return opts
+}
+
+// send 由 Accounts[0] 直接签名发送交易, 检查执行成功
+func (c *Chain) send(t testing.TB, send func(opts *bind.TransactOpts) (*types.Transaction, error)) {
+	t.Helper()
+	tx, err := send(c.Transactor(t, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	receipt, err := c.Client.TransactionReceipt(context.Background(), tx.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		t.Fatalf("交易 %s 执行失败", tx.Hash().Hex())
+	}
+}
+
+// Client 实现 util.Client, 每笔交易发送后立即出块
+type Client struct {
+	simulated.Client
+	backend *simulated.Backend
+}
+
+var _ util.Client = (*Client)(nil)
+
+// SendTransaction 发送交易并出块
+func (c *Client) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	if err := c.Client.SendTransaction(ctx, tx); err != nil {
+		return err
+	}
+	c.backend.Commit()
+	return nil
+}
+
+// NetworkID 模拟链的网络 ID 与链 ID 相同
+func (c *Client) NetworkID(ctx context.Context) (*big.Int, error) {
+	return c.ChainID(ctx)
+}
+
+// EstimateGasAtBlock 模拟链只在最新状态上估算
+func (c *Client) EstimateGasAtBlock(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) (uint64, error) {
+	return c.EstimateGas(ctx, msg)
+}
+
+// Close 模拟链由 Chain 关闭
+func (c *Client) Close() {}
@@ -0,0 +1,82 @@
+// 编译测试使用的合约字节码: solidity/task3 的 NFTAuction、MyNFT、MockUSDC 和 Chainlink 的 MockV3Aggregator
+//
+// task3 依赖的 OpenZeppelin 和 Chainlink 合约使用 lib/ 中的测试用替代实现, 外部接口、事件、错误和存储位置与原版相同,
+// 编译得到的 ABI 与 auction/*.abi 一致; 字节码只用于 simulated.Backend 上的测试, 不能用于部署.
+//
+// 用法 (需要 solc 的 npm 包, 版本与 hardhat.config.ts 相同):
+//
+//	npm install --no-save solc@0.8.28
+//	node compile.js
+const fs = require('fs')
+const path = require('path')
+const solc = require('solc')
+
+const task3 = path.resolve(__dirname, '../../../../../solidity/task3/contracts')
+const lib = path.join(__dirname, 'lib')
+const targets = {
+  'contracts/NFTAuction.sol': 'NFTAuction',
+  'contracts/MyNFT.sol': 'MyNFT',
+  'contracts/MockUSDC.sol': 'MockUSDC',
+  '@chainlink/contracts/src/v0.8/shared/mocks/MockV3Aggregator.sol': 'MockV3Aggregator',
+}
+
+// 所有源文件直接放在输入中, 源文件名与 import 路径一致, 不需要导入回调
+const sources = {}
+for (const file of ['NFTAuction.sol', 'ConvertPrice.sol', 'MyNFT.sol', 'MockUSDC.sol']) {
+  sources['contracts/' + file] = { content: fs.readFileSync(path.join(task3, file), 'utf8') }
+}
+const walk = (dir) => {
+  for (const entry of fs.readdirSync(dir, { withFileTypes: true })) {
+    const file = path.join(dir, entry.name)
+    if (entry.isDirectory()) {
+      walk(file)
+    } else if (entry.name.endsWith('.sol')) {
+      sources[path.relative(lib, file).split(path.sep).join('/')] = { content: fs.readFileSync(file, 'utf8') }
+    }
+  }
+}
+walk(lib)
+
+const input = {
+  language: 'Solidity',
+  sources,
+  settings: {
+    // Hardhat 的默认设置: 不开启优化, solc 0.8.28 默认的 cancun
+    evmVersion: 'cancun',
+    optimizer: { enabled: false, runs: 200 },
+    outputSelection: Object.fromEntries(Object.entries(targets).map(([file, name]) => [file, { [name]: ['abi', 'evm.bytecode.object'] }])),
+  },
+}
+
+const output = JSON.parse(solc.compile(JSON.stringify(input)))
+const errors = (output.errors || []).filter((e) => e.severity === 'error')
+if (errors.length > 0) {
+  for (const e of errors) console.error(e.formattedMessage)
+  process.exit(1)
+}
+// 按签名比较 ABI, 与绑定代码使用的 ABI 不一致说明替代实现的接口与原版不同
+const sorted = (v) =>
+  Array.isArray(v) ? v.map(sorted)
+    : v && typeof v === 'object' ? Object.fromEntries(Object.keys(v).filter((k) => k !== 'internalType').sort().map((k) => [k, sorted(v[k])]))
+    : v
+const canonical = (abi) => abi.map((e) => JSON.stringify(sorted(e))).sort()
+let mismatch = false
+for (const [file, name] of Object.entries(targets)) {
+  const contract = output.contracts[file][name]
+  const abiFile = path.join(__dirname, '../..', name + '.abi')
+  if (fs.existsSync(abiFile)) {
+    const want = canonical(JSON.parse(fs.readFileSync(abiFile, 'utf8')))
+    const got = canonical(contract.abi)
+    const missing = want.filter((e) => !got.includes(e))
+    const extra = got.filter((e) => !want.includes(e))
+    if (missing.length > 0 || extra.length > 0) {
+      console.error(`${name} 的 ABI 与 ${name}.abi 不一致\n缺少: ${missing.join('\n')}\n多出: ${extra.join('\n')}`)
+      mismatch = true
+    }
+  }
+  fs.writeFileSync(path.join(__dirname, name + '.bin'), contract.evm.bytecode.object + '\n')
+  console.log(`${name}.bin: ${contract.evm.bytecode.object.length / 2} 字节 (solc ${solc.version()})`)
+}
+if (mismatch) {
+  process.exit(1)
+}
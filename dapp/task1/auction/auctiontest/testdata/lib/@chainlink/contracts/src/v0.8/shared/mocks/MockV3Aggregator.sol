@@ -0,0 +1,47 @@
+// SPDX-License-Identifier: MIT
+// 测试用替代实现: 构造函数和 updateAnswer 与 Chainlink Contracts v1.5 的 MockV3Aggregator 相同
+pragma solidity ^0.8.0;
+
+import {AggregatorV3Interface} from "../interfaces/AggregatorV3Interface.sol";
+
+contract MockV3Aggregator is AggregatorV3Interface {
+    uint256 public constant override version = 0;
+
+    uint8 public override decimals;
+    int256 public latestAnswer;
+    uint256 public latestTimestamp;
+    uint256 public latestRound;
+
+    constructor(uint8 _decimals, int256 _initialAnswer) {
+        decimals = _decimals;
+        updateAnswer(_initialAnswer);
+    }
+
+    function updateAnswer(int256 _answer) public {
+        latestAnswer = _answer;
+        latestTimestamp = block.timestamp;
+        latestRound++;
+    }
+
+    function getRoundData(uint80 _roundId)
+        external
+        view
+        override
+        returns (uint80 roundId, int256 answer, uint256 startedAt, uint256 updatedAt, uint80 answeredInRound)
+    {
+        return (_roundId, latestAnswer, latestTimestamp, latestTimestamp, _roundId);
+    }
+
+    function latestRoundData()
+        external
+        view
+        override
+        returns (uint80 roundId, int256 answer, uint256 startedAt, uint256 updatedAt, uint80 answeredInRound)
+    {
+        return (uint80(latestRound), latestAnswer, latestTimestamp, latestTimestamp, uint80(latestRound));
+    }
+
+    function description() external pure override returns (string memory) {
+        return "v0.8/tests/MockV3Aggregator.sol";
+    }
+}
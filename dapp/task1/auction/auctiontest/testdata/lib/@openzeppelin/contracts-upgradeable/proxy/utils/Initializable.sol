@@ -0,0 +1,62 @@
+// SPDX-License-Identifier: MIT
+// 测试用替代实现: 修饰符、事件、错误和 ERC-7201 存储位置与 OpenZeppelin Contracts Upgradeable v5.4 相同
+pragma solidity ^0.8.20;
+
+abstract contract Initializable {
+    /// @custom:storage-location erc7201:openzeppelin.storage.Initializable
+    struct InitializableStorage {
+        uint64 _initialized;
+        bool _initializing;
+    }
+
+    bytes32 private constant INITIALIZABLE_STORAGE = 0xf0c57e16840df040f15088dc2f81fe391c3923bec73e23a9662efc9c229c6a00;
+
+    error InvalidInitialization();
+    error NotInitializing();
+
+    event Initialized(uint64 version);
+
+    modifier initializer() {
+        InitializableStorage storage $ = _getInitializableStorage();
+        bool isTopLevelCall = !$._initializing;
+        uint64 initialized = $._initialized;
+        bool initialSetup = initialized == 0 && isTopLevelCall;
+        bool construction = initialized == 1 && address(this).code.length == 0;
+        if (!initialSetup && !construction) {
+            revert InvalidInitialization();
+        }
+        $._initialized = 1;
+        if (isTopLevelCall) {
+            $._initializing = true;
+        }
+        _;
+        if (isTopLevelCall) {
+            $._initializing = false;
+            emit Initialized(1);
+        }
+    }
+
+    modifier onlyInitializing() {
+        if (!_getInitializableStorage()._initializing) {
+            revert NotInitializing();
+        }
+        _;
+    }
+
+    function _disableInitializers() internal virtual {
+        InitializableStorage storage $ = _getInitializableStorage();
+        if ($._initializing) {
+            revert InvalidInitialization();
+        }
+        if ($._initialized != type(uint64).max) {
+            $._initialized = type(uint64).max;
+            emit Initialized(type(uint64).max);
+        }
+    }
+
+    function _getInitializableStorage() private pure returns (InitializableStorage storage $) {
+        assembly {
+            $.slot := INITIALIZABLE_STORAGE
+        }
+    }
+}
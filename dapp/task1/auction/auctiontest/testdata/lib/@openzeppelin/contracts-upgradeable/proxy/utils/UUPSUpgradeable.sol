@@ -0,0 +1,50 @@
+// SPDX-License-Identifier: MIT
+// 测试用替代实现: 外部接口、错误和升级检查与 OpenZeppelin Contracts Upgradeable v5.4 相同
+pragma solidity ^0.8.22;
+
+import {IERC1822Proxiable} from "@openzeppelin/contracts/interfaces/draft-IERC1822.sol";
+import {ERC1967Utils} from "@openzeppelin/contracts/proxy/ERC1967/ERC1967Utils.sol";
+import {Initializable} from "./Initializable.sol";
+
+abstract contract UUPSUpgradeable is Initializable, IERC1822Proxiable {
+    address private immutable __self = address(this);
+
+    string public constant UPGRADE_INTERFACE_VERSION = "5.0.0";
+
+    error UUPSUnauthorizedCallContext();
+    error UUPSUnsupportedProxiableUUID(bytes32 slot);
+
+    modifier onlyProxy() {
+        if (address(this) == __self || ERC1967Utils.getImplementation() != __self) {
+            revert UUPSUnauthorizedCallContext();
+        }
+        _;
+    }
+
+    modifier notDelegated() {
+        if (address(this) != __self) {
+            revert UUPSUnauthorizedCallContext();
+        }
+        _;
+    }
+
+    function __UUPSUpgradeable_init() internal onlyInitializing {}
+
+    function proxiableUUID() external view virtual notDelegated returns (bytes32) {
+        return ERC1967Utils.IMPLEMENTATION_SLOT;
+    }
+
+    function upgradeToAndCall(address newImplementation, bytes memory data) public payable virtual onlyProxy {
+        _authorizeUpgrade(newImplementation);
+        try IERC1822Proxiable(newImplementation).proxiableUUID() returns (bytes32 slot) {
+            if (slot != ERC1967Utils.IMPLEMENTATION_SLOT) {
+                revert UUPSUnsupportedProxiableUUID(slot);
+            }
+            ERC1967Utils.upgradeToAndCall(newImplementation, data);
+        } catch {
+            revert ERC1967Utils.ERC1967InvalidImplementation(newImplementation);
+        }
+    }
+
+    function _authorizeUpgrade(address newImplementation) internal virtual;
+}
@@ -0,0 +1,162 @@
+// SPDX-License-Identifier: MIT
+// 测试用替代实现: 外部接口、事件、错误和 ERC-7201 存储位置与 OpenZeppelin Contracts Upgradeable v5.4 相同,
+// 省略了 task3 合约没有使用的内部函数
+pragma solidity ^0.8.20;
+
+import {IERC721} from "@openzeppelin/contracts/token/ERC721/IERC721.sol";
+import {IERC721Receiver} from "@openzeppelin/contracts/token/ERC721/IERC721Receiver.sol";
+import {IERC721Errors} from "@openzeppelin/contracts/interfaces/draft-IERC6093.sol";
+import {IERC165} from "@openzeppelin/contracts/utils/introspection/IERC165.sol";
+import {ContextUpgradeable} from "../../utils/ContextUpgradeable.sol";
+import {ERC165Upgradeable} from "../../utils/introspection/ERC165Upgradeable.sol";
+import {Initializable} from "../../proxy/utils/Initializable.sol";
+
+abstract contract ERC721Upgradeable is Initializable, ContextUpgradeable, ERC165Upgradeable, IERC721, IERC721Errors {
+    /// @custom:storage-location erc7201:openzeppelin.storage.ERC721
+    struct ERC721Storage {
+        string _name;
+        string _symbol;
+        mapping(uint256 tokenId => address) _owners;
+        mapping(address owner => uint256) _balances;
+        mapping(uint256 tokenId => address) _tokenApprovals;
+        mapping(address owner => mapping(address operator => bool)) _operatorApprovals;
+    }
+
+    bytes32 private constant ERC721StorageLocation = 0x80bb2b638cc20bc4d0a60d66940f3ab4a00c1d7b313497ca82fb0b4ab0079300;
+
+    function _getERC721Storage() private pure returns (ERC721Storage storage $) {
+        assembly {
+            $.slot := ERC721StorageLocation
+        }
+    }
+
+    function __ERC721_init(string memory name_, string memory symbol_) internal onlyInitializing {
+        ERC721Storage storage $ = _getERC721Storage();
+        $._name = name_;
+        $._symbol = symbol_;
+    }
+
+    function supportsInterface(bytes4 interfaceId) public view virtual override(ERC165Upgradeable, IERC165) returns (bool) {
+        return interfaceId == type(IERC721).interfaceId || interfaceId == 0x5b5e139f || super.supportsInterface(interfaceId);
+    }
+
+    function balanceOf(address owner) public view virtual returns (uint256) {
+        if (owner == address(0)) {
+            revert ERC721InvalidOwner(address(0));
+        }
+        return _getERC721Storage()._balances[owner];
+    }
+
+    function ownerOf(uint256 tokenId) public view virtual returns (address) {
+        return _requireOwned(tokenId);
+    }
+
+    function name() public view virtual returns (string memory) {
+        return _getERC721Storage()._name;
+    }
+
+    function symbol() public view virtual returns (string memory) {
+        return _getERC721Storage()._symbol;
+    }
+
+    function tokenURI(uint256 tokenId) public view virtual returns (string memory) {
+        _requireOwned(tokenId);
+        return "";
+    }
+
+    function approve(address to, uint256 tokenId) public virtual {
+        address owner = _requireOwned(tokenId);
+        address auth = _msgSender();
+        if (auth != owner && !isApprovedForAll(owner, auth)) {
+            revert ERC721InvalidApprover(auth);
+        }
+        _getERC721Storage()._tokenApprovals[tokenId] = to;
+        emit Approval(owner, to, tokenId);
+    }
+
+    function getApproved(uint256 tokenId) public view virtual returns (address) {
+        _requireOwned(tokenId);
+        return _getERC721Storage()._tokenApprovals[tokenId];
+    }
+
+    function setApprovalForAll(address operator, bool approved) public virtual {
+        if (operator == address(0)) {
+            revert ERC721InvalidOperator(operator);
+        }
+        _getERC721Storage()._operatorApprovals[_msgSender()][operator] = approved;
+        emit ApprovalForAll(_msgSender(), operator, approved);
+    }
+
+    function isApprovedForAll(address owner, address operator) public view virtual returns (bool) {
+        return _getERC721Storage()._operatorApprovals[owner][operator];
+    }
+
+    function transferFrom(address from, address to, uint256 tokenId) public virtual {
+        if (to == address(0)) {
+            revert ERC721InvalidReceiver(address(0));
+        }
+        address owner = _requireOwned(tokenId);
+        address spender = _msgSender();
+        if (spender != owner && !isApprovedForAll(owner, spender) && getApproved(tokenId) != spender) {
+            revert ERC721InsufficientApproval(spender, tokenId);
+        }
+        if (owner != from) {
+            revert ERC721IncorrectOwner(from, tokenId, owner);
+        }
+        _update(to, tokenId);
+    }
+
+    function safeTransferFrom(address from, address to, uint256 tokenId) public {
+        safeTransferFrom(from, to, tokenId, "");
+    }
+
+    function safeTransferFrom(address from, address to, uint256 tokenId, bytes memory data) public virtual {
+        transferFrom(from, to, tokenId);
+        _checkOnERC721Received(from, to, tokenId, data);
+    }
+
+    function _requireOwned(uint256 tokenId) internal view returns (address) {
+        address owner = _getERC721Storage()._owners[tokenId];
+        if (owner == address(0)) {
+            revert ERC721NonexistentToken(tokenId);
+        }
+        return owner;
+    }
+
+    function _safeMint(address to, uint256 tokenId) internal {
+        if (to == address(0)) {
+            revert ERC721InvalidReceiver(address(0));
+        }
+        if (_getERC721Storage()._owners[tokenId] != address(0)) {
+            revert ERC721InvalidSender(address(0));
+        }
+        _update(to, tokenId);
+        _checkOnERC721Received(address(0), to, tokenId, "");
+    }
+
+    // 转移 tokenId 并清除单个授权, 调用方负责检查权限
+    function _update(address to, uint256 tokenId) internal {
+        ERC721Storage storage $ = _getERC721Storage();
+        address from = $._owners[tokenId];
+        if (from != address(0)) {
+            delete $._tokenApprovals[tokenId];
+            $._balances[from] -= 1;
+        }
+        $._balances[to] += 1;
+        $._owners[tokenId] = to;
+        emit Transfer(from, to, tokenId);
+    }
+
+    function _checkOnERC721Received(address from, address to, uint256 tokenId, bytes memory data) private {
+        if (to.code.length == 0) {
+            return;
+        }
+        try IERC721Receiver(to).onERC721Received(_msgSender(), from, tokenId, data) returns (bytes4 retval) {
+            if (retval != IERC721Receiver.onERC721Received.selector) {
+                revert ERC721InvalidReceiver(to);
+            }
+        } catch {
+            revert ERC721InvalidReceiver(to);
+        }
+    }
+}
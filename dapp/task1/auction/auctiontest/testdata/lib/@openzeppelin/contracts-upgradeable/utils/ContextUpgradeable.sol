@@ -0,0 +1,17 @@
+// SPDX-License-Identifier: MIT
+// 测试用替代实现: 与 OpenZeppelin Contracts Upgradeable v5.4 相同
+pragma solidity ^0.8.20;
+
+import {Initializable} from "../proxy/utils/Initializable.sol";
+
+abstract contract ContextUpgradeable is Initializable {
+    function __Context_init() internal onlyInitializing {}
+
+    function _msgSender() internal view virtual returns (address) {
+        return msg.sender;
+    }
+
+    function _msgData() internal view virtual returns (bytes calldata) {
+        return msg.data;
+    }
+}
@@ -0,0 +1,39 @@
+// SPDX-License-Identifier: MIT
+// 测试用替代实现: 修饰符、错误和 ERC-7201 存储位置与 OpenZeppelin Contracts Upgradeable v5.4 相同
+pragma solidity ^0.8.20;
+
+import {Initializable} from "../proxy/utils/Initializable.sol";
+
+abstract contract ReentrancyGuardUpgradeable is Initializable {
+    uint256 private constant NOT_ENTERED = 1;
+    uint256 private constant ENTERED = 2;
+
+    /// @custom:storage-location erc7201:openzeppelin.storage.ReentrancyGuard
+    struct ReentrancyGuardStorage {
+        uint256 _status;
+    }
+
+    bytes32 private constant ReentrancyGuardStorageLocation = 0x9b779b17422d0df92223018b32b4d1fa46e071723d6817e2486d003becc55f00;
+
+    error ReentrancyGuardReentrantCall();
+
+    function __ReentrancyGuard_init() internal onlyInitializing {
+        _getReentrancyGuardStorage()._status = NOT_ENTERED;
+    }
+
+    modifier nonReentrant() {
+        ReentrancyGuardStorage storage $ = _getReentrancyGuardStorage();
+        if ($._status == ENTERED) {
+            revert ReentrancyGuardReentrantCall();
+        }
+        $._status = ENTERED;
+        _;
+        $._status = NOT_ENTERED;
+    }
+
+    function _getReentrancyGuardStorage() private pure returns (ReentrancyGuardStorage storage $) {
+        assembly {
+            $.slot := ReentrancyGuardStorageLocation
+        }
+    }
+}
@@ -0,0 +1,14 @@
+// SPDX-License-Identifier: MIT
+// 测试用替代实现: 与 OpenZeppelin Contracts Upgradeable v5.4 相同
+pragma solidity ^0.8.20;
+
+import {IERC165} from "@openzeppelin/contracts/utils/introspection/IERC165.sol";
+import {Initializable} from "../../proxy/utils/Initializable.sol";
+
+abstract contract ERC165Upgradeable is Initializable, IERC165 {
+    function __ERC165_init() internal onlyInitializing {}
+
+    function supportsInterface(bytes4 interfaceId) public view virtual returns (bool) {
+        return interfaceId == type(IERC165).interfaceId;
+    }
+}
@@ -0,0 +1,7 @@
+// SPDX-License-Identifier: MIT
+// 测试用替代实现: 只包含 UUPSUpgradeable 使用的事件, 与 OpenZeppelin Contracts v5.4 相同
+pragma solidity ^0.8.20;
+
+interface IERC1967 {
+    event Upgraded(address indexed implementation);
+}
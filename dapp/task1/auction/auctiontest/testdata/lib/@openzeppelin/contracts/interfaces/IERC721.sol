@@ -0,0 +1,5 @@
+// SPDX-License-Identifier: MIT
+// 测试用替代实现: 与 OpenZeppelin Contracts v5.4 相同, 重新导出 IERC721
+pragma solidity ^0.8.20;
+
+import {IERC721} from "../token/ERC721/IERC721.sol";
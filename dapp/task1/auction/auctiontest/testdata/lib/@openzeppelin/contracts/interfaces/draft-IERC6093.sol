@@ -0,0 +1,14 @@
+// SPDX-License-Identifier: MIT
+// 测试用替代实现: 只包含 ERC721 的错误, 与 OpenZeppelin Contracts v5.4 相同
+pragma solidity ^0.8.20;
+
+interface IERC721Errors {
+    error ERC721InvalidOwner(address owner);
+    error ERC721NonexistentToken(uint256 tokenId);
+    error ERC721IncorrectOwner(address sender, uint256 tokenId, address owner);
+    error ERC721InvalidSender(address sender);
+    error ERC721InvalidReceiver(address receiver);
+    error ERC721InsufficientApproval(address operator, uint256 tokenId);
+    error ERC721InvalidApprover(address approver);
+    error ERC721InvalidOperator(address operator);
+}
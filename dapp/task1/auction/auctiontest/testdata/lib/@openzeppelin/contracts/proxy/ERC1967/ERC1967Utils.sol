@@ -0,0 +1,35 @@
+// SPDX-License-Identifier: MIT
+// 测试用替代实现: 只包含 UUPSUpgradeable 使用的函数, 行为与 OpenZeppelin Contracts v5.4 相同
+pragma solidity ^0.8.20;
+
+import {IERC1967} from "../../interfaces/IERC1967.sol";
+import {Address} from "../../utils/Address.sol";
+
+library ERC1967Utils {
+    // bytes32(uint256(keccak256("eip1967.proxy.implementation")) - 1)
+    bytes32 internal constant IMPLEMENTATION_SLOT = 0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc;
+
+    error ERC1967InvalidImplementation(address implementation);
+    error ERC1967NonPayable();
+
+    function getImplementation() internal view returns (address implementation) {
+        assembly ("memory-safe") {
+            implementation := sload(IMPLEMENTATION_SLOT)
+        }
+    }
+
+    function upgradeToAndCall(address newImplementation, bytes memory data) internal {
+        if (newImplementation.code.length == 0) {
+            revert ERC1967InvalidImplementation(newImplementation);
+        }
+        assembly ("memory-safe") {
+            sstore(IMPLEMENTATION_SLOT, newImplementation)
+        }
+        emit IERC1967.Upgraded(newImplementation);
+        if (data.length > 0) {
+            Address.functionDelegateCall(newImplementation, data);
+        } else if (msg.value > 0) {
+            revert ERC1967NonPayable();
+        }
+    }
+}
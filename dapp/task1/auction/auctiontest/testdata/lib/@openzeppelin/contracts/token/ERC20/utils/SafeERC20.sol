@@ -0,0 +1,41 @@
+// SPDX-License-Identifier: MIT
+// 测试用替代实现: 只包含 task3 合约使用的函数, 行为与 OpenZeppelin Contracts v5.4 相同
+pragma solidity ^0.8.20;
+
+import {IERC20} from "../IERC20.sol";
+
+library SafeERC20 {
+    error SafeERC20FailedOperation(address token);
+
+    function safeTransfer(IERC20 token, address to, uint256 value) internal {
+        if (!_call(token, abi.encodeCall(token.transfer, (to, value)))) {
+            revert SafeERC20FailedOperation(address(token));
+        }
+    }
+
+    function safeTransferFrom(IERC20 token, address from, address to, uint256 value) internal {
+        if (!_call(token, abi.encodeCall(token.transferFrom, (from, to, value)))) {
+            revert SafeERC20FailedOperation(address(token));
+        }
+    }
+
+    function trySafeTransfer(IERC20 token, address to, uint256 value) internal returns (bool) {
+        return _call(token, abi.encodeCall(token.transfer, (to, value)));
+    }
+
+    function trySafeTransferFrom(IERC20 token, address from, address to, uint256 value) internal returns (bool) {
+        return _call(token, abi.encodeCall(token.transferFrom, (from, to, value)));
+    }
+
+    // 调用成功且没有返回值 (需要有合约代码) 或返回 true 时视为成功
+    function _call(IERC20 token, bytes memory data) private returns (bool) {
+        (bool success, bytes memory result) = address(token).call(data);
+        if (!success) {
+            return false;
+        }
+        if (result.length == 0) {
+            return address(token).code.length > 0;
+        }
+        return abi.decode(result, (bool));
+    }
+}
@@ -0,0 +1,7 @@
+// SPDX-License-Identifier: MIT
+// 测试用替代实现: 接口与 OpenZeppelin Contracts v5.4 相同
+pragma solidity ^0.8.20;
+
+interface IERC721Receiver {
+    function onERC721Received(address operator, address from, uint256 tokenId, bytes calldata data) external returns (bytes4);
+}
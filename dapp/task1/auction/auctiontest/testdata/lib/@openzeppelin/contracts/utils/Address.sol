@@ -0,0 +1,25 @@
+// SPDX-License-Identifier: MIT
+// 测试用替代实现: 只包含 ERC1967Utils 使用的函数, 行为与 OpenZeppelin Contracts v5.4 相同
+pragma solidity ^0.8.20;
+
+import {Errors} from "./Errors.sol";
+
+library Address {
+    error AddressEmptyCode(address target);
+
+    function functionDelegateCall(address target, bytes memory data) internal returns (bytes memory) {
+        (bool success, bytes memory result) = target.delegatecall(data);
+        if (!success) {
+            if (result.length > 0) {
+                assembly ("memory-safe") {
+                    revert(add(result, 0x20), mload(result))
+                }
+            }
+            revert Errors.FailedCall();
+        }
+        if (result.length == 0 && target.code.length == 0) {
+            revert AddressEmptyCode(target);
+        }
+        return result;
+    }
+}
@@ -0,0 +1,7 @@
+// SPDX-License-Identifier: MIT
+// 测试用替代实现: 与 OpenZeppelin Contracts v5.4 相同的错误定义
+pragma solidity ^0.8.20;
+
+library Errors {
+    error FailedCall();
+}
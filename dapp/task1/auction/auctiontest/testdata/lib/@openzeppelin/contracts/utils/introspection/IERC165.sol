@@ -0,0 +1,7 @@
+// SPDX-License-Identifier: MIT
+// 测试用替代实现: 接口与 OpenZeppelin Contracts v5.4 相同
+pragma solidity ^0.8.20;
+
+interface IERC165 {
+    function supportsInterface(bytes4 interfaceId) external view returns (bool);
+}
@@ -0,0 +1,29 @@
+#!/bin/sh
+# 根据 solidity/task3 的 Hardhat 编译产物重新生成绑定代码, 包含 ABI 和部署字节码 (abigen --bin)
+#
+# 用法 (在 auction 目录下, 需要 node 和 abigen, 或执行 go generate ./auction):
+#
+#	sh generate.sh
+#
+# 先在 solidity/task3 中执行 npx hardhat compile, 再从 artifacts/contracts/<Name>.sol/<Name>.json
+# 提取 abi 和 bytecode 写入 <Name>.abi 和 <Name>.bin, 最后用 abigen 生成 <name>.go
+set -e
+
+cd "$(dirname "$0")"
+task3=../../../solidity/task3
+
+(cd "$task3" && npx hardhat compile)
+
+for name in NFTAuction NFTAuctionFactory MyNFT MockUSDC; do
+	artifact="$task3/artifacts/contracts/$name.sol/$name.json"
+	node -e '
+		const fs = require("fs")
+		const [artifact, name] = process.argv.slice(1)
+		const { abi, bytecode } = JSON.parse(fs.readFileSync(artifact, "utf8"))
+		fs.writeFileSync(name + ".abi", JSON.stringify(abi))
+		fs.writeFileSync(name + ".bin", bytecode.replace(/^0x/, "") + "\n")
+	' "$artifact" "$name"
+	out=$(echo "$name" | tr '[:upper:]' '[:lower:]').go
+	abigen --abi "$name.abi" --bin "$name.bin" --pkg auction --type "$name" --out "$out"
+	echo "$out: $name"
+done
@@ -0,0 +1,411 @@
+// Package auction 通过生成的绑定代码操作 solidity/task3 的 NFT 拍卖合约
+//
+// 绑定代码由 generate.sh 根据 solidity/task3 的编译产物用 abigen --abi --bin 生成 (nftauction.go、nftauctionfactory.go、mynft.go、mockusdc.go),
+// 合约地址从部署记录中按合约名查找, 也可以直接指定地址
+package auction
+
+//go:generate sh generate.sh
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"task1/account"
+	"task1/contracts"
+	"task1/decoder"
+	"task1/errs"
+	"task1/transactions"
+	"task1/util"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func init() {
+	// 注册拍卖合约的完整 ABI, 替换内置的事件和错误定义
+	// MyNFT 和 MockUSDC 的事件与标准 ERC721 / ERC20 相同, 由签名数据库解码
+	if contractABI, err := NFTAuctionMetaData.GetAbi(); err == nil {
+		decoder.Register(AuctionName, contractABI)
+	}
+	if contractABI, err := NFTAuctionFactoryMetaData.GetAbi(); err == nil {
+		decoder.Register(FactoryName, contractABI)
+	}
+}
+
+// 部署记录中的合约名
+const (
+	AuctionName = "NFTAuction"
+	FactoryName = "NFTAuctionFactory"
+	NFTName     = "MyNFT"
+	USDCName    = "MockUSDC"
+)
+
+// 拍卖状态
+const (
+	StatusActive  = "active"  // 进行中, 可以出价
+	StatusExpired = "expired" // 已到结束时间, 等待调用 endAuction
+	StatusEnded   = "ended"   // 已结束或已取消
+)
+
+// Statuses 返回所有拍卖状态, 用于命令行参数校验
+func Statuses() []string {
+	return []string{StatusActive, StatusExpired, StatusEnded}
+}
+
+// Auction 拍卖信息, 金额为出价代币的最小单位 (ETH 为 wei)
+type Auction struct {
+	ID            *big.Int       `json:"id"`
+	TokenID       *big.Int       `json:"tokenId"`
+	NFTContract   common.Address `json:"nftContract"`
+	Seller        common.Address `json:"seller"`
+	HighestBidder common.Address `json:"highestBidder"`
+	BidToken      common.Address `json:"bidToken"` // 零地址表示 ETH
+	StartingPrice *big.Int       `json:"startingPrice"`
+	HighestBid    *big.Int       `json:"highestBid"` // 没有出价时等于起始价格
+	StartTime     time.Time      `json:"startTime"`
+	EndTime       time.Time      `json:"endTime"`
+	Ended         bool           `json:"ended"`
+	Status        string         `json:"status"` // 按最新区块时间计算
+}
+
+// Fees 手续费配置和累计未提取的手续费
+type Fees struct {
+	Owner         common.Address `json:"owner"`
+	FeeRate       *big.Int       `json:"feeRate"`     // 以 FeeRateBase 为基数, 250 表示 2.5%
+	FeeRateBase   *big.Int       `json:"feeRateBase"` // 10000 = 100%
+	TotalFeesETH  *big.Int       `json:"totalFeesETH"`
+	TotalFeesUSDC *big.Int       `json:"totalFeesUSDC"`
+	USDC          common.Address `json:"usdc"`
+}
+
+// Service 拍卖合约服务
+type Service struct {
+	Address common.Address
+	client  util.Client
+	auction *NFTAuction
+}
+
+// NewService 按合约地址或部署记录中的合约名加载拍卖合约
+// client 由调用方负责关闭
+func NewService(ctx context.Context, client util.Client, deployments *contracts.Deployments, target string) (*Service, error) {
+	address, _, err := deployments.Resolve(ctx, client, target)
+	if err != nil {
+		return nil, err
+	}
+	auction, err := NewNFTAuction(address, client)
+	if err != nil {
+		return nil, err
+	}
+	return &Service{Address: address, client: client, auction: auction}, nil
+}
+
+// USDC 返回拍卖合约配置的 USDC 代币地址和小数位数
+func (s *Service) USDC(ctx context.Context) (common.Address, uint8, error) {
+	opts := &bind.CallOpts{Context: ctx}
+	token, err := s.auction.UsdcTokenAddress(opts)
+	if err != nil {
+		return common.Address{}, 0, fmt.Errorf("查询 USDC 地址失败: %w", errs.Classify(err))
+	}
+	if token == (common.Address{}) {
+		return common.Address{}, 0, fmt.Errorf("%w: 拍卖合约没有配置 USDC 地址", util.ErrConfig)
+	}
+	usdc, err := NewMockUSDC(token, s.client)
+	if err != nil {
+		return common.Address{}, 0, err
+	}
+	decimals, err := usdc.Decimals(opts)
+	if err != nil {
+		return common.Address{}, 0, fmt.Errorf("查询 USDC 小数位数失败: %w", errs.Classify(err))
+	}
+	return token, decimals, nil
+}
+
+// Show 查询拍卖信息, 拍卖不存在时返回 util.ErrInvalidArgument
+func (s *Service) Show(ctx context.Context, id *big.Int) (*Auction, error) {
+	now, err := s.chainTime(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.show(ctx, id, now)
+}
+
+// List 按 ID 顺序列出所有拍卖, status 不为空时只返回该状态的拍卖
+func (s *Service) List(ctx context.Context, status string) ([]*Auction, error) {
+	now, err := s.chainTime(ctx)
+	if err != nil {
+		return nil, err
+	}
+	next, err := s.auction.NextAuctionId(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return nil, fmt.Errorf("查询拍卖数量失败: %w", errs.Classify(err))
+	}
+	var auctions []*Auction
+	for id := new(big.Int); id.Cmp(next) < 0; id = new(big.Int).Add(id, big.NewInt(1)) {
+		auction, err := s.show(ctx, id, now)
+		if err != nil {
+			return nil, err
+		}
+		if status == "" || auction.Status == status {
+			auctions = append(auctions, auction)
+		}
+	}
+	return auctions, nil
+}
+
+// show 查询拍卖信息, now 为计算状态使用的区块时间
+func (s *Service) show(ctx context.Context, id *big.Int, now time.Time) (*Auction, error) {
+	info, err := s.auction.Auctions(&bind.CallOpts{Context: ctx}, id)
+	if err != nil {
+		return nil, fmt.Errorf("查询拍卖 %s 失败: %w", id, errs.Classify(err))
+	}
+	// 映射中不存在的拍卖所有字段都是零值
+	if info.Seller == (common.Address{}) {
+		return nil, fmt.Errorf("%w: 拍卖 %s 不存在", util.ErrInvalidArgument, id)
+	}
+	auction := &Auction{
+		ID:            id,
+		TokenID:       info.TokenId,
+		NFTContract:   info.NftContract,
+		Seller:        info.Seller,
+		HighestBidder: info.HighestBidder,
+		BidToken:      info.BidToken,
+		StartingPrice: info.StartingPrice,
+		HighestBid:    info.HighestBid,
+		StartTime:     time.Unix(int64(info.StartTime), 0).UTC(),
+		EndTime:       time.Unix(int64(info.EndTime), 0).UTC(),
+		Ended:         info.Ended,
+	}
+	switch {
+	case auction.Ended:
+		auction.Status = StatusEnded
+	// 合约在 endTime 之后才允许结束, endTime 当秒仍可出价
+	case now.After(auction.EndTime):
+		auction.Status = StatusExpired
+	default:
+		auction.Status = StatusActive
+	}
+	return auction, nil
+}
+
+// Fees 查询手续费配置和累计未提取的手续费
+func (s *Service) Fees(ctx context.Context) (*Fees, error) {
+	opts := &bind.CallOpts{Context: ctx}
+	var fees Fees
+	var err error
+	if fees.Owner, err = s.auction.Owner(opts); err != nil {
+		return nil, fmt.Errorf("查询合约所有者失败: %w", errs.Classify(err))
+	}
+	if fees.FeeRate, err = s.auction.FeeRate(opts); err != nil {
+		return nil, fmt.Errorf("查询手续费比例失败: %w", errs.Classify(err))
+	}
+	if fees.FeeRateBase, err = s.auction.FEERATEBASE(opts); err != nil {
+		return nil, fmt.Errorf("查询手续费比例基数失败: %w", errs.Classify(err))
+	}
+	if fees.TotalFeesETH, err = s.auction.TotalFeesETH(opts); err != nil {
+		return nil, fmt.Errorf("查询 ETH 手续费失败: %w", errs.Classify(err))
+	}
+	if fees.TotalFeesUSDC, err = s.auction.TotalFeesUSDC(opts); err != nil {
+		return nil, fmt.Errorf("查询 USDC 手续费失败: %w", errs.Classify(err))
+	}
+	if fees.USDC, err = s.auction.UsdcTokenAddress(opts); err != nil {
+		return nil, fmt.Errorf("查询 USDC 地址失败: %w", errs.Classify(err))
+	}
+	return &fees, nil
+}
+
+// Create 创建拍卖, 返回交易收据和新拍卖的 ID
+// 拍卖合约没有转移该 NFT 的授权时先发送 approve 交易 (--dry-run 模式下只模拟 approve 交易); usdcPrice 为 true 时起始价格按 USDC 计价
+func (s *Service) Create(ctx context.Context, nftContract common.Address, tokenID, startingPrice *big.Int, usdcPrice bool, duration time.Duration) (*types.Receipt, *big.Int, error) {
+	from, err := account.Address(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	nft, err := NewMyNFT(nftContract, s.client)
+	if err != nil {
+		return nil, nil, err
+	}
+	opts := &bind.CallOpts{From: from, Context: ctx}
+	owner, err := nft.OwnerOf(opts, tokenID)
+	if err != nil {
+		return nil, nil, util.RevertError(fmt.Sprintf("查询 NFT #%s 的所有者失败", tokenID), err)
+	}
+	if owner != from {
+		return nil, nil, fmt.Errorf("%w: NFT #%s 的所有者是 %s, 不是当前账户 %s", util.ErrInvalidArgument, tokenID, owner.Hex(), from.Hex())
+	}
+	approved, err := nft.GetApproved(opts, tokenID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("查询 NFT 授权失败: %w", errs.Classify(err))
+	}
+	approvedForAll, err := nft.IsApprovedForAll(opts, from, s.Address)
+	if err != nil {
+		return nil, nil, fmt.Errorf("查询 NFT 授权失败: %w", errs.Classify(err))
+	}
+	if approved != s.Address && !approvedForAll {
+		util.Logf("授权拍卖合约 %s 转移 NFT #%s", s.Address.Hex(), tokenID)
+		_, err := s.transact(ctx, "授权 NFT", nil, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+			return nft.Approve(opts, s.Address, tokenID)
+		})
+		if errors.Is(err, util.ErrDryRun) {
+			// 授权交易没有广播, 创建拍卖在当前状态下必然回滚, 无法模拟
+			util.Logf("--dry-run: 创建拍卖需要先确认授权 NFT 的交易, 跳过创建拍卖的模拟执行")
+			return nil, nil, err
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	util.Logf("开始创建 NFT #%s 的拍卖", tokenID)
+	receipt, err := s.transact(ctx, "创建拍卖", nil, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return s.auction.CreateAuction(opts, nftContract, tokenID, startingPrice, usdcPrice, big.NewInt(int64(duration/time.Second)))
+	})
+	if err != nil {
+		return receipt, nil, err
+	}
+	for _, log := range receipt.Logs {
+		if log.Address != s.Address {
+			continue
+		}
+		if created, err := s.auction.ParseAuctionCreated(*log); err == nil {
+			return receipt, created.AuctionId, nil
+		}
+	}
+	return receipt, nil, fmt.Errorf("交易收据中没有 AuctionCreated 事件")
+}
+
+// Bid 对拍卖出价, amount 为出价代币的最小单位
+// 使用 USDC 出价时先检查余额, 授权额度不足时先发送 approve 交易;
+// --dry-run 模式下授权额度不足时只模拟 approve 交易, 出价依赖授权额度, 不模拟
+func (s *Service) Bid(ctx context.Context, id, amount *big.Int, usdc bool) (*types.Receipt, error) {
+	if !usdc {
+		util.Logf("开始使用 ETH 对拍卖 %s 出价", id)
+		return s.transact(ctx, "出价", amount, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+			return s.auction.PlaceBid(opts, id, new(big.Int), common.Address{})
+		})
+	}
+
+	from, err := account.Address(ctx)
+	if err != nil {
+		return nil, err
+	}
+	opts := &bind.CallOpts{From: from, Context: ctx}
+	usdcAddress, err := s.auction.UsdcTokenAddress(opts)
+	if err != nil {
+		return nil, fmt.Errorf("查询 USDC 地址失败: %w", errs.Classify(err))
+	}
+	token, err := NewMockUSDC(usdcAddress, s.client)
+	if err != nil {
+		return nil, err
+	}
+	balance, err := token.BalanceOf(opts, from)
+	if err != nil {
+		return nil, fmt.Errorf("查询 USDC 余额失败: %w", errs.Classify(err))
+	}
+	if balance.Cmp(amount) < 0 {
+		return nil, fmt.Errorf("%w: USDC 余额不足: 余额 %s, 出价 %s", util.ErrInvalidArgument, balance, amount)
+	}
+	allowance, err := token.Allowance(opts, from, s.Address)
+	if err != nil {
+		return nil, fmt.Errorf("查询 USDC 授权额度失败: %w", errs.Classify(err))
+	}
+	if allowance.Cmp(amount) < 0 {
+		// 只授权本次出价的金额, 不使用无限授权
+		util.Logf("USDC 授权额度 %s 不足, 授权拍卖合约 %s 使用 %s", allowance, s.Address.Hex(), amount)
+		_, err := s.transact(ctx, "授权 USDC", nil, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+			return token.Approve(opts, s.Address, amount)
+		})
+		if errors.Is(err, util.ErrDryRun) {
+			// 授权交易没有广播, 按当前授权额度模拟出价必然回滚
+			util.Logf("--dry-run: 出价需要先确认授权 USDC 的交易 (当前授权额度 %s, 需要 %s), 跳过出价的模拟执行", allowance, amount)
+			return nil, err
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	util.Logf("开始使用 USDC 对拍卖 %s 出价", id)
+	return s.transact(ctx, "出价", nil, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return s.auction.PlaceBid(opts, id, amount, usdcAddress)
+	})
+}
+
+// End 结束拍卖, 将 NFT 转给最高出价者, 扣除手续费后将出价转给卖家
+func (s *Service) End(ctx context.Context, id *big.Int) (*types.Receipt, error) {
+	util.Logf("开始结束拍卖 %s", id)
+	return s.transact(ctx, "结束拍卖", nil, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return s.auction.EndAuction(opts, id)
+	})
+}
+
+// Cancel 取消拍卖, 只有卖家可以取消, 退还最高出价并将 NFT 转回卖家
+func (s *Service) Cancel(ctx context.Context, id *big.Int) (*types.Receipt, error) {
+	util.Logf("开始取消拍卖 %s", id)
+	return s.transact(ctx, "取消拍卖", nil, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return s.auction.CancelAuction(opts, id)
+	})
+}
+
+// SetFeeRate 设置手续费比例, 以 FEE_RATE_BASE (10000) 为基数, 最高 10%, 只有合约所有者可以调用
+func (s *Service) SetFeeRate(ctx context.Context, feeRate *big.Int) (*types.Receipt, error) {
+	util.Logf("开始设置手续费比例为 %s", feeRate)
+	return s.transact(ctx, "设置手续费比例", nil, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return s.auction.SetFeeRate(opts, feeRate)
+	})
+}
+
+// WithdrawFees 将累计的 ETH 和 USDC 手续费提取到合约所有者, 只有合约所有者可以调用
+func (s *Service) WithdrawFees(ctx context.Context) (*types.Receipt, error) {
+	util.Logf("开始提取手续费")
+	return s.transact(ctx, "提取手续费", nil, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return s.auction.WithdrawFees(opts)
+	})
+}
+
+// transact 通过绑定代码签名并发送交易, 等待确认并检查执行结果, value 为附带的金额 (wei)
+// 交易会回滚时绑定代码估算 gas 失败, 返回解码后的回滚原因
+func (s *Service) transact(ctx context.Context, action string, value *big.Int, send func(opts *bind.TransactOpts) (*types.Transaction, error)) (*types.Receipt, error) {
+	from, err := account.Address(ctx)
+	if err != nil {
+		return nil, err
+	}
+	chainID, err := s.client.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("获取链ID失败: %w", errs.Classify(err))
+	}
+	// 绑定代码只构建交易, 由 util.BuildAndSend 负责签名、广播和重试
+	opts := account.NewUnsignedTransactor(ctx, from)
+	opts.Value = value
+	tx, err := util.BuildAndSend(ctx, s.client, from, func(ctx context.Context, nonce uint64) (*types.Transaction, error) {
+		opts.Nonce = new(big.Int).SetUint64(nonce)
+		fees, err := transactions.SuggestFees(ctx, s.client, transactions.CurrentFeeOptions())
+		if err != nil {
+			return nil, err
+		}
+		fees.Apply(opts)
+		tx, err := send(opts)
+		if err != nil {
+			return nil, util.RevertError("估算 gas 失败", err)
+		}
+		return tx, nil
+	}, account.Sign(from, chainID))
+	if err != nil {
+		return nil, fmt.Errorf("%s失败: %w", action, err)
+	}
+	receipt, err := util.WaitReceipt(ctx, s.client, tx.Hash())
+	if err != nil {
+		return nil, err
+	}
+	return receipt, util.DiagnoseReceipt(ctx, s.client, receipt)
+}
+
+// chainTime 返回最新区块的时间, 拍卖状态按链上时间而不是本机时间计算
+func (s *Service) chainTime(ctx context.Context) (time.Time, error) {
+	header, err := s.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("获取最新区块失败: %w", errs.Classify(err))
+	}
+	return time.Unix(int64(header.Time), 0).UTC(), nil
+}
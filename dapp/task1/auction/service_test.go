@@ -0,0 +1,377 @@
+package auction_test
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math/big"
+	"path/filepath"
+	"strings"
+	"task1/auction"
+	"task1/auction/auctiontest"
+	"task1/contracts"
+	"task1/util"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// 账户: 0 合约所有者, 1 卖家, 2、3 出价者
+const (
+	owner = iota
+	seller
+	bidder
+	bidder2
+)
+
+func ether(n int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(n), big.NewInt(params.Ether))
+}
+
+// usdc MockUSDC 使用默认的 18 位小数
+func usdc(n int64) *big.Int {
+	return ether(n)
+}
+
+func newService(t *testing.T) (*auctiontest.Chain, *auction.Service) {
+	t.Helper()
+	chain := auctiontest.New(t, 4)
+	deployments := contracts.OpenDeployments(filepath.Join(t.TempDir(), "deployments.json"))
+	service, err := auction.NewService(context.Background(), chain.Client, deployments, chain.Auction.Hex())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return chain, service
+}
+
+// createAuction 卖家铸造 NFT 并创建拍卖, 返回拍卖 ID
+func createAuction(t *testing.T, chain *auctiontest.Chain, service *auction.Service, tokenID int64, startingPrice *big.Int, usdcPrice bool) *big.Int {
+	t.Helper()
+	chain.MintNFT(t, chain.Accounts[seller], tokenID)
+	chain.Use(seller)
+	_, id, err := service.Create(context.Background(), chain.NFT, big.NewInt(tokenID), startingPrice, usdcPrice, 2*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+func show(t *testing.T, service *auction.Service, id *big.Int) *auction.Auction {
+	t.Helper()
+	a, err := service.Show(context.Background(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func balance(t *testing.T, chain *auctiontest.Chain, address common.Address) *big.Int {
+	t.Helper()
+	b, err := chain.Client.BalanceAt(context.Background(), address, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func usdcBalance(t *testing.T, chain *auctiontest.Chain, address common.Address) *big.Int {
+	t.Helper()
+	token, err := auction.NewMockUSDC(chain.USDC, chain.Client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := token.BalanceOf(&bind.CallOpts{}, address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func nftOwner(t *testing.T, chain *auctiontest.Chain, tokenID int64) common.Address {
+	t.Helper()
+	nft, err := auction.NewMyNFT(chain.NFT, chain.Client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	o, err := nft.OwnerOf(&bind.CallOpts{}, big.NewInt(tokenID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return o
+}
+
+// gasCost 返回交易收据的手续费
+func gasCost(receipt *types.Receipt) *big.Int {
+	return new(big.Int).Mul(new(big.Int).SetUint64(receipt.GasUsed), receipt.EffectiveGasPrice)
+}
+
+func TestServiceAuction(t *testing.T) {
+	ctx := context.Background()
+	chain, service := newService(t)
+
+	id := createAuction(t, chain, service, 1, ether(1), false)
+	if id.Sign() != 0 {
+		t.Fatalf("第一个拍卖的 ID 为 %s", id)
+	}
+	if got := nftOwner(t, chain, 1); got != service.Address {
+		t.Fatalf("创建拍卖后 NFT 的所有者为 %s, 预期拍卖合约", got.Hex())
+	}
+	a := show(t, service, id)
+	if a.Status != auction.StatusActive || a.Seller != chain.Accounts[seller] || a.BidToken != (common.Address{}) || a.StartingPrice.Cmp(ether(1)) != 0 {
+		t.Fatalf("新拍卖的信息错误: %+v", a)
+	}
+
+	// 低于起始价格的出价在发送前报错, 返回解码后的回滚原因
+	chain.Use(bidder)
+	if _, err := service.Bid(ctx, id, big.NewInt(1), false); err == nil || !strings.Contains(err.Error(), "BidTooLow") {
+		t.Fatalf("出价过低的错误为 %v", err)
+	}
+
+	// ETH 出价
+	before := balance(t, chain, chain.Accounts[bidder])
+	receipt, err := service.Bid(ctx, id, ether(2), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	spent := new(big.Int).Add(ether(2), gasCost(receipt))
+	if got := new(big.Int).Sub(before, balance(t, chain, chain.Accounts[bidder])); got.Cmp(spent) != 0 {
+		t.Fatalf("ETH 出价花费 %s, 预期 %s", got, spent)
+	}
+
+	// USDC 出价: 授权额度不足时先授权, 按预言机价格比较, 2 ETH = 4000 USD
+	chain.MintUSDC(t, chain.Accounts[bidder2], usdc(5000))
+	chain.Use(bidder2)
+	if _, err := service.Bid(ctx, id, usdc(3000), true); err == nil || !strings.Contains(err.Error(), "BidTooLow") {
+		t.Fatalf("USDC 出价过低的错误为 %v", err)
+	}
+	if _, err := service.Bid(ctx, id, usdc(6000), true); err == nil {
+		t.Fatal("USDC 余额不足时应报错")
+	}
+	if _, err := service.Bid(ctx, id, usdc(5000), true); err != nil {
+		t.Fatal(err)
+	}
+	// 之前的 ETH 出价被退还
+	refunded := new(big.Int).Sub(before, gasCost(receipt))
+	if got := balance(t, chain, chain.Accounts[bidder]); got.Cmp(refunded) != 0 {
+		t.Fatalf("被超过的出价者余额为 %s, 预期 %s", got, refunded)
+	}
+	a = show(t, service, id)
+	if a.HighestBidder != chain.Accounts[bidder2] || a.BidToken != chain.USDC || a.HighestBid.Cmp(usdc(5000)) != 0 {
+		t.Fatalf("USDC 出价后的拍卖信息错误: %+v", a)
+	}
+
+	// 到期前不能结束
+	chain.Use(seller)
+	if _, err := service.End(ctx, id); err == nil || !strings.Contains(err.Error(), "AuctionNotEnded") {
+		t.Fatalf("到期前结束拍卖的错误为 %v", err)
+	}
+	chain.AdjustTime(t, 3*time.Hour)
+	if a := show(t, service, id); a.Status != auction.StatusExpired {
+		t.Fatalf("到期后的状态为 %s", a.Status)
+	}
+	if _, err := service.End(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+
+	// NFT 转给最高出价者, 卖家收到扣除 2.5% 手续费后的 USDC
+	if got := nftOwner(t, chain, 1); got != chain.Accounts[bidder2] {
+		t.Fatalf("结束后 NFT 的所有者为 %s", got.Hex())
+	}
+	if got := usdcBalance(t, chain, chain.Accounts[seller]); got.Cmp(usdc(4875)) != 0 {
+		t.Fatalf("卖家收到 %s USDC, 预期 %s", got, usdc(4875))
+	}
+	fees, err := service.Fees(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fees.TotalFeesUSDC.Cmp(usdc(125)) != 0 || fees.TotalFeesETH.Sign() != 0 {
+		t.Fatalf("累计手续费错误: %+v", fees)
+	}
+	ended, err := service.List(ctx, auction.StatusEnded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ended) != 1 || ended[0].ID.Cmp(id) != 0 {
+		t.Fatalf("已结束的拍卖为 %+v", ended)
+	}
+}
+
+func TestServiceCancel(t *testing.T) {
+	ctx := context.Background()
+	chain, service := newService(t)
+	id := createAuction(t, chain, service, 7, ether(1), false)
+
+	chain.Use(bidder)
+	before := balance(t, chain, chain.Accounts[bidder])
+	receipt, err := service.Bid(ctx, id, ether(2), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 只有卖家可以取消
+	if _, err := service.Cancel(ctx, id); err == nil || !strings.Contains(err.Error(), "UnauthorizedSeller") {
+		t.Fatalf("其他账户取消拍卖的错误为 %v", err)
+	}
+	chain.Use(seller)
+	if _, err := service.Cancel(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+
+	// 退还出价, NFT 转回卖家
+	refunded := new(big.Int).Sub(before, gasCost(receipt))
+	if got := balance(t, chain, chain.Accounts[bidder]); got.Cmp(refunded) != 0 {
+		t.Fatalf("取消后出价者余额为 %s, 预期 %s", got, refunded)
+	}
+	if got := nftOwner(t, chain, 7); got != chain.Accounts[seller] {
+		t.Fatalf("取消后 NFT 的所有者为 %s", got.Hex())
+	}
+	if a := show(t, service, id); a.Status != auction.StatusEnded || !a.Ended {
+		t.Fatalf("取消后的拍卖信息错误: %+v", a)
+	}
+	if _, err := service.Cancel(ctx, id); err == nil || !strings.Contains(err.Error(), "AuctionAlreadyEnded") {
+		t.Fatalf("重复取消的错误为 %v", err)
+	}
+}
+
+func TestServiceFees(t *testing.T) {
+	ctx := context.Background()
+	chain, service := newService(t)
+
+	// 只有合约所有者可以设置手续费比例, 最高 10%
+	chain.Use(seller)
+	if _, err := service.SetFeeRate(ctx, big.NewInt(500)); err == nil || !strings.Contains(err.Error(), "OwnableUnauthorizedAccount") {
+		t.Fatalf("其他账户设置手续费比例的错误为 %v", err)
+	}
+	chain.Use(owner)
+	if _, err := service.SetFeeRate(ctx, big.NewInt(1001)); err == nil || !strings.Contains(err.Error(), "InvalidFeeRate") {
+		t.Fatalf("手续费比例超过上限的错误为 %v", err)
+	}
+	if _, err := service.SetFeeRate(ctx, big.NewInt(500)); err != nil {
+		t.Fatal(err)
+	}
+	fees, err := service.Fees(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fees.FeeRate.Int64() != 500 || fees.FeeRateBase.Int64() != 10000 || fees.Owner != chain.Accounts[owner] || fees.USDC != chain.USDC {
+		t.Fatalf("手续费配置错误: %+v", fees)
+	}
+	if _, err := service.WithdrawFees(ctx); err == nil || !strings.Contains(err.Error(), "NoFeesToWithdraw") {
+		t.Fatalf("没有手续费时提取的错误为 %v", err)
+	}
+
+	// ETH 拍卖结束后按 5% 收取手续费
+	id := createAuction(t, chain, service, 3, ether(1), false)
+	chain.Use(bidder)
+	if _, err := service.Bid(ctx, id, ether(4), false); err != nil {
+		t.Fatal(err)
+	}
+	chain.AdjustTime(t, 3*time.Hour)
+	sellerBefore := balance(t, chain, chain.Accounts[seller])
+	if _, err := service.End(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+	if got := new(big.Int).Sub(balance(t, chain, chain.Accounts[seller]), sellerBefore); got.Cmp(big.NewInt(3.8e18)) != 0 {
+		t.Fatalf("卖家收到 %s wei, 预期 3.8 ETH", got)
+	}
+
+	chain.Use(bidder)
+	if _, err := service.WithdrawFees(ctx); err == nil || !strings.Contains(err.Error(), "OwnableUnauthorizedAccount") {
+		t.Fatalf("其他账户提取手续费的错误为 %v", err)
+	}
+	chain.Use(owner)
+	before := balance(t, chain, chain.Accounts[owner])
+	receipt, err := service.WithdrawFees(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := new(big.Int).Sub(big.NewInt(0.2e18), gasCost(receipt))
+	if got := new(big.Int).Sub(balance(t, chain, chain.Accounts[owner]), before); got.Cmp(want) != 0 {
+		t.Fatalf("所有者提取到 %s wei, 预期 %s", got, want)
+	}
+	if fees, err := service.Fees(ctx); err != nil || fees.TotalFeesETH.Sign() != 0 {
+		t.Fatalf("提取后的累计手续费为 %+v, %v", fees, err)
+	}
+}
+
+func TestServiceDryRun(t *testing.T) {
+	ctx := context.Background()
+	chain, service := newService(t)
+	id := createAuction(t, chain, service, 5, ether(1), false)
+	chain.MintNFT(t, chain.Accounts[seller], 6)
+	chain.MintUSDC(t, chain.Accounts[bidder2], usdc(5000))
+
+	var logs strings.Builder
+	util.SetLogger(log.New(&logs, "", 0))
+	util.SetDryRun(true)
+	t.Cleanup(func() {
+		util.SetLogger(nil)
+		util.SetDryRun(false)
+	})
+	nonce := func(i int) uint64 {
+		t.Helper()
+		n, err := chain.Client.PendingNonceAt(ctx, chain.Accounts[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+
+	// 授权额度不足: 只模拟授权交易, 明确说明出价没有模拟
+	chain.Use(bidder2)
+	before := nonce(bidder2)
+	if _, err := service.Bid(ctx, id, usdc(5000), true); !errors.Is(err, util.ErrDryRun) {
+		t.Fatalf("错误为 %v, 预期 %v", err, util.ErrDryRun)
+	}
+	if out := logs.String(); strings.Count(out, "模拟执行成功") != 1 || !strings.Contains(out, "跳过出价的模拟执行") {
+		t.Fatalf("日志:\n%s", out)
+	}
+	if got := nonce(bidder2); got != before {
+		t.Fatalf("出价者的 nonce 为 %d, 预期 %d", got, before)
+	}
+
+	// 授权额度足够时模拟出价, 出价过低时返回回滚原因
+	token, err := auction.NewMockUSDC(chain.USDC, chain.Client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := token.Approve(chain.Transactor(t, bidder2), service.Address, usdc(5000)); err != nil {
+		t.Fatal(err)
+	}
+	before = nonce(bidder2)
+	logs.Reset()
+	if _, err := service.Bid(ctx, id, usdc(5000), true); !errors.Is(err, util.ErrDryRun) {
+		t.Fatalf("错误为 %v, 预期 %v", err, util.ErrDryRun)
+	}
+	if out := logs.String(); strings.Count(out, "模拟执行成功") != 1 || strings.Contains(out, "跳过") {
+		t.Fatalf("日志:\n%s", out)
+	}
+	if _, err := service.Bid(ctx, id, usdc(1), true); err == nil || !strings.Contains(err.Error(), "BidTooLow") {
+		t.Fatalf("出价过低的错误为 %v", err)
+	}
+
+	// 创建拍卖同样只模拟授权 NFT 的交易
+	chain.Use(seller)
+	logs.Reset()
+	if _, _, err := service.Create(ctx, chain.NFT, big.NewInt(6), ether(1), false, time.Hour); !errors.Is(err, util.ErrDryRun) {
+		t.Fatalf("错误为 %v, 预期 %v", err, util.ErrDryRun)
+	}
+	if out := logs.String(); !strings.Contains(out, "跳过创建拍卖的模拟执行") {
+		t.Fatalf("日志:\n%s", out)
+	}
+
+	// 没有广播任何交易
+	if got := nonce(bidder2); got != before {
+		t.Fatalf("出价者的 nonce 为 %d, 预期 %d", got, before)
+	}
+	if a := show(t, service, id); a.HighestBidder != (common.Address{}) {
+		t.Fatalf("模拟出价后的拍卖信息: %+v", a)
+	}
+	if got := nftOwner(t, chain, 6); got != chain.Accounts[seller] {
+		t.Fatalf("模拟创建拍卖后 NFT 的所有者为 %s", got.Hex())
+	}
+}
@@ -2,21 +2,56 @@ package blocks
 
 import (
 	"context"
-	"log"
+	"fmt"
 	"math/big"
 	"task1/util"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 )
 
+// BlockInfo 区块查询结果
+type BlockInfo struct {
+	Number     uint64      // 区块号
+	Hash       common.Hash // 区块哈希
+	ParentHash common.Hash // 父区块哈希
+	Time       uint64      // 区块时间戳
+	TxCount    int         // 交易数量
+	Verified   int         // 交叉校验一致的端点数量, 0 表示未校验
+}
+
 // 使用 ethclient 连接到 Sepolia 测试网络。
 // 实现查询指定区块号的区块信息，包括区块的哈希、时间戳、交易数量等。
-// 输出查询结果到控制台。
-func QueryById(id int64) {
-	client := util.LoadClient()
-	block, err := client.BlockByNumber(context.Background(), big.NewInt(id))
+// 返回查询结果, 由调用方负责输出。
+// 开启 --verify-quorum 时 client 需要是 *util.Pool, 从多个端点获取同一区块并比较哈希、状态根和交易数量
+func QueryById(ctx context.Context, client util.Client, id int64) (*BlockInfo, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("%w: 区块ID必须为正整数", util.ErrInvalidArgument)
+	}
+	var (
+		block    *types.Block
+		err      error
+		verified int
+	)
+	if quorum := util.VerifyQuorum(); quorum > 1 {
+		pool, ok := client.(*util.Pool)
+		if !ok {
+			return nil, fmt.Errorf("%w: 区块一致性校验需要使用连接池客户端", util.ErrInvalidArgument)
+		}
+		block, err = pool.VerifyBlock(ctx, big.NewInt(id), quorum)
+		verified = quorum
+	} else {
+		block, err = client.BlockByNumber(ctx, big.NewInt(id))
+	}
 	if err != nil {
-		log.Fatal("区块查询失败: ", err)
+		return nil, fmt.Errorf("区块查询失败: %w", err)
 	}
-	log.Printf("区块 %d 的哈希: %s\n", id, block.Hash().Hex())
-	log.Printf("区块 %d 的时间戳: %d\n", id, block.Time())
-	log.Printf("区块 %d 的交易数量: %d\n", id, len(block.Transactions()))
+	return &BlockInfo{
+		Number:     block.NumberU64(),
+		Hash:       block.Hash(),
+		ParentHash: block.ParentHash(),
+		Time:       block.Time(),
+		TxCount:    len(block.Transactions()),
+		Verified:   verified,
+	}, nil
 }
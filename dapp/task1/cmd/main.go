@@ -1,14 +1,35 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"math/big"
+	"os"
+	"os/signal"
+	"slices"
 	"strings"
+	"task1/account"
+	"task1/auction"
 	"task1/blocks"
 	"task1/contracts"
+	"task1/decoder"
+	"task1/errs"
+	"task1/proxy"
 	"task1/transactions"
 	"task1/util"
+	"time"
 
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/console/prompt"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/spf13/cobra"
 )
 
@@ -16,6 +37,25 @@ import (
 func init() {
 	// 设置根命令的持久标志
 	rootCmd.PersistentFlags().StringP("env-file", "e", "", "指定环境变量文件路径 (默认: .env)")
+	rootCmd.PersistentFlags().String("proxy", "", "代理地址, 支持 http/https/socks5, none 表示直连 (默认: 环境文件 PROXY 或系统 HTTPS_PROXY)")
+	rootCmd.PersistentFlags().Int("verify-quorum", 0, "从 N 个端点交叉校验区块和交易收据, 不一致时报错 (默认: 不校验)")
+	rootCmd.PersistentFlags().Uint64("confirmations", util.DefaultWaitConfig().Confirmations, "等待交易达到的确认数, 交易所在区块算 1 个确认")
+	rootCmd.PersistentFlags().Duration("wait-timeout", util.DefaultWaitConfig().Timeout, "等待交易确认的最长时间, 0 表示一直等待直到 Ctrl+C")
+	rootCmd.PersistentFlags().String("max-fee", "", "最高费用 maxFeePerGas, 默认单位 gwei, 如 30 / 30.5gwei, 传统交易时作为 gasPrice (默认: 2 * 基础费用 + 优先费)")
+	rootCmd.PersistentFlags().String("priority-fee", "", "优先费 maxPriorityFeePerGas, 默认单位 gwei (默认: 按 --fee-strategy 估算或使用节点建议值)")
+	rootCmd.PersistentFlags().String("fee-strategy", "", fmt.Sprintf("按最近区块小费百分位数估算优先费 (可选: %s, 默认: 节点建议值)", strings.Join(transactions.FeeStrategies(), ", ")))
+	rootCmd.PersistentFlags().Bool("legacy", false, "发送传统交易(gasPrice), 用于未启用 EIP-1559 的链")
+	rootCmd.PersistentFlags().String("nonce-store", "", "nonce 持久化文件, 多个进程并发发送时共享 (默认: 环境文件 NONCE_STORE, 未配置时只保存在内存中)")
+	rootCmd.PersistentFlags().String("from", "", "签名账户地址, env 表示使用环境文件中的明文 PRIVATE_KEY (默认: 环境文件 FROM, 或 keystore 中唯一的账户)")
+	rootCmd.PersistentFlags().String("keystore", "", "keystore 目录 (默认: 环境文件 KEYSTORE_DIR 或 "+account.DefaultKeystoreDir+")")
+	rootCmd.PersistentFlags().String("password-file", "", "账户密码文件, 读取第一行作为密码 (默认: 在终端提示输入)")
+	rootCmd.PersistentFlags().Uint32("account-index", 0, "使用助记词派生的第 i 个账户签名, 派生路径为 --hd-path/i (默认: 不使用助记词)")
+	rootCmd.PersistentFlags().String("hd-path", "", "助记词派生基础路径 (默认: 环境文件 HD_PATH 或 "+account.DefaultHDPath+")")
+	rootCmd.PersistentFlags().Bool("dry-run", false, "只在 pending 状态上模拟执行交易, 输出预计手续费和发送后余额, 不广播")
+	rootCmd.PersistentFlags().String("format", formatText, "交易收据的输出格式 (可选: text, json), json 输出到标准输出")
+	rootCmd.PersistentFlags().StringSlice("abi", nil, "用于解码事件日志和回滚原因的 ABI 文件, 支持 ABI 数组和 Hardhat / Foundry 编译产物, 可重复指定 (默认: 环境文件 ABI_FILES, 逗号分隔)")
+	rootCmd.PersistentFlags().String("signer-url", "", "Clef 兼容的外部签名服务地址, 如 http://127.0.0.1:8550 或 clef.ipc 文件路径 (默认: 环境文件 SIGNER_URL, 未配置时使用本地账户)")
+	rootCmd.PersistentFlags().StringP("network", "n", "", fmt.Sprintf("指定网络配置 (可选: %s, 默认: 环境变量 NETWORK 或 %s)", strings.Join(util.NetworkNames(), ", "), util.DEFAULT_NETWORK))
 
 	// 设置区块查询命令的标志 - 使用 Int64P 而不是 StringP 更合适
 	blocksCmd.Flags().Int64P("id", "i", 0, "区块ID (必需)")
@@ -23,32 +63,203 @@ func init() {
 
 	// 设置交易命令的标志
 	transactionsCmd.Flags().StringP("to", "t", "", "接收地址 (必需)")
-	transactionsCmd.Flags().Int64P("amount", "a", 0, "转账金额 (必需)")
-	transactionsCmd.Flags().UintP("digits", "d", 0, "小数位数 (必需)")
+	transactionsCmd.Flags().StringP("amount", "a", "", "转账金额 (必需), 默认单位 ether, 如 0.001 / 1.5ether / 250gwei / 1000wei")
 	transactionsCmd.MarkFlagRequired("to")
 	transactionsCmd.MarkFlagRequired("amount")
-	transactionsCmd.MarkFlagRequired("digits")
+	for _, cmd := range []*cobra.Command{transactionsSpeedupCmd, transactionsCancelCmd} {
+		cmd.Flags().String("hash", "", "卡住的交易哈希 (必需)")
+		cmd.MarkFlagRequired("hash")
+	}
+
+	// 设置离线签名命令的标志
+	txBuildCmd.Flags().StringP("to", "t", "", "接收地址, 为空表示部署合约")
+	txBuildCmd.Flags().StringP("amount", "a", "0", "转账金额, 默认单位 ether")
+	txBuildCmd.Flags().String("data", "", "交易数据 (十六进制)")
+	txBuildCmd.Flags().Uint64("gas", 0, "gas 限制 (默认: eth_estimateGas 估算)")
+	txBuildCmd.Flags().Uint64("nonce", 0, "nonce (默认: 由 --nonce-store 持久化的 nonce 管理器分配, 未启用持久化时必须指定)")
+	txBuildCmd.Flags().StringP("out", "o", "", "未签名交易文件 (默认: 标准输出)")
+	txSignCmd.Flags().StringP("in", "i", "", "未签名交易文件, - 表示标准输入 (必需)")
+	txSignCmd.Flags().StringP("out", "o", "", "签名后的交易文件 (默认: 标准输出)")
+	txSignCmd.MarkFlagRequired("in")
+	txBroadcastCmd.Flags().StringP("in", "i", "", "签名后的交易文件或 RLP 十六进制文件, - 表示标准输入")
+	txBroadcastCmd.Flags().String("raw", "", "签名交易的 RLP 十六进制字符串")
+	txBroadcastCmd.MarkFlagsOneRequired("in", "raw")
+	txBroadcastCmd.MarkFlagsMutuallyExclusive("in", "raw")
+
+	// 设置账户命令的标志
+	accountImportCmd.Flags().String("key-file", "", "私钥文件, 内容为十六进制私钥或其他工具导出的 keystore JSON (默认: 在终端提示输入私钥)")
+	accountImportCmd.Flags().Bool("from-env", false, "导入环境文件中的 PRIVATE_KEY, 导入后请从环境文件中删除")
+	accountImportCmd.MarkFlagsMutuallyExclusive("key-file", "from-env")
+	accountExportCmd.Flags().StringP("out", "o", "", "导出的 keystore 文件 (默认: 标准输出)")
+	accountDeriveCmd.Flags().Uint32("count", 10, "派生的账户数量, 最多 1000 个")
+	accountDeriveCmd.Flags().Uint32("start", 0, "起始账户索引")
+	accountMnemonicNewCmd.Flags().Int("words", 12, "助记词单词数 (12/15/18/21/24)")
+	accountMnemonicImportCmd.Flags().String("mnemonic-file", "", "助记词文件 (默认: 在终端提示输入)")
+	accountMnemonicImportCmd.Flags().Bool("from-env", false, "导入环境文件中的 MNEMONIC, 导入后请从环境文件中删除")
+	accountMnemonicImportCmd.MarkFlagsMutuallyExclusive("mnemonic-file", "from-env")
 
 	// 设置合约命令的标志
-	contractsCmd.PersistentFlags().StringP("path", "p", "~/.task1_contractsAddress", "合约地址文件路径")
+	for _, cmd := range []*cobra.Command{contractsCmd, contractCmd} {
+		cmd.PersistentFlags().String("deployments", "", "部署记录文件 (默认: 环境文件 DEPLOYMENTS_FILE 或 "+contracts.DefaultDeploymentsFile+")")
+	}
+	contractsListCmd.Flags().Bool("all", false, "列出所有链的部署记录 (默认: 只列出当前网络)")
+	contractsShowCmd.Flags().String("export-abi", "", "将部署记录中的 ABI 导出到文件")
 	contractsDeployCmd.Flags().BoolP("redeploy", "r", false, "(历史部署过的情况下)重新部署合约 (默认: false)")
 	contractsCallCmd.Flags().StringP("method", "m", "", "调用合约的方法名 (必需) 只能是 'count' 或 'increment'")
 	contractsCallCmd.MarkFlagRequired("method")
+	for _, cmd := range []*cobra.Command{contractCallCmd, contractSendCmd} {
+		cmd.Flags().String("address", "", "合约地址或部署记录中的合约名 (必需)")
+		cmd.Flags().StringP("method", "m", "", "方法名或完整签名, 重载的方法需要使用签名, 如 'transfer(address,uint256)' (必需)")
+		cmd.Flags().StringArray("args", nil, "方法参数, 按顺序重复指定; 数组和 tuple 写成 JSON, 如 --args 0x... --args '[1,2]'")
+		cmd.MarkFlagRequired("address")
+		cmd.MarkFlagRequired("method")
+	}
+	contractCallCmd.Flags().Uint64("block", 0, "在指定区块的状态上调用 (默认: 最新区块)")
+	contractSendCmd.Flags().StringP("value", "v", "0", "附带的金额, 只能用于 payable 方法, 默认单位 ether")
+	contractDeployCmd.Flags().String("bin", "", "solc --bin 输出的字节码文件 (默认: 使用 --abi 编译产物中的 bytecode)")
+	contractDeployCmd.Flags().StringArray("args", nil, "构造函数参数, 按顺序重复指定; 数组和 tuple 写成 JSON")
+	contractDeployCmd.Flags().StringP("value", "v", "0", "附带的金额, 只能用于 payable 构造函数, 默认单位 ether")
+	contractDeployCmd.Flags().StringArray("lib", nil, "链接的库地址, 格式为 库名=地址 或 源文件:库名=地址, 可重复指定")
+	contractDeployCmd.Flags().String("name", "", "部署记录中的合约名 (默认: 编译产物的 contractName 或 ABI 文件名)")
+
+	// 设置拍卖命令的标志
+	auctionCmd.PersistentFlags().String("deployments", "", "部署记录文件 (默认: 环境文件 DEPLOYMENTS_FILE 或 "+contracts.DefaultDeploymentsFile+")")
+	auctionCmd.PersistentFlags().String("auction", auction.AuctionName, "拍卖合约地址或部署记录中的合约名")
+	auctionCreateCmd.Flags().String("nft", auction.NFTName, "NFT 合约地址或部署记录中的合约名")
+	auctionCreateCmd.Flags().String("token-id", "", "NFT token ID (必需)")
+	auctionCreateCmd.Flags().String("price", "", "起始价格 (必需), ETH 计价时默认单位 ether, USDC 计价时为 USDC 数量, 如 100 / 1.5")
+	auctionCreateCmd.Flags().Bool("usdc", false, "使用 USDC 计价 (默认: ETH)")
+	auctionCreateCmd.Flags().Duration("duration", 24*time.Hour, "拍卖时长, 需要大于 1h 且不超过 168h")
+	auctionCreateCmd.MarkFlagRequired("token-id")
+	auctionCreateCmd.MarkFlagRequired("price")
+	auctionBidCmd.Flags().StringP("amount", "a", "", "出价金额 (必需), ETH 出价时默认单位 ether, USDC 出价时为 USDC 数量")
+	auctionBidCmd.Flags().Bool("usdc", false, "使用 USDC 出价, 授权额度不足时自动发送 approve 交易 (默认: ETH)")
+	auctionBidCmd.MarkFlagRequired("amount")
+	auctionListCmd.Flags().String("status", "", fmt.Sprintf("只列出指定状态的拍卖 (可选: %s)", strings.Join(auction.Statuses(), ", ")))
+
+	// 设置代理命令的标志
+	proxyCmd.PersistentFlags().String("deployments", "", "部署记录文件 (默认: 环境文件 DEPLOYMENTS_FILE 或 "+contracts.DefaultDeploymentsFile+")")
+	for _, cmd := range []*cobra.Command{proxyDeployCmd, proxyUpgradeCmd} {
+		cmd.Flags().String("bin", "", "实现合约的字节码文件 (默认: 使用 --abi 编译产物中的 bytecode)")
+		cmd.Flags().StringArray("lib", nil, "实现合约链接的库地址, 格式为 库名=地址 或 源文件:库名=地址, 可重复指定")
+		cmd.Flags().StringArray("args", nil, "初始化方法的参数, 按顺序重复指定; 数组和 tuple 写成 JSON")
+		cmd.Flags().StringP("value", "v", "0", "附带的金额, 只能用于 payable 的初始化方法, 默认单位 ether")
+		cmd.Flags().String("layout", "", "实现合约的存储布局 (默认: 从 --abi 编译产物读取, 支持 Foundry 编译产物、Hardhat 编译产物和布局 JSON)")
+	}
+	proxyDeployCmd.Flags().String("name", "", "部署记录中的合约名 (默认: 编译产物的 contractName 或 ABI 文件名)")
+	proxyDeployCmd.Flags().String("initializer", "", "在代理构造函数中调用的初始化方法, 如 __NFTAuction_init (默认: 不调用)")
+	proxyDeployCmd.Flags().String("proxy-artifact", "", "代理合约的编译产物, 如 OpenZeppelin 的 ERC1967Proxy.json (默认: 内置的 ERC1967 代理)")
+	proxyUpgradeCmd.Flags().String("call", "", "升级时通过 upgradeToAndCall 调用的方法, 如 __NFTAuctionV2_init (默认: 不调用)")
+	proxyUpgradeCmd.Flags().String("old-layout", "", "当前实现合约的存储布局 (默认: 部署记录中保存的布局), 支持 OpenZeppelin Upgrades 清单 .openzeppelin/<网络>.json")
+	proxyUpgradeCmd.Flags().Bool("unsafe-skip-storage-check", false, "跳过存储布局兼容性检查")
+
+	// 设置网络命令的标志
+	networksCmd.Flags().BoolP("check", "c", false, "检查当前网络所有端点的健康状态")
 
 	// 将子命令添加到根命令
 	rootCmd.AddCommand(blocksCmd)
 	rootCmd.AddCommand(transactionsCmd)
+	rootCmd.AddCommand(txCmd)
+	rootCmd.AddCommand(accountCmd)
 	rootCmd.AddCommand(contractsCmd)
+	rootCmd.AddCommand(contractCmd)
+	rootCmd.AddCommand(auctionCmd)
+	rootCmd.AddCommand(proxyCmd)
 	rootCmd.AddCommand(envTemplateCmd)
+	rootCmd.AddCommand(networksCmd)
 
 	// 添加子命令的命令
+	transactionsCmd.AddCommand(transactionsSpeedupCmd)
+	transactionsCmd.AddCommand(transactionsCancelCmd)
+	accountCmd.AddCommand(accountNewCmd)
+	accountCmd.AddCommand(accountImportCmd)
+	accountCmd.AddCommand(accountListCmd)
+	accountCmd.AddCommand(accountExportCmd)
+	accountCmd.AddCommand(accountDeriveCmd)
+	accountCmd.AddCommand(accountMnemonicCmd)
+	accountMnemonicCmd.AddCommand(accountMnemonicNewCmd)
+	accountMnemonicCmd.AddCommand(accountMnemonicImportCmd)
+	txCmd.AddCommand(txBuildCmd)
+	txCmd.AddCommand(txSignCmd)
+	txCmd.AddCommand(txBroadcastCmd)
 	contractsCmd.AddCommand(contractsDeployCmd)
 	contractsCmd.AddCommand(contractsCallCmd)
+	contractsCmd.AddCommand(contractsListCmd)
+	contractsCmd.AddCommand(contractsShowCmd)
+	contractsCmd.AddCommand(contractsForgetCmd)
+	contractCmd.AddCommand(contractCallCmd)
+	contractCmd.AddCommand(contractSendCmd)
+	contractCmd.AddCommand(contractDeployCmd)
+	auctionCmd.AddCommand(auctionCreateCmd)
+	auctionCmd.AddCommand(auctionBidCmd)
+	auctionCmd.AddCommand(auctionEndCmd)
+	auctionCmd.AddCommand(auctionCancelCmd)
+	auctionCmd.AddCommand(auctionShowCmd)
+	auctionCmd.AddCommand(auctionListCmd)
+	auctionCmd.AddCommand(auctionFeesCmd)
+	auctionCmd.AddCommand(auctionSetFeeCmd)
+	auctionCmd.AddCommand(auctionWithdrawFeesCmd)
+	proxyCmd.AddCommand(proxyDeployCmd)
+	proxyCmd.AddCommand(proxyUpgradeCmd)
+	proxyCmd.AddCommand(proxyShowCmd)
+}
+
+// 退出码, 便于脚本区分错误类型
+const (
+	exitError       = 1 // 其他错误
+	exitUsage       = 2 // 参数错误
+	exitConfig      = 3 // 配置错误: 环境文件、网络、代理、私钥
+	exitNetwork     = 4 // 没有可用的 RPC 端点或节点持续返回网络、限流、鉴权错误
+	exitConsistency = 5 // 多端点数据不一致
+	exitTxFailed    = 6 // 交易执行失败或被重组丢弃
+	exitTimeout     = 7 // 等待交易确认超时
+	exitNotDeployed = 8 // 合约未部署
+)
+
+// exitCode 根据错误类型返回退出码
+func exitCode(err error) int {
+	var qe *util.QuorumError
+	switch {
+	case errors.Is(err, util.ErrInvalidArgument):
+		return exitUsage
+	case errors.Is(err, util.ErrConfig):
+		return exitConfig
+	case errors.Is(err, util.ErrNoHealthyEndpoint):
+		return exitNetwork
+	case errors.As(err, &qe), errors.Is(err, util.ErrQuorumUnavailable):
+		return exitConsistency
+	case errors.Is(err, util.ErrTxFailed), errors.Is(err, util.ErrReorged):
+		return exitTxFailed
+	case errors.Is(err, util.ErrTimeout), errors.Is(err, context.DeadlineExceeded):
+		return exitTimeout
+	case errors.Is(err, util.ErrNotDeployed):
+		return exitNotDeployed
+	}
+	switch errs.KindOf(err) {
+	case errs.ErrNetwork, errs.ErrTimeout, errs.ErrRateLimited, errs.ErrUnavailable, errs.ErrUnauthorized:
+		return exitNetwork
+	case errs.ErrExecutionReverted:
+		return exitTxFailed
+	}
+	return exitError
 }
 
 func main() {
+	// 库内部的进度日志输出到标准日志
+	util.SetLogger(log.Default())
+
+	// 参数解析错误归类为参数错误
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return fmt.Errorf("%w: %w", util.ErrInvalidArgument, err)
+	})
+
 	// 在执行命令前处理环境文件配置
 	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		// 生成模板不需要读取环境文件
+		if cmd == envTemplateCmd {
+			return nil
+		}
+
 		envFile, err := cmd.Flags().GetString("env-file")
 		if err != nil {
 			return fmt.Errorf("获取环境文件参数错误: %w", err)
@@ -57,22 +268,638 @@ func main() {
 		if envFile != "" {
 			// 验证自定义环境文件
 			if err := util.ValidateEnvFile(envFile); err != nil {
-				return fmt.Errorf("环境文件验证失败: %w", err)
+				return fmt.Errorf("%w: 环境文件验证失败: %w", util.ErrConfig, err)
 			}
+			log.Printf("使用自定义环境文件: %s", envFile)
+		}
+		// 未指定自定义环境文件时从当前目录向上查找 .env
+		if err := util.InitConfig(envFile); err != nil {
+			return fmt.Errorf("初始化配置失败: %w", err)
+		}
+
+		// 命令行指定的代理优先于环境配置
+		proxy, err := cmd.Flags().GetString("proxy")
+		if err != nil {
+			return fmt.Errorf("获取代理参数错误: %w", err)
+		}
+		util.SetProxy(proxy)
+		if _, err := util.LoadProxyConfig(); err != nil {
+			return fmt.Errorf("%w: 代理配置错误: %w", util.ErrConfig, err)
+		}
+
+		// 选择网络配置, 需要在环境文件加载之后执行以便读取 NETWORK 等覆盖项
+		networkName, err := cmd.Flags().GetString("network")
+		if err != nil {
+			return fmt.Errorf("获取网络参数错误: %w", err)
+		}
+		if err := util.SetNetwork(networkName); err != nil {
+			return fmt.Errorf("选择网络失败: %w", err)
+		}
+
+		quorum, err := cmd.Flags().GetInt("verify-quorum")
+		if err != nil {
+			return fmt.Errorf("获取一致性校验参数错误: %w", err)
+		}
+		if quorum < 0 {
+			return fmt.Errorf("%w: 一致性校验端点数量不能为负数: %d", util.ErrInvalidArgument, quorum)
+		}
+		util.SetVerifyQuorum(quorum)
+
+		dryRun, err := cmd.Flags().GetBool("dry-run")
+		if err != nil {
+			return fmt.Errorf("获取模拟执行参数错误: %w", err)
+		}
+		util.SetDryRun(dryRun)
+
+		waitConfig := util.DefaultWaitConfig()
+		if waitConfig.Confirmations, err = cmd.Flags().GetUint64("confirmations"); err != nil {
+			return fmt.Errorf("获取确认数参数错误: %w", err)
+		}
+		if waitConfig.Timeout, err = cmd.Flags().GetDuration("wait-timeout"); err != nil {
+			return fmt.Errorf("获取等待超时参数错误: %w", err)
+		}
+		if waitConfig.Timeout < 0 {
+			return fmt.Errorf("%w: 等待超时不能为负数: %s", util.ErrInvalidArgument, waitConfig.Timeout)
+		}
+		util.SetWaitConfig(waitConfig)
+
+		feeOptions, err := loadFeeOptions(cmd)
+		if err != nil {
+			return err
+		}
+		transactions.SetFeeOptions(feeOptions)
+
+		// 命令行指定的 nonce 存储优先于环境文件
+		nonceStore, err := cmd.Flags().GetString("nonce-store")
+		if err != nil {
+			return fmt.Errorf("获取 nonce 存储参数错误: %w", err)
+		}
+		if nonceStore == "" {
+			nonceStore = util.LoadEnv("<NONCE_STORE>")
+		}
+		if err := util.SetNonceStore(util.ExpandHome(nonceStore)); err != nil {
+			return fmt.Errorf("加载 nonce 存储失败: %w", err)
+		}
+
+		accountConfig, err := loadAccountConfig(cmd)
+		if err != nil {
+			return err
+		}
+		account.SetConfig(accountConfig)
 
-			// 使用自定义环境文件初始化配置
-			if err := util.InitConfig(envFile); err != nil {
-				return fmt.Errorf("初始化配置失败: %w", err)
+		if outputFormat, err = cmd.Flags().GetString("format"); err != nil {
+			return fmt.Errorf("获取输出格式参数错误: %w", err)
+		}
+		if outputFormat != formatText && outputFormat != formatJSON {
+			return fmt.Errorf("%w: 未知的输出格式 %q, 可选: %s, %s", util.ErrInvalidArgument, outputFormat, formatText, formatJSON)
+		}
+
+		// 用户提供的 ABI 文件用于解码事件日志和回滚原因, 优先于内置定义
+		abiFiles, err := cmd.Flags().GetStringSlice("abi")
+		if err != nil {
+			return fmt.Errorf("获取 ABI 文件参数错误: %w", err)
+		}
+		if len(abiFiles) == 0 {
+			abiFiles = strings.FieldsFunc(util.LoadEnv("<ABI_FILES>"), func(r rune) bool { return r == ',' })
+		}
+		for _, file := range abiFiles {
+			name, err := decoder.LoadFile(strings.TrimSpace(file))
+			if err != nil {
+				return fmt.Errorf("%w: %w", util.ErrConfig, err)
 			}
-			log.Printf("使用自定义环境文件: %s", envFile)
+			log.Printf("已加载合约 %s 的 ABI: %s", name, file)
+		}
+
+		return nil
+	}
+
+	// Ctrl+C 时取消正在进行的查询和等待
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	err := rootCmd.ExecuteContext(ctx)
+	stop()
+	account.CloseSigner()
+	if errors.Is(err, util.ErrDryRun) {
+		log.Print("--dry-run: ", util.ErrDryRun)
+		return
+	}
+	if err != nil {
+		log.Print("命令执行错误: ", err)
+		os.Exit(exitCode(err))
+	}
+}
+
+// loadAccountConfig 读取签名账户相关参数, 未指定时使用环境文件的 FROM / KEYSTORE_DIR / HD_PATH / SIGNER_URL
+func loadAccountConfig(cmd *cobra.Command) (account.Config, error) {
+	var config account.Config
+	var err error
+	if config.From, err = cmd.Flags().GetString("from"); err != nil {
+		return config, fmt.Errorf("获取签名账户参数错误: %w", err)
+	}
+	if config.From == "" {
+		config.From = util.LoadEnv("<FROM>")
+	}
+	if config.From != "" && config.From != account.FromEnv {
+		if _, err := account.ParseAddress(config.From); err != nil {
+			return config, fmt.Errorf("--from: %w", err)
+		}
+	}
+	if config.KeystoreDir, err = cmd.Flags().GetString("keystore"); err != nil {
+		return config, fmt.Errorf("获取 keystore 参数错误: %w", err)
+	}
+	if config.KeystoreDir == "" {
+		config.KeystoreDir = util.LoadEnv("<KEYSTORE_DIR>")
+	}
+	if config.PasswordFile, err = cmd.Flags().GetString("password-file"); err != nil {
+		return config, fmt.Errorf("获取密码文件参数错误: %w", err)
+	}
+	if cmd.Flags().Changed("account-index") {
+		index, err := cmd.Flags().GetUint32("account-index")
+		if err != nil {
+			return config, fmt.Errorf("获取账户索引参数错误: %w", err)
+		}
+		config.AccountIndex = &index
+	}
+	if config.HDPath, err = cmd.Flags().GetString("hd-path"); err != nil {
+		return config, fmt.Errorf("获取派生路径参数错误: %w", err)
+	}
+	if config.HDPath == "" {
+		config.HDPath = util.LoadEnv("<HD_PATH>")
+	}
+	if _, err := account.ParseHDPath(config.HDPath); err != nil {
+		return config, fmt.Errorf("--hd-path: %w", err)
+	}
+	if config.SignerURL, err = cmd.Flags().GetString("signer-url"); err != nil {
+		return config, fmt.Errorf("获取签名服务参数错误: %w", err)
+	}
+	if config.SignerURL == "" {
+		config.SignerURL = util.LoadEnv("<SIGNER_URL>")
+	}
+	if config.SignerURL != "" {
+		// 外部签名服务自己管理私钥, 只能通过 --from 选择其中的账户
+		if config.AccountIndex != nil || config.From == account.FromEnv {
+			return config, fmt.Errorf("%w: --signer-url 不能与 --account-index 或 --from env 同时使用", util.ErrInvalidArgument)
+		}
+	}
+	return config, nil
+}
+
+// loadFeeOptions 读取手续费相关参数
+func loadFeeOptions(cmd *cobra.Command) (transactions.FeeOptions, error) {
+	var options transactions.FeeOptions
+	var err error
+	if options.Legacy, err = cmd.Flags().GetBool("legacy"); err != nil {
+		return options, fmt.Errorf("获取传统交易参数错误: %w", err)
+	}
+	strategy, err := cmd.Flags().GetString("fee-strategy")
+	if err != nil {
+		return options, fmt.Errorf("获取手续费策略参数错误: %w", err)
+	}
+	if options.Strategy, err = transactions.ParseFeeStrategy(strategy); err != nil {
+		return options, err
+	}
+	maxFee, err := cmd.Flags().GetString("max-fee")
+	if err != nil {
+		return options, fmt.Errorf("获取最高费用参数错误: %w", err)
+	}
+	if maxFee != "" {
+		if options.MaxFee, err = util.ParseAmount(maxFee, "gwei"); err != nil {
+			return options, fmt.Errorf("--max-fee: %w", err)
+		}
+	}
+	priorityFee, err := cmd.Flags().GetString("priority-fee")
+	if err != nil {
+		return options, fmt.Errorf("获取优先费参数错误: %w", err)
+	}
+	if priorityFee != "" {
+		if options.PriorityFee, err = util.ParseAmount(priorityFee, "gwei"); err != nil {
+			return options, fmt.Errorf("--priority-fee: %w", err)
+		}
+	}
+	return options, nil
+}
+
+// loadClient 连接当前网络, 调用方负责关闭
+func loadClient(cmd *cobra.Command) (*util.Pool, error) {
+	return util.LoadClient(cmd.Context())
+}
+
+// replaceTransaction 读取 --hash 参数并执行加速或取消
+func replaceTransaction(cmd *cobra.Command, replace func(context.Context, util.Client, common.Hash) (*types.Receipt, error)) error {
+	hash, err := cmd.Flags().GetString("hash")
+	if err != nil {
+		return fmt.Errorf("获取交易哈希参数错误: %w", err)
+	}
+	if len(strings.TrimPrefix(hash, "0x")) != 64 {
+		return fmt.Errorf("%w: 交易哈希格式错误: %s", util.ErrInvalidArgument, hash)
+	}
+
+	client, err := loadClient(cmd)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	receipt, err := replace(cmd.Context(), client, common.HexToHash(hash))
+	printReceipt(receipt)
+	return err
+}
+
+// loadBuildRequest 读取 tx build 的参数
+func loadBuildRequest(cmd *cobra.Command) (transactions.BuildRequest, error) {
+	var request transactions.BuildRequest
+	// 冷钱包账户不在联网机器的 keystore 中, --from 指定的地址直接使用
+	from, err := account.Address(cmd.Context())
+	if err != nil {
+		return request, err
+	}
+	request.From = from
+
+	to, err := cmd.Flags().GetString("to")
+	if err != nil {
+		return request, fmt.Errorf("获取接收地址参数错误: %w", err)
+	}
+	if to != "" {
+		if !common.IsHexAddress(to) {
+			return request, fmt.Errorf("%w: 接收地址格式错误: %s", util.ErrInvalidArgument, to)
+		}
+		address := common.HexToAddress(to)
+		request.To = &address
+	}
+	amount, err := cmd.Flags().GetString("amount")
+	if err != nil {
+		return request, fmt.Errorf("获取金额参数错误: %w", err)
+	}
+	if request.Value, err = util.ParseAmount(amount, "ether"); err != nil {
+		return request, err
+	}
+	data, err := cmd.Flags().GetString("data")
+	if err != nil {
+		return request, fmt.Errorf("获取交易数据参数错误: %w", err)
+	}
+	if data != "" {
+		if request.Data, err = hexutil.Decode(data); err != nil {
+			return request, fmt.Errorf("%w: 交易数据格式错误: %w", util.ErrInvalidArgument, err)
+		}
+	}
+	if request.Gas, err = cmd.Flags().GetUint64("gas"); err != nil {
+		return request, fmt.Errorf("获取 gas 参数错误: %w", err)
+	}
+	if cmd.Flags().Changed("nonce") {
+		nonce, err := cmd.Flags().GetUint64("nonce")
+		if err != nil {
+			return request, fmt.Errorf("获取 nonce 参数错误: %w", err)
+		}
+		request.Nonce = &nonce
+	}
+	return request, nil
+}
+
+// loadContractMethod 读取 --abi、--address、--method 和 --args 参数, 地址上没有合约代码时返回 util.ErrNotDeployed
+// --address 可以是合约地址或部署记录中的合约名, 使用合约名时默认使用部署记录中的 ABI; 指定多个 --abi 时使用第一个
+func loadContractMethod(cmd *cobra.Command, client util.Client) (*contracts.Contract, *abi.Method, []string, error) {
+	abiFiles, err := cmd.Flags().GetStringSlice("abi")
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("获取 ABI 文件参数错误: %w", err)
+	}
+	var contractABI *abi.ABI
+	if len(abiFiles) > 0 {
+		if _, contractABI, err = decoder.ParseFile(strings.TrimSpace(abiFiles[0])); err != nil {
+			return nil, nil, nil, fmt.Errorf("%w: %w", util.ErrConfig, err)
+		}
+	}
+	target, err := cmd.Flags().GetString("address")
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("获取合约地址参数错误: %w", err)
+	}
+	deployments, err := loadDeployments(cmd)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	address, deployment, err := deployments.Resolve(cmd.Context(), client, target)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if contractABI == nil && deployment != nil {
+		if contractABI, err = deployment.ParseABI(); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	if contractABI == nil {
+		return nil, nil, nil, fmt.Errorf("%w: 需要通过 --abi 指定合约的 ABI 文件, 或通过 --address 使用部署记录中的合约名", util.ErrInvalidArgument)
+	}
+	name, err := cmd.Flags().GetString("method")
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("获取合约方法参数错误: %w", err)
+	}
+	args, err := cmd.Flags().GetStringArray("args")
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("获取方法参数错误: %w", err)
+	}
+	contract := contracts.NewContract(client, address, contractABI)
+	method, err := contract.Method(name)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return contract, method, args, nil
+}
+
+// loadDeployments 打开 --deployments 或环境文件 DEPLOYMENTS_FILE 指定的部署记录文件
+func loadDeployments(cmd *cobra.Command) (*contracts.Deployments, error) {
+	path, err := cmd.Flags().GetString("deployments")
+	if err != nil {
+		return nil, fmt.Errorf("获取部署记录文件参数错误: %w", err)
+	}
+	if path == "" {
+		path = util.LoadEnv("<DEPLOYMENTS_FILE>")
+	}
+	return contracts.OpenDeployments(path), nil
+}
+
+// deploymentChainID 返回部署记录使用的链ID: 优先使用网络配置中的链ID, 未配置时连接节点查询
+func deploymentChainID(cmd *cobra.Command) (*big.Int, error) {
+	network, err := util.CurrentNetwork()
+	if err != nil {
+		return nil, err
+	}
+	if network.ChainID != 0 {
+		return new(big.Int).SetUint64(network.ChainID), nil
+	}
+	client, err := loadClient(cmd)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	chainID, err := client.ChainID(cmd.Context())
+	if err != nil {
+		return nil, fmt.Errorf("获取链ID失败: %w", errs.Classify(err))
+	}
+	return chainID, nil
+}
+
+// deploymentJSON --format json 时输出的部署记录
+type deploymentJSON struct {
+	ChainID *big.Int `json:"chainId"`
+	Name    string   `json:"name"`
+	*contracts.Deployment
+}
+
+// printJSON 以缩进格式输出 JSON 到标准输出
+func printJSON(value any) {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		log.Printf("输出 JSON 失败: %v", err)
+	}
+}
+
+// parseLibraries 解析 --lib 库名=地址 参数
+func parseLibraries(values []string) (map[string]common.Address, error) {
+	libraries := make(map[string]common.Address, len(values))
+	for _, value := range values {
+		name, address, ok := strings.Cut(value, "=")
+		if !ok || name == "" || !common.IsHexAddress(address) {
+			return nil, fmt.Errorf("%w: 库地址格式错误 %q, 示例: SafeMath=0x... 或 contracts/SafeMath.sol:SafeMath=0x...", util.ErrInvalidArgument, value)
+		}
+		libraries[strings.TrimSpace(name)] = common.HexToAddress(address)
+	}
+	return libraries, nil
+}
+
+// printOutputs 输出合约方法的返回值
+func printOutputs(method *abi.Method, outputs []decoder.Arg) {
+	if outputFormat == formatJSON {
+		printJSON(outputs)
+		return
+	}
+	log.Printf("%s 返回值(%d):", method.Sig, len(outputs))
+	for i, output := range outputs {
+		name := output.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i)
+		}
+		log.Printf("  %s (%s): %s", name, output.Type, decoder.FormatValue(output.Value))
+	}
+}
+
+// loadAuction 按 --auction 加载拍卖合约服务
+func loadAuction(cmd *cobra.Command, client util.Client) (*auction.Service, error) {
+	target, err := cmd.Flags().GetString("auction")
+	if err != nil {
+		return nil, fmt.Errorf("获取拍卖合约参数错误: %w", err)
+	}
+	deployments, err := loadDeployments(cmd)
+	if err != nil {
+		return nil, err
+	}
+	return auction.NewService(cmd.Context(), client, deployments, target)
+}
+
+// parseUint256 解析十进制或 0x 十六进制的 uint256 参数, 如拍卖 ID 和 token ID
+func parseUint256(name, value string) (*big.Int, error) {
+	n, ok := math.ParseBig256(value)
+	if !ok || n.Sign() < 0 {
+		return nil, fmt.Errorf("%w: %s 格式错误: %q", util.ErrInvalidArgument, name, value)
+	}
+	return n, nil
+}
+
+// parseBidAmount 解析拍卖金额: ETH 默认单位为 ether, USDC 按代币的小数位数转换
+func parseBidAmount(cmd *cobra.Command, service *auction.Service, value string, usdc bool) (*big.Int, error) {
+	if !usdc {
+		return util.ParseAmount(value, "ether")
+	}
+	_, decimals, err := service.USDC(cmd.Context())
+	if err != nil {
+		return nil, err
+	}
+	return util.ParseUnits(value, int(decimals))
+}
+
+// auctionRunE 返回对拍卖 ID 发送交易的命令实现, 如 end / cancel
+func auctionRunE(send func(*auction.Service, context.Context, *big.Int) (*types.Receipt, error)) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		id, err := parseUint256("拍卖 ID", args[0])
+		if err != nil {
+			return err
+		}
+		client, err := loadClient(cmd)
+		if err != nil {
+			return err
 		}
-		// 如果未指定自定义环境文件，使用默认配置（已在init中初始化）
+		defer client.Close()
 
+		service, err := loadAuction(cmd, client)
+		if err != nil {
+			return err
+		}
+		receipt, err := send(service, cmd.Context(), id)
+		printReceipt(receipt)
+		return err
+	}
+}
+
+// formatBidAmount 按出价代币格式化拍卖金额, 零地址为 ETH
+func formatBidAmount(amount *big.Int, token, usdc common.Address, usdcDecimals uint8) string {
+	switch token {
+	case common.Address{}:
+		return util.FormatUnits(amount, 18) + " ETH"
+	case usdc:
+		return util.FormatUnits(amount, int(usdcDecimals)) + " USDC"
+	}
+	return fmt.Sprintf("%s (代币 %s)", amount, token.Hex())
+}
+
+// printAuctions 输出拍卖信息, USDC 金额按代币的小数位数显示
+func printAuctions(cmd *cobra.Command, service *auction.Service, auctions []*auction.Auction) error {
+	if outputFormat == formatJSON {
+		printJSON(auctions)
 		return nil
 	}
+	var usdc common.Address
+	var decimals uint8
+	for _, a := range auctions {
+		if a.BidToken != (common.Address{}) {
+			var err error
+			if usdc, decimals, err = service.USDC(cmd.Context()); err != nil {
+				return err
+			}
+			break
+		}
+	}
+	for _, a := range auctions {
+		log.Printf("拍卖 %s [%s]", a.ID, a.Status)
+		log.Printf("  NFT: %s #%s", a.NFTContract.Hex(), a.TokenID)
+		log.Printf("  卖家: %s", a.Seller.Hex())
+		log.Printf("  起始价格: %s", formatBidAmount(a.StartingPrice, a.BidToken, usdc, decimals))
+		if a.HighestBidder == (common.Address{}) {
+			log.Printf("  最高出价: 无")
+		} else {
+			log.Printf("  最高出价: %s (%s)", formatBidAmount(a.HighestBid, a.BidToken, usdc, decimals), a.HighestBidder.Hex())
+		}
+		log.Printf("  时间: %s ~ %s", a.StartTime.Local().Format(time.DateTime), a.EndTime.Local().Format(time.DateTime))
+	}
+	return nil
+}
+
+// loadImplementation 读取 --abi / --bin 指定的实现合约编译产物、--lib 库地址和 --value 金额,
+// 以及 --layout 或编译产物中的存储布局; 未指定 --layout 且读取失败时, requireLayout 为 false 则只输出提示
+func loadImplementation(cmd *cobra.Command, requireLayout bool) (*contracts.Artifact, map[string]common.Address, *big.Int, *proxy.StorageLayout, error) {
+	abiFiles, err := cmd.Flags().GetStringSlice("abi")
+	if err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("获取 ABI 文件参数错误: %w", err)
+	}
+	if len(abiFiles) == 0 {
+		return nil, nil, nil, nil, fmt.Errorf("%w: 需要通过 --abi 指定实现合约的编译产物", util.ErrInvalidArgument)
+	}
+	abiFile := strings.TrimSpace(abiFiles[0])
+	binFile, err := cmd.Flags().GetString("bin")
+	if err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("获取字节码文件参数错误: %w", err)
+	}
+	artifact, err := contracts.LoadArtifact(abiFile, binFile)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	libs, err := cmd.Flags().GetStringArray("lib")
+	if err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("获取库地址参数错误: %w", err)
+	}
+	libraries, err := parseLibraries(libs)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	amount, err := cmd.Flags().GetString("value")
+	if err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("获取金额参数错误: %w", err)
+	}
+	value, err := util.ParseAmount(amount, "ether")
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	layoutFile, err := cmd.Flags().GetString("layout")
+	if err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("获取存储布局参数错误: %w", err)
+	}
+	path := layoutFile
+	if path == "" {
+		path = abiFile
+	}
+	layout, err := proxy.LoadLayout(path, common.Address{})
+	if err != nil {
+		if layoutFile != "" || requireLayout {
+			return nil, nil, nil, nil, fmt.Errorf("读取实现合约 %s 的存储布局失败, 可以通过 --layout 指定: %w", artifact.Name, err)
+		}
+		log.Printf("未能读取实现合约 %s 的存储布局, 升级时需要通过 --old-layout 指定: %v", artifact.Name, err)
+	}
+	return artifact, libraries, value, layout, nil
+}
+
+// printReceipts 依次输出多笔交易的收据
+func printReceipts(receipts []*types.Receipt) {
+	for _, receipt := range receipts {
+		printReceipt(receipt)
+	}
+}
+
+// 交易收据的输出格式
+const (
+	formatText = "text"
+	formatJSON = "json"
+)
+
+// outputFormat 通过 --format 指定的输出格式
+var outputFormat = formatText
+
+// receiptJSON --format json 时输出的交易收据
+type receiptJSON struct {
+	TxHash           common.Hash      `json:"transactionHash"`
+	Status           bool             `json:"status"`
+	BlockHash        common.Hash      `json:"blockHash"`
+	BlockNumber      *big.Int         `json:"blockNumber"`
+	TransactionIndex uint             `json:"transactionIndex"`
+	GasUsed          uint64           `json:"gasUsed"`
+	ContractAddress  *common.Address  `json:"contractAddress,omitempty"`
+	Logs             []*decoder.Event `json:"logs"`
+}
+
+// printReceipt 输出交易收据, 事件日志按已知 ABI 解码
+func printReceipt(receipt *types.Receipt) {
+	if receipt == nil {
+		return
+	}
+	events := decoder.DecodeLogs(receipt.Logs)
+	if outputFormat == formatJSON {
+		out := receiptJSON{
+			TxHash:           receipt.TxHash,
+			Status:           receipt.Status == types.ReceiptStatusSuccessful,
+			BlockHash:        receipt.BlockHash,
+			BlockNumber:      receipt.BlockNumber,
+			TransactionIndex: receipt.TransactionIndex,
+			GasUsed:          receipt.GasUsed,
+			Logs:             events,
+		}
+		if receipt.ContractAddress != (common.Address{}) {
+			out.ContractAddress = &receipt.ContractAddress
+		}
+		printJSON(out)
+		return
+	}
 
-	if err := rootCmd.Execute(); err != nil {
-		log.Fatal("命令执行错误: ", err)
+	log.Printf("交易: %s, 状态: %v\n", receipt.TxHash.Hex(), receipt.Status == types.ReceiptStatusSuccessful)
+	if network, err := util.CurrentNetwork(); err == nil {
+		if txURL := network.TxURL(receipt.TxHash.Hex()); txURL != "" {
+			log.Printf("浏览器: %s\n", txURL)
+		}
+	}
+	log.Printf("区块哈希: %s\n", receipt.BlockHash.Hex())
+	log.Printf("区块号: %d\n", receipt.BlockNumber)
+	log.Printf("交易索引: %d\n", receipt.TransactionIndex)
+	if receipt.ContractAddress != (common.Address{}) {
+		log.Println("部署的合约地址: ", receipt.ContractAddress.Hex())
+	}
+	log.Printf("logs(%d):", len(events))
+	for _, event := range events {
+		log.Printf("  %s", event)
 	}
 }
 
@@ -82,6 +909,9 @@ var (
 		Use:   "task1",
 		Short: "以太坊区块和交易操作工具",
 		Long:  "一个用于查询以太坊区块信息和执行以太坊交易的命令行工具",
+		// 错误由 main 统一输出并转换为退出码
+		SilenceErrors: true,
+		SilenceUsage:  true,
 	}
 
 	// blocksCmd 区块查询命令
@@ -89,17 +919,30 @@ var (
 		Use:   "blocks",
 		Short: "查询区块信息",
 		Long:  "根据区块ID查询以太坊区块的详细信息",
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			// 获取并验证区块ID参数
 			id, err := cmd.Flags().GetInt64("id")
 			if err != nil {
-				log.Fatal("获取区块ID参数错误: ", err)
+				return fmt.Errorf("获取区块ID参数错误: %w", err)
 			}
-			if id <= 0 {
-				log.Fatal("区块ID必须为正整数")
+
+			client, err := loadClient(cmd)
+			if err != nil {
+				return err
 			}
+			defer client.Close()
 
-			blocks.QueryById(id)
+			info, err := blocks.QueryById(cmd.Context(), client, id)
+			if err != nil {
+				return err
+			}
+			if info.Verified > 1 {
+				log.Printf("区块 %d 已在 %d 个端点间校验一致\n", id, info.Verified)
+			}
+			log.Printf("区块 %d 的哈希: %s\n", id, info.Hash.Hex())
+			log.Printf("区块 %d 的时间戳: %d\n", id, info.Time)
+			log.Printf("区块 %d 的交易数量: %d\n", id, info.TxCount)
+			return nil
 		},
 	}
 
@@ -107,93 +950,1347 @@ var (
 	transactionsCmd = &cobra.Command{
 		Use:   "transactions",
 		Short: "执行以太坊交易",
-		Long:  "执行以太坊转账交易，需要指定接收地址、金额和小数位数",
-		Run: func(cmd *cobra.Command, args []string) {
+		Long:  "执行以太坊转账交易，需要指定接收地址和金额",
+		RunE: func(cmd *cobra.Command, args []string) error {
 			// 获取并验证接收地址
 			to, err := cmd.Flags().GetString("to")
 			if err != nil {
-				log.Fatal("获取接收地址参数错误: ", err)
-			}
-			if to == "" {
-				log.Fatal("接收地址不能为空")
+				return fmt.Errorf("获取接收地址参数错误: %w", err)
 			}
 
-			// 获取并验证转账金额
-			amount, err := cmd.Flags().GetInt64("amount")
+			// 获取并精确解析转账金额
+			amount, err := cmd.Flags().GetString("amount")
 			if err != nil {
-				log.Fatal("获取金额参数错误: ", err)
+				return fmt.Errorf("获取金额参数错误: %w", err)
 			}
-			if amount <= 0 {
-				log.Fatal("转账金额必须为正数")
+			value, err := util.ParseAmount(amount, "ether")
+			if err != nil {
+				return err
 			}
 
-			// 获取并验证小数位数
-			digits, err := cmd.Flags().GetUint("digits")
+			client, err := loadClient(cmd)
 			if err != nil {
-				log.Fatal("获取小数位数参数错误: ", err)
+				return err
 			}
+			defer client.Close()
 
-			transactions.Transactions(to, amount, digits)
+			receipt, err := transactions.Transactions(cmd.Context(), client, to, value)
+			printReceipt(receipt)
+			return err
 		},
 	}
 
-	contractsCmd = &cobra.Command{
-		Use:   "contracts",
-		Short: "合约操作",
-		Long:  "部署和调用智能合约",
-		Run: func(cmd *cobra.Command, args []string) {
+	// transactionsSpeedupCmd 加速卡住的交易
+	transactionsSpeedupCmd = &cobra.Command{
+		Use:   "speedup",
+		Short: "加速卡住的交易",
+		Long:  "使用相同的 nonce 和更高的手续费重新广播交易, 交易内容不变, 然后等待原交易或替换交易被打包",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return replaceTransaction(cmd, transactions.SpeedUp)
 		},
 	}
 
-	contractsDeployCmd = &cobra.Command{
-		Use:   "deploy",
-		Short: "部署合约",
-		Long:  "部署智能合约到以太坊网络",
-		Run: func(cmd *cobra.Command, args []string) {
-			path, err := cmd.Flags().GetString("path")
+	// transactionsCancelCmd 取消卡住的交易
+	transactionsCancelCmd = &cobra.Command{
+		Use:   "cancel",
+		Short: "取消卡住的交易",
+		Long:  "使用相同的 nonce 和更高的手续费向自己发送 0 金额的交易, 然后等待原交易或取消交易被打包",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return replaceTransaction(cmd, transactions.Cancel)
+		},
+	}
+
+	// txCmd 离线签名: 联网机器构建交易, 离线机器签名, 任意联网机器广播
+	txCmd = &cobra.Command{
+		Use:   "tx",
+		Short: "离线签名交易",
+		Long:  "分三步发送交易: tx build 在联网机器上构建未签名交易, tx sign 在离线机器上签名, tx broadcast 在任意联网机器上广播",
+	}
+
+	txBuildCmd = &cobra.Command{
+		Use:   "build",
+		Short: "构建未签名交易",
+		Long:  "连接网络获取 nonce、手续费和链ID, 构建未签名交易并输出为 JSON 文件",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			request, err := loadBuildRequest(cmd)
 			if err != nil {
-				log.Fatal("合约地址文件位置获取异常: ", err)
+				return err
 			}
-			cs := contracts.NewContractService(path)
-			defer cs.Close()
-			redeploy, err := cmd.Flags().GetBool("redeploy")
+			out, err := cmd.Flags().GetString("out")
 			if err != nil {
-				log.Fatal("获取重新部署参数异常: ", err)
+				return fmt.Errorf("获取输出文件参数错误: %w", err)
 			}
-			if !redeploy && cs.Contracts != nil {
-				log.Printf("已经加载了历史部署合约: %s, 如需重新部署请添加 --redeploy 参数\n", cs.Address)
-				return
+
+			client, err := loadClient(cmd)
+			if err != nil {
+				return err
 			}
-			if redeploy {
-				cs = cs.SetReDeploy()
+			defer client.Close()
+
+			offline, err := transactions.Build(cmd.Context(), client, request)
+			if err != nil {
+				return err
 			}
-			cs.Deploy()
+			return offline.Write(out)
 		},
 	}
-	contractsCallCmd = &cobra.Command{
-		Use:   "call",
-		Short: "调用合约",
-		Long:  "调用已部署的智能合约",
-		Run: func(cmd *cobra.Command, args []string) {
-			method, err := cmd.Flags().GetString("method")
+
+	txSignCmd = &cobra.Command{
+		Use:   "sign",
+		Short: "离线签名交易",
+		Long:  "使用 keystore 账户签名 tx build 生成的交易文件, 不连接网络",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			in, err := cmd.Flags().GetString("in")
 			if err != nil {
-				log.Fatal("获取合约方法参数异常: ", err)
+				return fmt.Errorf("获取输入文件参数错误: %w", err)
 			}
-			method = strings.ToLower(method)
-			path, err := cmd.Flags().GetString("path")
+			out, err := cmd.Flags().GetString("out")
 			if err != nil {
-				log.Fatal("合约地址文件位置获取异常: ", err)
+				return fmt.Errorf("获取输出文件参数错误: %w", err)
 			}
-			cs := contracts.NewContractService(path)
-			defer cs.Close()
-			switch method {
+			offline, err := transactions.ReadOfflineTx(in)
+			if err != nil {
+				return err
+			}
+			// 未指定 --from 时使用交易文件中的发送方
+			if account.CurrentConfig().From == "" {
+				config := account.CurrentConfig()
+				config.From = offline.From.Hex()
+				account.SetConfig(config)
+			}
+			signer, err := account.LoadSigner(cmd.Context())
+			if err != nil {
+				return err
+			}
+			if err := offline.Sign(cmd.Context(), signer); err != nil {
+				return err
+			}
+			return offline.Write(out)
+		},
+	}
+
+	txBroadcastCmd = &cobra.Command{
+		Use:   "broadcast",
+		Short: "广播签名交易",
+		Long:  "广播 tx sign 生成的交易文件或 RLP 十六进制编码的签名交易, 并等待确认",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			in, err := cmd.Flags().GetString("in")
+			if err != nil {
+				return fmt.Errorf("获取输入文件参数错误: %w", err)
+			}
+			raw, err := cmd.Flags().GetString("raw")
+			if err != nil {
+				return fmt.Errorf("获取签名交易参数错误: %w", err)
+			}
+			var tx *types.Transaction
+			if raw != "" {
+				tx, err = transactions.ParseRawTx(raw)
+			} else {
+				tx, err = transactions.LoadSignedTx(in)
+			}
+			if err != nil {
+				return err
+			}
+
+			client, err := loadClient(cmd)
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+
+			receipt, err := transactions.Broadcast(cmd.Context(), client, tx)
+			printReceipt(receipt)
+			return err
+		},
+	}
+
+	// accountCmd 管理加密的 keystore 账户
+	accountCmd = &cobra.Command{
+		Use:     "account",
+		Aliases: []string{"accounts"},
+		Short:   "管理签名账户",
+		Long:    "管理 keystore 中加密保存的签名账户和助记词, 其他命令通过 --from 或 --account-index 选择账户",
+	}
+
+	accountNewCmd = &cobra.Command{
+		Use:   "new",
+		Short: "创建账户",
+		Long:  "生成新的随机私钥, 使用密码加密后保存到 keystore",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			passphrase, err := account.Passphrase("请设置账户密码: ", true)
+			if err != nil {
+				return err
+			}
+			acct, err := account.New(passphrase)
+			if err != nil {
+				return err
+			}
+			log.Printf("账户地址: %s\n", acct.Address.Hex())
+			return nil
+		},
+	}
+
+	accountImportCmd = &cobra.Command{
+		Use:   "import",
+		Short: "导入账户",
+		Long:  "将十六进制私钥、其他工具导出的 keystore 文件或环境文件中的 PRIVATE_KEY 加密后导入 keystore",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			keyFile, err := cmd.Flags().GetString("key-file")
+			if err != nil {
+				return fmt.Errorf("获取私钥文件参数错误: %w", err)
+			}
+			fromEnv, err := cmd.Flags().GetBool("from-env")
+			if err != nil {
+				return fmt.Errorf("获取导入来源参数错误: %w", err)
+			}
+
+			var hexKey string
+			switch {
+			case fromEnv:
+				hexKey = util.LoadEnv("<PRIVATE_KEY>")
+			case keyFile != "":
+				data, err := os.ReadFile(keyFile)
+				if err != nil {
+					return fmt.Errorf("%w: 读取私钥文件失败: %w", util.ErrInvalidArgument, err)
+				}
+				// keystore JSON 使用原密码解密后以新密码重新加密
+				if strings.HasPrefix(strings.TrimSpace(string(data)), "{") {
+					passphrase, err := account.Passphrase("请输入 keystore 文件的密码: ", false)
+					if err != nil {
+						return err
+					}
+					newPassphrase, err := account.Passphrase("请设置账户密码: ", true)
+					if err != nil {
+						return err
+					}
+					acct, err := account.ImportJSON(data, passphrase, newPassphrase)
+					if err != nil {
+						return err
+					}
+					log.Printf("账户地址: %s\n", acct.Address.Hex())
+					return nil
+				}
+				hexKey = string(data)
+			default:
+				if hexKey, err = prompt.Stdin.PromptPassword("请输入十六进制私钥: "); err != nil {
+					return fmt.Errorf("%w: 读取私钥失败: %w", util.ErrInvalidArgument, err)
+				}
+			}
+			key, err := crypto.HexToECDSA(strings.TrimPrefix(strings.TrimSpace(hexKey), "0x"))
+			if err != nil {
+				return fmt.Errorf("%w: 私钥解析失败: %w", util.ErrInvalidArgument, err)
+			}
+			passphrase, err := account.Passphrase("请设置账户密码: ", true)
+			if err != nil {
+				return err
+			}
+			acct, err := account.ImportKey(key, passphrase)
+			if err != nil {
+				return err
+			}
+			log.Printf("账户地址: %s\n", acct.Address.Hex())
+			if fromEnv {
+				log.Printf("请从环境文件中删除 PRIVATE_KEY, 之后通过 --from %s 或环境文件 FROM 选择该账户\n", acct.Address.Hex())
+			}
+			return nil
+		},
+	}
+
+	accountListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "列出账户",
+		Long:  "列出 keystore 中的全部账户",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			accts, err := account.List()
+			if err != nil {
+				return err
+			}
+			if len(accts) == 0 {
+				log.Println("keystore 中没有账户, 请执行 account new 或 account import")
+				return nil
+			}
+			for i, acct := range accts {
+				log.Printf("#%d %s %s\n", i, acct.Address.Hex(), acct.URL.Path)
+			}
+			return nil
+		},
+	}
+
+	accountExportCmd = &cobra.Command{
+		Use:   "export",
+		Short: "导出账户",
+		Long:  "导出 --from 指定账户的加密 keystore 文件, 可以导入 geth、MetaMask 等工具",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out, err := cmd.Flags().GetString("out")
+			if err != nil {
+				return fmt.Errorf("获取输出文件参数错误: %w", err)
+			}
+			address, err := account.Address(cmd.Context())
+			if err != nil {
+				return err
+			}
+			passphrase, err := account.Passphrase(fmt.Sprintf("请输入账户 %s 的密码: ", address.Hex()), false)
+			if err != nil {
+				return err
+			}
+			keyJSON, err := account.Export(address, passphrase, passphrase)
+			if err != nil {
+				return err
+			}
+			if out == "" || out == "-" {
+				_, err = fmt.Println(string(keyJSON))
+				return err
+			}
+			if err := os.WriteFile(out, keyJSON, 0600); err != nil {
+				return fmt.Errorf("写入 keystore 文件失败: %w", err)
+			}
+			log.Printf("账户 %s 已导出到 %s\n", address.Hex(), out)
+			return nil
+		},
+	}
+
+	accountDeriveCmd = &cobra.Command{
+		Use:   "derive",
+		Short: "列出助记词派生的账户",
+		Long:  "按 BIP-44 路径 (默认 m/44'/60'/0'/0/i) 从助记词派生账户并列出地址, 签名时通过 --account-index 选择",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			count, err := cmd.Flags().GetUint32("count")
+			if err != nil {
+				return fmt.Errorf("获取账户数量参数错误: %w", err)
+			}
+			start, err := cmd.Flags().GetUint32("start")
+			if err != nil {
+				return fmt.Errorf("获取起始索引参数错误: %w", err)
+			}
+			derived, err := account.Derive(start, count)
+			if err != nil {
+				return err
+			}
+			for _, acct := range derived {
+				log.Printf("#%d %s %s\n", acct.Index, acct.Address.Hex(), acct.Path)
+			}
+			return nil
+		},
+	}
+
+	accountMnemonicCmd = &cobra.Command{
+		Use:   "mnemonic",
+		Short: "管理助记词",
+		Long:  "生成或导入 BIP-39 助记词, 使用密码加密后保存到 keystore 目录",
+	}
+
+	accountMnemonicNewCmd = &cobra.Command{
+		Use:   "new",
+		Short: "生成助记词",
+		Long:  "生成新的 BIP-39 英文助记词并加密保存, 助记词只输出一次, 请抄写备份",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			words, err := cmd.Flags().GetInt("words")
+			if err != nil {
+				return fmt.Errorf("获取助记词单词数参数错误: %w", err)
+			}
+			mnemonic, err := account.NewMnemonic(words)
+			if err != nil {
+				return err
+			}
+			password, err := account.Passphrase("请设置助记词密码: ", true)
+			if err != nil {
+				return err
+			}
+			if _, err := account.SaveMnemonic(mnemonic, password); err != nil {
+				return err
+			}
+			log.Println("请抄写并妥善保存以下助记词, 之后不会再次显示:")
+			fmt.Println(mnemonic)
+			return nil
+		},
+	}
+
+	accountMnemonicImportCmd = &cobra.Command{
+		Use:   "import",
+		Short: "导入助记词",
+		Long:  "导入已有的 BIP-39 助记词或环境文件中的 MNEMONIC, 使用密码加密后保存",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mnemonicFile, err := cmd.Flags().GetString("mnemonic-file")
+			if err != nil {
+				return fmt.Errorf("获取助记词文件参数错误: %w", err)
+			}
+			fromEnv, err := cmd.Flags().GetBool("from-env")
+			if err != nil {
+				return fmt.Errorf("获取导入来源参数错误: %w", err)
+			}
+
+			var mnemonic string
+			switch {
+			case fromEnv:
+				mnemonic = util.LoadEnv("<MNEMONIC>")
+			case mnemonicFile != "":
+				data, err := os.ReadFile(mnemonicFile)
+				if err != nil {
+					return fmt.Errorf("%w: 读取助记词文件失败: %w", util.ErrInvalidArgument, err)
+				}
+				mnemonic = string(data)
+			default:
+				// 助记词不回显, 与密码使用相同的输入方式
+				if mnemonic, err = prompt.Stdin.PromptPassword("请输入助记词: "); err != nil {
+					return fmt.Errorf("%w: 读取助记词失败: %w", util.ErrInvalidArgument, err)
+				}
+			}
+			if mnemonic, err = account.NormalizeMnemonic(mnemonic); err != nil {
+				return err
+			}
+			password, err := account.Passphrase("请设置助记词密码: ", true)
+			if err != nil {
+				return err
+			}
+			if _, err := account.SaveMnemonic(mnemonic, password); err != nil {
+				return err
+			}
+			if fromEnv {
+				log.Println("请从环境文件中删除 MNEMONIC, 之后通过 --account-index 选择派生账户")
+			}
+			return nil
+		},
+	}
+
+	contractsCmd = &cobra.Command{
+		Use:   "contracts",
+		Short: "合约操作",
+		Long:  "部署和调用计数器合约, 管理按链ID和合约名保存的部署记录",
+	}
+
+	contractsDeployCmd = &cobra.Command{
+		Use:   "deploy",
+		Short: "部署合约",
+		Long:  "部署智能合约到以太坊网络",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			redeploy, err := cmd.Flags().GetBool("redeploy")
+			if err != nil {
+				return fmt.Errorf("获取重新部署参数异常: %w", err)
+			}
+			deployments, err := loadDeployments(cmd)
+			if err != nil {
+				return err
+			}
+
+			client, err := loadClient(cmd)
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+
+			cs, err := contracts.NewContractService(cmd.Context(), client, deployments)
+			if err != nil {
+				return err
+			}
+			if !redeploy && cs.Contracts != nil {
+				log.Printf("已经加载了历史部署合约: %s, 如需重新部署请添加 --redeploy 参数\n", cs.Address)
+				return nil
+			}
+			if redeploy {
+				cs = cs.SetReDeploy()
+			}
+			receipt, err := cs.Deploy(cmd.Context())
+			printReceipt(receipt)
+			if err != nil {
+				return err
+			}
+			if network, err := util.CurrentNetwork(); err == nil {
+				if addressURL := network.AddressURL(cs.Address.Hex()); addressURL != "" {
+					log.Printf("合约浏览器地址: %s\n", addressURL)
+				}
+			}
+			return nil
+		},
+	}
+	contractsCallCmd = &cobra.Command{
+		Use:   "call",
+		Short: "调用合约",
+		Long:  "调用已部署的智能合约",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			method, err := cmd.Flags().GetString("method")
+			if err != nil {
+				return fmt.Errorf("获取合约方法参数异常: %w", err)
+			}
+			method = strings.ToLower(method)
+			if method != "count" && method != "increment" {
+				return fmt.Errorf("%w: 无效的合约方法: %s", util.ErrInvalidArgument, method)
+			}
+			deployments, err := loadDeployments(cmd)
+			if err != nil {
+				return err
+			}
+
+			client, err := loadClient(cmd)
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+
+			cs, err := contracts.NewContractService(cmd.Context(), client, deployments)
+			if err != nil {
+				return err
+			}
+			switch method {
 			case "count":
-				cs.Count()
+				count, err := cs.Count(cmd.Context())
+				if err != nil {
+					return err
+				}
+				log.Println("Count: ", count)
 			case "increment":
-				cs.Increment()
-			default:
-				log.Fatal("无效的合约方法: ", method)
+				receipt, err := cs.Increment(cmd.Context())
+				printReceipt(receipt)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+
+	contractsListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "列出部署记录",
+		Long:  "列出当前网络 (--all 时为所有链) 的合约部署记录; --all 或网络配置了链ID时不连接节点, 否则连接节点查询链ID",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			all, err := cmd.Flags().GetBool("all")
+			if err != nil {
+				return fmt.Errorf("获取 all 参数错误: %w", err)
+			}
+			deployments, err := loadDeployments(cmd)
+			if err != nil {
+				return err
+			}
+			var chainID *big.Int
+			if !all {
+				if chainID, err = deploymentChainID(cmd); err != nil {
+					return err
+				}
+			}
+			records, err := deployments.List(chainID)
+			if err != nil {
+				return err
+			}
+			if outputFormat == formatJSON {
+				out := make([]deploymentJSON, len(records))
+				for i, record := range records {
+					// 列表中不输出 ABI, 通过 contracts show 查看
+					record.ABI = nil
+					out[i] = deploymentJSON{ChainID: record.ChainID, Name: record.Name, Deployment: record}
+				}
+				printJSON(out)
+				return nil
+			}
+			if len(records) == 0 {
+				log.Printf("部署记录 %s 中没有合约", deployments.Path())
+				return nil
+			}
+			log.Printf("部署记录 %s:", deployments.Path())
+			for _, record := range records {
+				if record.TxHash == (common.Hash{}) {
+					log.Printf("  [链 %s] %-20s %s 从旧合约地址文件导入", record.ChainID, record.Name, record.Address.Hex())
+					continue
+				}
+				log.Printf("  [链 %s] %-20s %s 区块 %d 部署账户 %s 时间 %s", record.ChainID, record.Name, record.Address.Hex(),
+					record.Block, record.Deployer.Hex(), record.Time.Local().Format(time.DateTime))
+			}
+			return nil
+		},
+	}
+
+	contractsShowCmd = &cobra.Command{
+		Use:   "show <合约名>",
+		Short: "查看部署记录",
+		Long:  "查看当前网络上合约的部署记录, 并检查地址上的合约代码是否与部署时一致",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			exportABI, err := cmd.Flags().GetString("export-abi")
+			if err != nil {
+				return fmt.Errorf("获取 ABI 导出文件参数错误: %w", err)
+			}
+			deployments, err := loadDeployments(cmd)
+			if err != nil {
+				return err
+			}
+			chainID, err := deploymentChainID(cmd)
+			if err != nil {
+				return err
+			}
+			record, err := deployments.Lookup(chainID, args[0])
+			if err != nil {
+				return err
+			}
+			if exportABI != "" {
+				var abiJSON bytes.Buffer
+				if err := json.Indent(&abiJSON, record.ABI, "", "  "); err != nil {
+					return fmt.Errorf("%w: 合约 %s 的部署记录中没有有效的 ABI", util.ErrConfig, record.Name)
+				}
+				if err := os.WriteFile(exportABI, abiJSON.Bytes(), 0644); err != nil {
+					return fmt.Errorf("导出 ABI 失败: %w", err)
+				}
+				log.Printf("已将合约 %s 的 ABI 导出到 %s", record.Name, exportABI)
+			}
+
+			client, err := loadClient(cmd)
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+			checkErr := record.Check(cmd.Context(), client)
+
+			if outputFormat == formatJSON {
+				printJSON(deploymentJSON{ChainID: record.ChainID, Name: record.Name, Deployment: record})
+				return checkErr
+			}
+			log.Printf("合约: %s (链 %s)", record.Name, record.ChainID)
+			log.Printf("地址: %s", record.Address.Hex())
+			if network, err := util.CurrentNetwork(); err == nil {
+				if addressURL := network.AddressURL(record.Address.Hex()); addressURL != "" {
+					log.Printf("浏览器: %s", addressURL)
+				}
+			}
+			log.Printf("部署交易: %s (区块 %d)", record.TxHash.Hex(), record.Block)
+			log.Printf("部署账户: %s", record.Deployer.Hex())
+			log.Printf("部署时间: %s", record.Time.Local().Format(time.DateTime))
+			log.Printf("代码哈希: %s", record.CodeHash.Hex())
+			if record.Implementation != nil {
+				log.Printf("实现合约: %s", record.Implementation.Hex())
+			}
+			if contractABI, err := record.ParseABI(); err == nil {
+				log.Printf("ABI: %d 个方法, %d 个事件, %d 个自定义错误", len(contractABI.Methods), len(contractABI.Events), len(contractABI.Errors))
+			}
+			if checkErr != nil {
+				return checkErr
+			}
+			log.Printf("链上代码: 与部署记录一致")
+			return nil
+		},
+	}
+
+	contractsForgetCmd = &cobra.Command{
+		Use:   "forget <合约名>",
+		Short: "删除部署记录",
+		Long:  "删除当前网络上合约的部署记录, 例如本地开发链重置后, 链上的合约不受影响",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			deployments, err := loadDeployments(cmd)
+			if err != nil {
+				return err
+			}
+			chainID, err := deploymentChainID(cmd)
+			if err != nil {
+				return err
+			}
+			if err := deployments.Forget(chainID, args[0]); err != nil {
+				return err
+			}
+			log.Printf("已删除链 %s 上合约 %s 的部署记录", chainID, args[0])
+			return nil
+		},
+	}
+
+	contractCmd = &cobra.Command{
+		Use:   "contract",
+		Short: "按 ABI 调用任意合约",
+		Long:  "通过 --abi 指定的 ABI 文件 (ABI 数组或 Hardhat / Foundry 编译产物) 调用任意已部署的合约, 不需要生成绑定代码",
+	}
+
+	contractCallCmd = &cobra.Command{
+		Use:   "call",
+		Short: "调用合约的只读方法",
+		Long:  "通过 eth_call 调用合约方法并解码返回值, 不发送交易; msg.sender 为当前签名账户",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := loadClient(cmd)
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+
+			contract, method, methodArgs, err := loadContractMethod(cmd, client)
+			if err != nil {
+				return err
+			}
+			var block *big.Int
+			if cmd.Flags().Changed("block") {
+				number, err := cmd.Flags().GetUint64("block")
+				if err != nil {
+					return fmt.Errorf("获取区块参数错误: %w", err)
+				}
+				block = new(big.Int).SetUint64(number)
+			}
+			// 部分只读方法依赖 msg.sender, 没有可用账户时使用零地址
+			from, err := account.Address(cmd.Context())
+			if err != nil {
+				log.Printf("无法确定调用账户, msg.sender 使用零地址: %v", err)
+			}
+			outputs, err := contract.Call(cmd.Context(), from, method, methodArgs, block)
+			if err != nil {
+				return err
+			}
+			printOutputs(method, outputs)
+			return nil
+		},
+	}
+
+	contractSendCmd = &cobra.Command{
+		Use:   "send",
+		Short: "发送调用合约方法的交易",
+		Long:  "编码方法调用, 签名并发送交易, 等待确认后输出收据和解码后的事件",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			amount, err := cmd.Flags().GetString("value")
+			if err != nil {
+				return fmt.Errorf("获取金额参数错误: %w", err)
+			}
+			value, err := util.ParseAmount(amount, "ether")
+			if err != nil {
+				return err
+			}
+
+			client, err := loadClient(cmd)
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+
+			contract, method, methodArgs, err := loadContractMethod(cmd, client)
+			if err != nil {
+				return err
+			}
+			receipt, err := contract.Send(cmd.Context(), method, methodArgs, value)
+			printReceipt(receipt)
+			return err
+		},
+	}
+
+	contractDeployCmd = &cobra.Command{
+		Use:   "deploy",
+		Short: "部署任意合约",
+		Long:  "按 --abi 指定的编译产物或 --abi + --bin 部署合约, 支持构造函数参数、payable 金额和库链接, 部署后保存到部署记录",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			abiFiles, err := cmd.Flags().GetStringSlice("abi")
+			if err != nil {
+				return fmt.Errorf("获取 ABI 文件参数错误: %w", err)
+			}
+			if len(abiFiles) == 0 {
+				return fmt.Errorf("%w: 需要通过 --abi 指定合约的 ABI 文件或编译产物", util.ErrInvalidArgument)
+			}
+			binFile, err := cmd.Flags().GetString("bin")
+			if err != nil {
+				return fmt.Errorf("获取字节码文件参数错误: %w", err)
+			}
+			artifact, err := contracts.LoadArtifact(strings.TrimSpace(abiFiles[0]), binFile)
+			if err != nil {
+				return err
+			}
+			name, err := cmd.Flags().GetString("name")
+			if err != nil {
+				return fmt.Errorf("获取合约名参数错误: %w", err)
+			}
+			if name == "" {
+				name = artifact.Name
+			}
+			constructorArgs, err := cmd.Flags().GetStringArray("args")
+			if err != nil {
+				return fmt.Errorf("获取构造函数参数错误: %w", err)
+			}
+			amount, err := cmd.Flags().GetString("value")
+			if err != nil {
+				return fmt.Errorf("获取金额参数错误: %w", err)
+			}
+			value, err := util.ParseAmount(amount, "ether")
+			if err != nil {
+				return err
+			}
+			libs, err := cmd.Flags().GetStringArray("lib")
+			if err != nil {
+				return fmt.Errorf("获取库地址参数错误: %w", err)
+			}
+			libraries, err := parseLibraries(libs)
+			if err != nil {
+				return err
+			}
+			deployments, err := loadDeployments(cmd)
+			if err != nil {
+				return err
+			}
+
+			client, err := loadClient(cmd)
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+
+			deployment, receipt, err := contracts.Deploy(cmd.Context(), client, artifact, libraries, constructorArgs, value)
+			printReceipt(receipt)
+			if err != nil {
+				return err
+			}
+			chainID, err := client.ChainID(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("获取链ID失败: %w", errs.Classify(err))
+			}
+			if err := deployments.Record(chainID, name, deployment); err != nil {
+				return fmt.Errorf("保存部署记录失败: %w", err)
+			}
+			log.Printf("合约 %s 已部署到 %s, 已保存到部署记录 (链ID %s)", name, deployment.Address.Hex(), chainID)
+			if network, err := util.CurrentNetwork(); err == nil {
+				if addressURL := network.AddressURL(deployment.Address.Hex()); addressURL != "" {
+					log.Printf("合约浏览器地址: %s\n", addressURL)
+				}
+			}
+			return nil
+		},
+	}
+
+	auctionCmd = &cobra.Command{
+		Use:   "auction",
+		Short: "NFT 拍卖",
+		Long:  "操作 solidity/task3 的 NFTAuction 拍卖合约, 合约地址默认从部署记录中按合约名 " + auction.AuctionName + " 查找",
+	}
+
+	auctionCreateCmd = &cobra.Command{
+		Use:   "create",
+		Short: "创建拍卖",
+		Long:  "将 NFT 转入拍卖合约并创建拍卖, 拍卖合约没有该 NFT 的授权时先发送 approve 交易",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			nftTarget, err := cmd.Flags().GetString("nft")
+			if err != nil {
+				return fmt.Errorf("获取 NFT 合约参数错误: %w", err)
+			}
+			tokenIDValue, err := cmd.Flags().GetString("token-id")
+			if err != nil {
+				return fmt.Errorf("获取 token ID 参数错误: %w", err)
+			}
+			tokenID, err := parseUint256("token ID", tokenIDValue)
+			if err != nil {
+				return err
+			}
+			price, err := cmd.Flags().GetString("price")
+			if err != nil {
+				return fmt.Errorf("获取起始价格参数错误: %w", err)
+			}
+			usdc, err := cmd.Flags().GetBool("usdc")
+			if err != nil {
+				return fmt.Errorf("获取 USDC 参数错误: %w", err)
+			}
+			duration, err := cmd.Flags().GetDuration("duration")
+			if err != nil {
+				return fmt.Errorf("获取拍卖时长参数错误: %w", err)
+			}
+			deployments, err := loadDeployments(cmd)
+			if err != nil {
+				return err
+			}
+
+			client, err := loadClient(cmd)
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+
+			service, err := loadAuction(cmd, client)
+			if err != nil {
+				return err
+			}
+			nft, _, err := deployments.Resolve(cmd.Context(), client, nftTarget)
+			if err != nil {
+				return err
+			}
+			startingPrice, err := parseBidAmount(cmd, service, price, usdc)
+			if err != nil {
+				return err
+			}
+			receipt, id, err := service.Create(cmd.Context(), nft, tokenID, startingPrice, usdc, duration)
+			printReceipt(receipt)
+			if err != nil {
+				return err
+			}
+			log.Printf("已创建拍卖 %s", id)
+			return nil
+		},
+	}
+
+	auctionBidCmd = &cobra.Command{
+		Use:   "bid <拍卖ID>",
+		Short: "出价",
+		Long:  "使用 ETH 或 USDC 对拍卖出价, 出价按预言机价格换算为 USD 后需要高于当前最高出价; USDC 授权额度不足时先发送 approve 交易",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := parseUint256("拍卖 ID", args[0])
+			if err != nil {
+				return err
+			}
+			value, err := cmd.Flags().GetString("amount")
+			if err != nil {
+				return fmt.Errorf("获取出价金额参数错误: %w", err)
+			}
+			usdc, err := cmd.Flags().GetBool("usdc")
+			if err != nil {
+				return fmt.Errorf("获取 USDC 参数错误: %w", err)
+			}
+
+			client, err := loadClient(cmd)
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+
+			service, err := loadAuction(cmd, client)
+			if err != nil {
+				return err
+			}
+			amount, err := parseBidAmount(cmd, service, value, usdc)
+			if err != nil {
+				return err
+			}
+			receipt, err := service.Bid(cmd.Context(), id, amount, usdc)
+			printReceipt(receipt)
+			return err
+		},
+	}
+
+	auctionEndCmd = &cobra.Command{
+		Use:   "end <拍卖ID>",
+		Short: "结束拍卖",
+		Long:  "拍卖到期后结束拍卖, 将 NFT 转给最高出价者, 扣除手续费后将出价转给卖家; 没有出价时 NFT 退还卖家",
+		Args:  cobra.ExactArgs(1),
+		RunE:  auctionRunE((*auction.Service).End),
+	}
+
+	auctionCancelCmd = &cobra.Command{
+		Use:   "cancel <拍卖ID>",
+		Short: "取消拍卖",
+		Long:  "卖家在拍卖到期前取消拍卖, 退还最高出价并将 NFT 转回卖家",
+		Args:  cobra.ExactArgs(1),
+		RunE:  auctionRunE((*auction.Service).Cancel),
+	}
+
+	auctionShowCmd = &cobra.Command{
+		Use:   "show <拍卖ID>",
+		Short: "查看拍卖",
+		Long:  "查看拍卖的 NFT、卖家、起始价格、最高出价和状态, 状态按最新区块时间计算",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := parseUint256("拍卖 ID", args[0])
+			if err != nil {
+				return err
+			}
+
+			client, err := loadClient(cmd)
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+
+			service, err := loadAuction(cmd, client)
+			if err != nil {
+				return err
+			}
+			info, err := service.Show(cmd.Context(), id)
+			if err != nil {
+				return err
+			}
+			if outputFormat == formatJSON {
+				printJSON(info)
+				return nil
+			}
+			return printAuctions(cmd, service, []*auction.Auction{info})
+		},
+	}
+
+	auctionListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "列出拍卖",
+		Long:  "按 ID 顺序列出拍卖合约中的所有拍卖",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			status, err := cmd.Flags().GetString("status")
+			if err != nil {
+				return fmt.Errorf("获取状态参数错误: %w", err)
+			}
+			if status != "" && !slices.Contains(auction.Statuses(), status) {
+				return fmt.Errorf("%w: 未知的拍卖状态 %q, 可选: %s", util.ErrInvalidArgument, status, strings.Join(auction.Statuses(), ", "))
+			}
+
+			client, err := loadClient(cmd)
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+
+			service, err := loadAuction(cmd, client)
+			if err != nil {
+				return err
+			}
+			auctions, err := service.List(cmd.Context(), status)
+			if err != nil {
+				return err
+			}
+			if outputFormat == formatText && len(auctions) == 0 {
+				log.Printf("拍卖合约 %s 中没有拍卖", service.Address.Hex())
+				return nil
+			}
+			return printAuctions(cmd, service, auctions)
+		},
+	}
+
+	auctionFeesCmd = &cobra.Command{
+		Use:   "fees",
+		Short: "查看手续费",
+		Long:  "查看手续费比例和累计未提取的 ETH / USDC 手续费",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := loadClient(cmd)
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+
+			service, err := loadAuction(cmd, client)
+			if err != nil {
+				return err
+			}
+			fees, err := service.Fees(cmd.Context())
+			if err != nil {
+				return err
+			}
+			if outputFormat == formatJSON {
+				printJSON(fees)
+				return nil
+			}
+			log.Printf("合约所有者: %s", fees.Owner.Hex())
+			if fees.FeeRateBase.Sign() > 0 {
+				log.Printf("手续费比例: %s%% (%s / %s)", new(big.Rat).SetFrac(new(big.Int).Mul(fees.FeeRate, big.NewInt(100)), fees.FeeRateBase).FloatString(2), fees.FeeRate, fees.FeeRateBase)
+			}
+			log.Printf("未提取的 ETH 手续费: %s ETH", util.FormatUnits(fees.TotalFeesETH, 18))
+			if fees.USDC == (common.Address{}) {
+				log.Printf("未提取的 USDC 手续费: %s", fees.TotalFeesUSDC)
+				return nil
+			}
+			_, decimals, err := service.USDC(cmd.Context())
+			if err != nil {
+				return err
+			}
+			log.Printf("未提取的 USDC 手续费: %s USDC (%s)", util.FormatUnits(fees.TotalFeesUSDC, int(decimals)), fees.USDC.Hex())
+			return nil
+		},
+	}
+
+	auctionSetFeeCmd = &cobra.Command{
+		Use:   "set-fee <手续费比例>",
+		Short: "设置手续费比例",
+		Long:  "设置拍卖成交时收取的手续费比例, 以 10000 为基数 (250 表示 2.5%), 最高 1000 (10%), 只有合约所有者可以调用",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			feeRate, err := parseUint256("手续费比例", args[0])
+			if err != nil {
+				return err
+			}
+
+			client, err := loadClient(cmd)
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+
+			service, err := loadAuction(cmd, client)
+			if err != nil {
+				return err
+			}
+			receipt, err := service.SetFeeRate(cmd.Context(), feeRate)
+			printReceipt(receipt)
+			return err
+		},
+	}
+
+	auctionWithdrawFeesCmd = &cobra.Command{
+		Use:   "withdraw-fees",
+		Short: "提取手续费",
+		Long:  "将累计的 ETH 和 USDC 手续费提取到合约所有者, 只有合约所有者可以调用",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := loadClient(cmd)
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+
+			service, err := loadAuction(cmd, client)
+			if err != nil {
+				return err
+			}
+			receipt, err := service.WithdrawFees(cmd.Context())
+			printReceipt(receipt)
+			return err
+		},
+	}
+
+	// proxyCmd UUPS 代理合约的部署和升级命令
+	proxyCmd = &cobra.Command{
+		Use:   "proxy",
+		Short: "部署和升级 UUPS 代理合约",
+		Long: "部署 UUPS 实现合约和 ERC1967 代理并调用初始化方法, 通过 upgradeToAndCall 升级代理;\n" +
+			"部署记录保存实现合约地址和存储布局, 升级前检查新实现合约的存储布局是否兼容",
+	}
+
+	proxyDeployCmd = &cobra.Command{
+		Use:   "deploy",
+		Short: "部署实现合约和代理",
+		Long: "部署 --abi 指定的实现合约, 再部署 ERC1967 代理并在代理构造函数中调用 --initializer 方法, 与 Hardhat 的 upgrades.deployProxy 相同;\n" +
+			"部署记录中保存代理地址、实现合约的 ABI 和存储布局, 之后可以按合约名通过代理调用",
+		Example: "  task1 proxy deploy --abi artifacts/contracts/NFTAuction.sol/NFTAuction.json --initializer __NFTAuction_init --args 0x...",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			artifact, libraries, value, layout, err := loadImplementation(cmd, false)
+			if err != nil {
+				return err
+			}
+			name, err := cmd.Flags().GetString("name")
+			if err != nil {
+				return fmt.Errorf("获取合约名参数错误: %w", err)
+			}
+			if name == "" {
+				name = artifact.Name
+			}
+			initializer, err := cmd.Flags().GetString("initializer")
+			if err != nil {
+				return fmt.Errorf("获取初始化方法参数错误: %w", err)
+			}
+			initArgs, err := cmd.Flags().GetStringArray("args")
+			if err != nil {
+				return fmt.Errorf("获取初始化方法参数错误: %w", err)
+			}
+			proxyFile, err := cmd.Flags().GetString("proxy-artifact")
+			if err != nil {
+				return fmt.Errorf("获取代理合约参数错误: %w", err)
+			}
+			proxyArtifact, err := proxy.Artifact(proxyFile)
+			if err != nil {
+				return err
+			}
+			deployments, err := loadDeployments(cmd)
+			if err != nil {
+				return err
+			}
+
+			client, err := loadClient(cmd)
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+
+			deployment, receipts, err := proxy.Deploy(cmd.Context(), client, proxy.DeployOptions{
+				Implementation: artifact,
+				Libraries:      libraries,
+				Proxy:          proxyArtifact,
+				Initializer:    initializer,
+				Args:           initArgs,
+				Value:          value,
+				Layout:         layout,
+			})
+			printReceipts(receipts)
+			if err != nil {
+				return err
+			}
+			chainID, err := client.ChainID(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("获取链ID失败: %w", errs.Classify(err))
+			}
+			if err := deployments.Record(chainID, name, deployment); err != nil {
+				return fmt.Errorf("保存部署记录失败: %w", err)
+			}
+			log.Printf("代理 %s 已部署到 %s, 实现合约 %s, 已保存到部署记录 (链ID %s)",
+				name, deployment.Address.Hex(), deployment.Implementation.Hex(), chainID)
+			return nil
+		},
+	}
+
+	proxyUpgradeCmd = &cobra.Command{
+		Use:   "upgrade <代理>",
+		Short: "升级代理的实现合约",
+		Long: "检查 --abi 指定的新实现合约的存储布局是否兼容当前实现合约, 部署新实现合约并通过代理调用 upgradeToAndCall, 与 Hardhat 的 upgrades.upgradeProxy 相同;\n" +
+			"<代理> 为代理地址或部署记录中的合约名, 使用合约名时升级后更新部署记录中的实现合约、ABI 和存储布局",
+		Example: "  task1 proxy upgrade NFTAuction --abi artifacts/contracts/NFTAuctionV2.sol/NFTAuctionV2.json --call __NFTAuctionV2_init",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			skip, err := cmd.Flags().GetBool("unsafe-skip-storage-check")
+			if err != nil {
+				return fmt.Errorf("获取存储布局检查参数错误: %w", err)
+			}
+			artifact, libraries, value, layout, err := loadImplementation(cmd, !skip)
+			if err != nil {
+				return err
+			}
+			call, err := cmd.Flags().GetString("call")
+			if err != nil {
+				return fmt.Errorf("获取升级调用方法参数错误: %w", err)
+			}
+			callArgs, err := cmd.Flags().GetStringArray("args")
+			if err != nil {
+				return fmt.Errorf("获取升级调用参数错误: %w", err)
+			}
+			oldLayoutFile, err := cmd.Flags().GetString("old-layout")
+			if err != nil {
+				return fmt.Errorf("获取存储布局参数错误: %w", err)
+			}
+			deployments, err := loadDeployments(cmd)
+			if err != nil {
+				return err
+			}
+
+			client, err := loadClient(cmd)
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+
+			address, deployment, err := deployments.Resolve(cmd.Context(), client, args[0])
+			if err != nil {
+				return err
+			}
+			if deployment == nil {
+				deployment = &contracts.Deployment{Address: address}
+			}
+			var oldLayout *proxy.StorageLayout
+			if oldLayoutFile != "" && !skip {
+				slots, err := proxy.ReadSlots(cmd.Context(), client, address)
+				if err != nil {
+					return err
+				}
+				if oldLayout, err = proxy.LoadLayout(oldLayoutFile, slots.Implementation); err != nil {
+					return err
+				}
+			}
+			receipts, err := proxy.Upgrade(cmd.Context(), client, deployment, proxy.UpgradeOptions{
+				Implementation: artifact,
+				Libraries:      libraries,
+				Call:           call,
+				Args:           callArgs,
+				Value:          value,
+				Layout:         layout,
+				OldLayout:      oldLayout,
+				SkipLayout:     skip,
+			})
+			printReceipts(receipts)
+			if err != nil {
+				return err
+			}
+			if deployment.Name == "" {
+				log.Printf("代理 %s 已升级, 实现合约 %s", address.Hex(), deployment.Implementation.Hex())
+				return nil
+			}
+			if err := deployments.Record(deployment.ChainID, deployment.Name, deployment); err != nil {
+				return fmt.Errorf("保存部署记录失败: %w", err)
+			}
+			log.Printf("代理 %s 已升级, 实现合约 %s, 已更新部署记录", deployment.Name, deployment.Implementation.Hex())
+			return nil
+		},
+	}
+
+	proxyShowCmd = &cobra.Command{
+		Use:   "show <代理>",
+		Short: "查看代理的实现合约和管理员",
+		Long:  "读取代理的 EIP-1967 存储槽 (实现合约、管理员、信标), 检查实现合约是否为 UUPS 合约, 以及是否与部署记录一致",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			deployments, err := loadDeployments(cmd)
+			if err != nil {
+				return err
+			}
+
+			client, err := loadClient(cmd)
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+
+			address, deployment, err := deployments.Resolve(cmd.Context(), client, args[0])
+			if err != nil {
+				return err
+			}
+			slots, err := proxy.ReadSlots(cmd.Context(), client, address)
+			if err != nil {
+				return err
+			}
+			if outputFormat == formatJSON {
+				printJSON(struct {
+					Proxy common.Address `json:"proxy"`
+					*proxy.Slots
+				}{address, slots})
+				return nil
+			}
+			log.Printf("代理: %s", address.Hex())
+			if slots.Implementation == (common.Address{}) {
+				return fmt.Errorf("%w: 地址 %s 不是 ERC1967 代理 (实现合约槽为空)", util.ErrInvalidArgument, address.Hex())
+			}
+			log.Printf("实现合约: %s", slots.Implementation.Hex())
+			if err := proxy.CheckUUPS(cmd.Context(), client, slots.Implementation); err != nil {
+				log.Printf("  %v", err)
+			} else {
+				log.Printf("  UUPS: proxiableUUID 检查通过")
+			}
+			if slots.Admin != (common.Address{}) {
+				log.Printf("管理员: %s", slots.Admin.Hex())
+			}
+			if slots.Beacon != (common.Address{}) {
+				log.Printf("信标: %s", slots.Beacon.Hex())
+			}
+			if deployment != nil && deployment.Implementation != nil && *deployment.Implementation != slots.Implementation {
+				log.Printf("部署记录中的实现合约为 %s, 代理可能在其他地方升级过", deployment.Implementation.Hex())
+			}
+			if deployment != nil && len(deployment.StorageLayout) > 0 {
+				if layout, err := proxy.ParseLayout(deployment.StorageLayout); err == nil {
+					log.Printf("存储布局: %d 个状态变量", len(layout.Storage))
+				}
+			}
+			return nil
+		},
+	}
+
+	// networksCmd 网络配置查看命令
+	networksCmd = &cobra.Command{
+		Use:   "networks",
+		Short: "查看网络配置",
+		Long:  "列出所有内置网络配置以及当前选中的网络 (可通过 <NAME>_HTTP_URLS 等环境变量覆盖)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			current, err := util.CurrentNetwork()
+			if err != nil {
+				return err
+			}
+			for _, name := range util.NetworkNames() {
+				network, err := util.LookupNetwork(name)
+				if err != nil {
+					return fmt.Errorf("加载网络配置失败: %w", err)
+				}
+				mark := " "
+				if network.Name == current.Name {
+					mark = "*"
+				}
+				log.Printf("%s %-13s 链ID: %-9d 代币: %s(%d) HTTP: %v WS: %v 浏览器: %s\n",
+					mark, network.Name, network.ChainID, network.Symbol, network.Decimals, network.HTTPURLs, network.WSURLs, network.Explorer)
+			}
+			// 通过环境变量自定义的网络不在内置列表中, 单独输出
+			if !slices.Contains(util.NetworkNames(), current.Name) {
+				log.Printf("* %-13s 链ID: %-9d 代币: %s(%d) HTTP: %v WS: %v 浏览器: %s\n",
+					current.Name, current.ChainID, current.Symbol, current.Decimals, current.HTTPURLs, current.WSURLs, current.Explorer)
+			}
+
+			check, err := cmd.Flags().GetBool("check")
+			if err != nil {
+				return fmt.Errorf("获取检查参数错误: %w", err)
+			}
+			if !check {
+				return nil
+			}
+			pool, err := util.NewNetworkPool(cmd.Context(), current, false)
+			if err != nil {
+				return fmt.Errorf("端点检查失败: %w", err)
+			}
+			defer pool.Close()
+			for _, status := range pool.Status() {
+				if status.Healthy {
+					log.Printf("[健康] %s 区块: %d 延迟: %s\n", status.URL, status.Head, status.Latency)
+				} else {
+					log.Printf("[异常] %s 错误: %v\n", status.URL, status.Err)
+				}
 			}
+			return nil
 		},
 	}
 
@@ -202,12 +2299,13 @@ var (
 		Use:   "env-template",
 		Short: "生成环境变量模板文件",
 		Long:  "在当前目录下生成环境变量模板文件 (.env.template)",
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			// 生成环境变量模板文件
 			if err := util.GenerateEnvTemplate(""); err != nil {
-				log.Fatal("生成环境变量模板失败: ", err)
+				return fmt.Errorf("生成环境变量模板失败: %w", err)
 			}
 			log.Println("环境变量模板文件已生成: .env.template")
+			return nil
 		},
 	}
 )
@@ -0,0 +1,231 @@
+package contracts
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"task1/util"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+)
+
+// ParseArgs 将命令行参数按方法的参数定义转换为 ABI 编码需要的 Go 类型
+//
+// 基本类型直接写值: 整数支持十进制和 0x 十六进制, bytes / bytesN 为 0x 十六进制, bool 为 true / false;
+// 数组和 tuple 写成 JSON, 如 [1,2,3]、["0x..","0x.."]、{"amount":"1000","to":"0x.."} 或按位置的 ["1000","0x.."],
+// JSON 中的大整数建议写成字符串, 避免精度丢失
+func ParseArgs(inputs abi.Arguments, args []string) ([]any, error) {
+	if len(args) != len(inputs) {
+		return nil, fmt.Errorf("%w: 需要 %d 个参数 (%s), 实际 %d 个", util.ErrInvalidArgument, len(inputs), argumentTypes(inputs), len(args))
+	}
+	values := make([]any, len(inputs))
+	for i, input := range inputs {
+		value, err := ParseValue(input.Type, args[i])
+		if err != nil {
+			return nil, fmt.Errorf("%w: 第 %d 个参数 %s: %w", util.ErrInvalidArgument, i+1, argumentName(input), err)
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+// ParseValue 将单个参数转换为 ABI 类型对应的 Go 值, 数组和 tuple 需要写成 JSON
+func ParseValue(t abi.Type, arg string) (any, error) {
+	var raw any = arg
+	switch t.T {
+	case abi.SliceTy, abi.ArrayTy, abi.TupleTy:
+		decoder := json.NewDecoder(strings.NewReader(arg))
+		// 保留数字的原始文本, 由 convertValue 按目标类型解析
+		decoder.UseNumber()
+		if err := decoder.Decode(&raw); err != nil {
+			return nil, fmt.Errorf("%s 类型的参数需要写成 JSON: %w", t, err)
+		}
+	}
+	value, err := convertValue(t, raw)
+	if err != nil {
+		return nil, err
+	}
+	return value.Interface(), nil
+}
+
+// convertValue 按 ABI 类型递归转换字符串或 JSON 解码出的值, 返回类型与 t.GetType() 一致
+func convertValue(t abi.Type, raw any) (reflect.Value, error) {
+	switch t.T {
+	case abi.IntTy, abi.UintTy:
+		n, err := parseInteger(t, raw)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		// 64 位以内的整数使用原生类型, 其余使用 *big.Int
+		goType := t.GetType()
+		if goType.Kind() == reflect.Ptr {
+			return reflect.ValueOf(n), nil
+		}
+		if t.T == abi.IntTy {
+			return reflect.ValueOf(n.Int64()).Convert(goType), nil
+		}
+		return reflect.ValueOf(n.Uint64()).Convert(goType), nil
+	case abi.BoolTy:
+		switch v := raw.(type) {
+		case bool:
+			return reflect.ValueOf(v), nil
+		case string:
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("bool 参数只能是 true 或 false: %q", v)
+			}
+			return reflect.ValueOf(b), nil
+		}
+	case abi.StringTy:
+		if v, ok := raw.(string); ok {
+			return reflect.ValueOf(v), nil
+		}
+	case abi.AddressTy:
+		if v, ok := raw.(string); ok {
+			if !common.IsHexAddress(v) {
+				return reflect.Value{}, fmt.Errorf("地址格式错误: %s", v)
+			}
+			return reflect.ValueOf(common.HexToAddress(v)), nil
+		}
+	case abi.BytesTy:
+		if v, ok := raw.(string); ok {
+			b, err := hexutil.Decode(v)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("bytes 参数需要 0x 开头的十六进制: %w", err)
+			}
+			return reflect.ValueOf(b), nil
+		}
+	case abi.FixedBytesTy, abi.FunctionTy:
+		if v, ok := raw.(string); ok {
+			b, err := hexutil.Decode(v)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("%s 参数需要 0x 开头的十六进制: %w", t, err)
+			}
+			size := t.Size
+			if t.T == abi.FunctionTy {
+				size = 24
+			}
+			if len(b) != size {
+				return reflect.Value{}, fmt.Errorf("%s 参数需要 %d 字节, 实际 %d 字节", t, size, len(b))
+			}
+			value := reflect.New(t.GetType()).Elem()
+			reflect.Copy(value, reflect.ValueOf(b))
+			return value, nil
+		}
+	case abi.SliceTy, abi.ArrayTy:
+		items, ok := raw.([]any)
+		if !ok {
+			break
+		}
+		if t.T == abi.ArrayTy && len(items) != t.Size {
+			return reflect.Value{}, fmt.Errorf("%s 需要 %d 个元素, 实际 %d 个", t, t.Size, len(items))
+		}
+		var value reflect.Value
+		if t.T == abi.SliceTy {
+			value = reflect.MakeSlice(t.GetType(), len(items), len(items))
+		} else {
+			value = reflect.New(t.GetType()).Elem()
+		}
+		for i, item := range items {
+			elem, err := convertValue(*t.Elem, item)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("第 %d 个元素: %w", i, err)
+			}
+			value.Index(i).Set(elem)
+		}
+		return value, nil
+	case abi.TupleTy:
+		return convertTuple(t, raw)
+	default:
+		return reflect.Value{}, fmt.Errorf("不支持的参数类型 %s", t)
+	}
+	return reflect.Value{}, fmt.Errorf("%s 类型的参数格式错误: %v", t, raw)
+}
+
+// convertTuple 转换 tuple (Solidity 结构体), 支持按字段名的 JSON 对象和按位置的 JSON 数组
+func convertTuple(t abi.Type, raw any) (reflect.Value, error) {
+	value := reflect.New(t.GetType()).Elem()
+	switch v := raw.(type) {
+	case []any:
+		if len(v) != len(t.TupleElems) {
+			return reflect.Value{}, fmt.Errorf("%s 需要 %d 个字段, 实际 %d 个", t, len(t.TupleElems), len(v))
+		}
+		for i, elem := range t.TupleElems {
+			field, err := convertValue(*elem, v[i])
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("字段 %s: %w", t.TupleRawNames[i], err)
+			}
+			value.Field(i).Set(field)
+		}
+	case map[string]any:
+		for i, elem := range t.TupleElems {
+			name := t.TupleRawNames[i]
+			item, ok := v[name]
+			if !ok {
+				return reflect.Value{}, fmt.Errorf("缺少字段 %s", name)
+			}
+			field, err := convertValue(*elem, item)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("字段 %s: %w", name, err)
+			}
+			value.Field(i).Set(field)
+		}
+		if len(v) != len(t.TupleElems) {
+			return reflect.Value{}, fmt.Errorf("%s 只有字段 %s", t, strings.Join(t.TupleRawNames, ", "))
+		}
+	default:
+		return reflect.Value{}, fmt.Errorf("%s 类型的参数需要 JSON 对象或数组: %v", t, raw)
+	}
+	return value, nil
+}
+
+// parseInteger 解析十进制或 0x 十六进制整数, 并检查是否超出类型的取值范围
+func parseInteger(t abi.Type, raw any) (*big.Int, error) {
+	var text string
+	switch v := raw.(type) {
+	case string:
+		text = v
+	case json.Number:
+		text = v.String()
+	default:
+		return nil, fmt.Errorf("%s 参数需要整数: %v", t, raw)
+	}
+	n, ok := math.ParseBig256(text)
+	if !ok {
+		return nil, fmt.Errorf("%s 参数需要十进制或 0x 十六进制整数: %q", t, text)
+	}
+	var minValue, maxValue *big.Int
+	if t.T == abi.UintTy {
+		minValue, maxValue = new(big.Int), new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), uint(t.Size)), big.NewInt(1))
+	} else {
+		maxValue = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), uint(t.Size-1)), big.NewInt(1))
+		minValue = new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), uint(t.Size-1)))
+	}
+	if n.Cmp(minValue) < 0 || n.Cmp(maxValue) > 0 {
+		return nil, fmt.Errorf("%s 超出 %s 的取值范围 [%s, %s]", n, t, minValue, maxValue)
+	}
+	return n, nil
+}
+
+// argumentTypes 返回参数类型列表, 如 address,uint256
+func argumentTypes(args abi.Arguments) string {
+	types := make([]string, len(args))
+	for i, arg := range args {
+		types[i] = arg.Type.String()
+	}
+	return strings.Join(types, ",")
+}
+
+// argumentName 返回参数名称, 没有名称时使用类型
+func argumentName(arg abi.Argument) string {
+	if arg.Name == "" {
+		return arg.Type.String()
+	}
+	return fmt.Sprintf("%s (%s)", arg.Name, arg.Type)
+}
@@ -0,0 +1,158 @@
+package contracts
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"task1/util"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func mustType(t *testing.T, typ string, components ...abi.ArgumentMarshaling) abi.Type {
+	t.Helper()
+	parsed, err := abi.NewType(typ, "", components)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed
+}
+
+func TestParseValue(t *testing.T) {
+	address := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	maxUint256, _ := new(big.Int).SetString("115792089237316195423570985008687907853269984665640564039457584007913129639935", 10)
+	tests := []struct {
+		typ  string
+		arg  string
+		want any // 为 nil 表示预期解析失败
+	}{
+		{"uint8", "255", uint8(255)},
+		{"uint8", "256", nil},
+		{"uint8", "-1", nil},
+		{"uint8", "0xff", uint8(255)},
+		{"int8", "-128", int8(-128)},
+		{"int8", "127", int8(127)},
+		{"int8", "-129", nil},
+		{"int8", "128", nil},
+		{"uint64", "18446744073709551615", uint64(18446744073709551615)},
+		{"uint64", "18446744073709551616", nil},
+		{"int24", "-8388608", big.NewInt(-8388608)},
+		{"int24", "8388608", nil},
+		{"uint256", maxUint256.String(), maxUint256},
+		{"uint256", "0x1" + fmt.Sprintf("%064x", 0), nil},
+		{"uint256", "1.5", nil},
+		{"bool", "true", true},
+		{"bool", "yes", nil},
+		{"string", "你好", "你好"},
+		{"address", address.Hex(), address},
+		{"address", "0x1234", nil},
+		{"bytes", "0x0102", []byte{1, 2}},
+		{"bytes", "0x", []byte{}},
+		{"bytes", "0102", nil},
+		{"bytes4", "0x01020304", [4]byte{1, 2, 3, 4}},
+		{"bytes4", "0x010203", nil},
+		{"bytes4", "0x0102030405", nil},
+		{"uint8[]", "[1, 2, 255]", []uint8{1, 2, 255}},
+		{"uint8[]", "[1, 256]", nil},
+		{"uint8[]", "1,2", nil},
+		{"uint256[2]", `["1", 2]`, [2]*big.Int{big.NewInt(1), big.NewInt(2)}},
+		{"uint256[2]", "[1]", nil},
+		{"uint16[][2]", "[[1, 2], []]", [2][]uint16{{1, 2}, {}}},
+		{"address[2][]", fmt.Sprintf(`[["%s", "%s"]]`, address.Hex(), address.Hex()), [][2]common.Address{{address, address}}},
+		{"bytes2[][]", `[["0x0102"], ["0x01"]]`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.typ+" "+tt.arg, func(t *testing.T) {
+			got, err := ParseValue(mustType(t, tt.typ), tt.arg)
+			if tt.want == nil {
+				if err == nil {
+					t.Fatalf("预期解析失败, 实际为 %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("解析结果为 %#v, 预期 %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTuple(t *testing.T) {
+	to := common.HexToAddress("0x00000000000000000000000000000000000000bb")
+	order := mustType(t, "tuple",
+		abi.ArgumentMarshaling{Name: "amount", Type: "uint256"},
+		abi.ArgumentMarshaling{Name: "to", Type: "address"},
+		abi.ArgumentMarshaling{Name: "tags", Type: "bytes2[]"},
+		abi.ArgumentMarshaling{Name: "fee", Type: "tuple", Components: []abi.ArgumentMarshaling{
+			{Name: "rate", Type: "uint16"},
+			{Name: "enabled", Type: "bool"},
+		}},
+	)
+	tests := []struct {
+		name string
+		arg  string
+		ok   bool
+	}{
+		{"按字段名", fmt.Sprintf(`{"amount": "1000", "to": "%s", "tags": ["0x0102"], "fee": {"rate": 30, "enabled": true}}`, to.Hex()), true},
+		{"按位置", fmt.Sprintf(`["1000", "%s", ["0x0102"], [30, true]]`, to.Hex()), true},
+		{"混合嵌套", fmt.Sprintf(`{"amount": 1000, "to": "%s", "tags": ["0x0102"], "fee": [30, "true"]}`, to.Hex()), true},
+		{"缺少字段", fmt.Sprintf(`{"amount": "1000", "to": "%s", "tags": []}`, to.Hex()), false},
+		{"多余字段", fmt.Sprintf(`{"amount": "1000", "to": "%s", "tags": [], "fee": [30, true], "memo": "x"}`, to.Hex()), false},
+		{"字段名大小写不同", fmt.Sprintf(`{"Amount": "1000", "to": "%s", "tags": [], "fee": [30, true]}`, to.Hex()), false},
+		{"按位置缺少字段", fmt.Sprintf(`["1000", "%s", []]`, to.Hex()), false},
+		{"按位置多余字段", fmt.Sprintf(`["1000", "%s", [], [30, true], 1]`, to.Hex()), false},
+		{"嵌套 tuple 多余字段", fmt.Sprintf(`["1000", "%s", [], [30, true, 1]]`, to.Hex()), false},
+		{"字段超出范围", fmt.Sprintf(`["1000", "%s", [], [65536, true]]`, to.Hex()), false},
+		{"不是 JSON", "1000," + to.Hex(), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseValue(order, tt.arg)
+			if !tt.ok {
+				if err == nil {
+					t.Fatalf("预期解析失败, 实际为 %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			value := reflect.ValueOf(got)
+			fee := value.Field(3)
+			if value.Field(0).Interface().(*big.Int).Cmp(big.NewInt(1000)) != 0 || value.Field(1).Interface() != to ||
+				!reflect.DeepEqual(value.Field(2).Interface(), [][2]byte{{1, 2}}) || fee.Field(0).Uint() != 30 || !fee.Field(1).Bool() {
+				t.Fatalf("解析结果为 %+v", got)
+			}
+			// 解析结果可以直接用于 ABI 编码
+			if _, err := (abi.Arguments{{Type: order}}).Pack(got); err != nil {
+				t.Fatalf("ABI 编码失败: %v", err)
+			}
+		})
+	}
+}
+
+func TestParseArgs(t *testing.T) {
+	inputs := abi.Arguments{{Name: "to", Type: mustType(t, "address")}, {Name: "amount", Type: mustType(t, "uint256")}}
+	values, err := ParseArgs(inputs, []string{"0x00000000000000000000000000000000000000aa", "0x10"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := inputs.Pack(values...); err != nil {
+		t.Fatalf("ABI 编码失败: %v", err)
+	}
+	for _, args := range [][]string{
+		{"0x00000000000000000000000000000000000000aa"},
+		{"0x00000000000000000000000000000000000000aa", "1", "2"},
+		{"0x00000000000000000000000000000000000000aa", "-1"},
+	} {
+		if _, err := ParseArgs(inputs, args); !errors.Is(err, util.ErrInvalidArgument) {
+			t.Errorf("参数 %q 的错误为 %v, 预期 %v", args, err, util.ErrInvalidArgument)
+		}
+	}
+}
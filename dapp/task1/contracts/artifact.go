@@ -0,0 +1,225 @@
+package contracts
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"task1/decoder"
+	"task1/util"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// linkReference 字节码中库地址占位符的位置, 单位为字节
+type linkReference struct {
+	Start  int `json:"start"`
+	Length int `json:"length"`
+}
+
+// linkReferences 源文件 -> 库名 -> 占位符位置, 与 solc 标准 JSON 输出和 Hardhat 编译产物的格式一致
+type linkReferences map[string]map[string][]linkReference
+
+// Artifact 编译产物: ABI 和部署字节码, 字节码中可能包含未链接的库地址占位符
+type Artifact struct {
+	Name     string
+	ABI      *abi.ABI
+	RawABI   json.RawMessage   // ABI 的原始 JSON, 保存到部署记录
+	bytecode string            // 十六进制字节码, 不带 0x
+	links    linkReferences    // 编译产物中记录的占位符位置, .bin 文件为空
+	names    map[string]string // .bin 文件末尾注释中占位符对应的完全限定名
+}
+
+// placeholderPattern 库地址占位符, 固定 40 个字符: solc 0.5 起为 __$<完全限定名的 keccak256 前 34 个十六进制字符>$__,
+// 更早的版本为 __<库名, 用 _ 补齐到 36 个字符>__; 十六进制字节码中不会出现下划线
+var placeholderPattern = regexp.MustCompile(`__.{36}__`)
+
+// LoadArtifact 加载编译产物
+//
+// abiPath 可以是 ABI 数组文件, 也可以是 Hardhat (artifacts/contracts/X.sol/X.json) 或 Foundry (out/X.sol/X.json) 的编译产物;
+// binPath 为 solc --bin 输出的十六进制字节码文件, 为空时使用编译产物中的 bytecode
+func LoadArtifact(abiPath, binPath string) (*Artifact, error) {
+	name, rawABI, err := decoder.ReadFile(abiPath)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", util.ErrConfig, err)
+	}
+	contractABI, err := abi.JSON(bytes.NewReader(rawABI))
+	if err != nil {
+		return nil, fmt.Errorf("%w: 解析合约 %s 的 ABI 失败: %w", util.ErrConfig, name, err)
+	}
+	artifact := &Artifact{Name: name, ABI: &contractABI, RawABI: rawABI}
+	if binPath != "" {
+		data, err := os.ReadFile(binPath)
+		if err != nil {
+			return nil, fmt.Errorf("%w: 读取字节码文件失败: %w", util.ErrConfig, err)
+		}
+		artifact.readBin(string(data))
+	} else if err := artifact.readBytecode(abiPath); err != nil {
+		return nil, err
+	}
+	artifact.bytecode = strings.TrimPrefix(artifact.bytecode, "0x")
+	if artifact.bytecode == "" {
+		return nil, fmt.Errorf("%w: 合约 %s 没有部署字节码 (接口或抽象合约不能部署)", util.ErrConfig, name)
+	}
+	return artifact, nil
+}
+
+// NewArtifact 根据 ABI JSON 和十六进制部署字节码创建编译产物, 用于程序内置的合约
+func NewArtifact(name string, rawABI []byte, bytecode string) (*Artifact, error) {
+	contractABI, err := abi.JSON(bytes.NewReader(rawABI))
+	if err != nil {
+		return nil, fmt.Errorf("%w: 解析合约 %s 的 ABI 失败: %w", util.ErrConfig, name, err)
+	}
+	return &Artifact{Name: name, ABI: &contractABI, RawABI: rawABI, bytecode: strings.TrimPrefix(bytecode, "0x")}, nil
+}
+
+// readBytecode 从编译产物中读取部署字节码和占位符位置
+// Hardhat: {"bytecode": "0x..", "linkReferences": {...}}, Foundry: {"bytecode": {"object": "0x..", "linkReferences": {...}}}
+func (a *Artifact) readBytecode(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("%w: 读取编译产物失败: %w", util.ErrConfig, err)
+	}
+	var artifact struct {
+		Bytecode       json.RawMessage `json:"bytecode"`
+		LinkReferences linkReferences  `json:"linkReferences"`
+	}
+	if trimmed := bytes.TrimSpace(data); len(trimmed) == 0 || trimmed[0] != '{' {
+		return fmt.Errorf("%w: %s 只包含 ABI, 需要通过 --bin 指定字节码文件", util.ErrInvalidArgument, path)
+	}
+	if err := json.Unmarshal(data, &artifact); err != nil {
+		return fmt.Errorf("%w: 解析编译产物 %s 失败: %w", util.ErrConfig, path, err)
+	}
+	if len(artifact.Bytecode) == 0 {
+		return fmt.Errorf("%w: 编译产物 %s 中没有 bytecode 字段, 需要通过 --bin 指定字节码文件", util.ErrInvalidArgument, path)
+	}
+	var foundry struct {
+		Object         string         `json:"object"`
+		LinkReferences linkReferences `json:"linkReferences"`
+	}
+	if err := json.Unmarshal(artifact.Bytecode, &a.bytecode); err == nil {
+		a.links = artifact.LinkReferences
+		return nil
+	}
+	if err := json.Unmarshal(artifact.Bytecode, &foundry); err != nil {
+		return fmt.Errorf("%w: 编译产物 %s 的 bytecode 格式错误: %w", util.ErrConfig, path, err)
+	}
+	a.bytecode, a.links = foundry.Object, foundry.LinkReferences
+	return nil
+}
+
+// readBin 解析 solc --bin 输出的字节码文件
+// 需要链接库时 solc 在字节码后追加 "// $<哈希>$ -> 源文件:库名" 形式的注释, 用于在错误信息中显示库名
+func (a *Artifact) readBin(data string) {
+	a.names = make(map[string]string)
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if comment, ok := strings.CutPrefix(line, "//"); ok {
+			if hash, name, ok := strings.Cut(strings.TrimSpace(comment), " -> "); ok {
+				a.names["__"+hash+"__"] = name
+			}
+			continue
+		}
+		a.bytecode += line
+	}
+}
+
+// Libraries 返回字节码需要链接的库, 格式为 "源文件:库名", .bin 文件中无法识别的占位符原样返回
+func (a *Artifact) Libraries() []string {
+	return a.unlinked(a.bytecode)
+}
+
+// unlinked 返回字节码中未链接的库
+func (a *Artifact) unlinked(code string) []string {
+	var libraries []string
+	for _, placeholder := range placeholderPattern.FindAllString(code, -1) {
+		libraries = append(libraries, a.libraryName(placeholder))
+	}
+	slices.Sort(libraries)
+	return slices.Compact(libraries)
+}
+
+// libraryName 返回占位符对应的库, 优先使用编译产物的 linkReferences 和 .bin 文件的注释
+func (a *Artifact) libraryName(placeholder string) string {
+	if name, ok := a.names[placeholder]; ok {
+		return name
+	}
+	for source, libs := range a.links {
+		for lib := range libs {
+			if slices.Contains(placeholders(source+":"+lib), placeholder) {
+				return source + ":" + lib
+			}
+		}
+	}
+	return placeholder
+}
+
+// Link 将库地址写入字节码的占位符并返回部署字节码
+//
+// libraries 的键可以是库名 (如 SafeMath) 或完全限定名 (如 contracts/SafeMath.sol:SafeMath);
+// .bin 文件中 solc 0.5 及以上版本的占位符是完全限定名的哈希, 只能按完全限定名匹配
+func (a *Artifact) Link(libraries map[string]common.Address) ([]byte, error) {
+	code := []byte(a.bytecode)
+	used := make(map[string]bool)
+	for source, libs := range a.links {
+		for lib, refs := range libs {
+			address, key, ok := lookupLibrary(libraries, source, lib)
+			if !ok {
+				continue
+			}
+			used[key] = true
+			for _, ref := range refs {
+				start, end := ref.Start*2, (ref.Start+ref.Length)*2
+				if ref.Length != common.AddressLength || end > len(code) {
+					return nil, fmt.Errorf("%w: 库 %s:%s 的占位符位置错误", util.ErrConfig, source, lib)
+				}
+				copy(code[start:end], hex.EncodeToString(address.Bytes()))
+			}
+		}
+	}
+	for key, address := range libraries {
+		for _, placeholder := range placeholders(key) {
+			if bytes.Contains(code, []byte(placeholder)) {
+				used[key] = true
+				code = bytes.ReplaceAll(code, []byte(placeholder), []byte(hex.EncodeToString(address.Bytes())))
+			}
+		}
+	}
+	for key := range libraries {
+		if !used[key] {
+			return nil, fmt.Errorf("%w: 字节码中没有库 %s 的占位符, 需要链接的库: %s", util.ErrInvalidArgument, key, strings.Join(a.Libraries(), ", "))
+		}
+	}
+	if missing := a.unlinked(string(code)); len(missing) > 0 {
+		return nil, fmt.Errorf("%w: 字节码中有未链接的库 %s, 请通过 --lib 库名=地址 指定库地址", util.ErrInvalidArgument, strings.Join(missing, ", "))
+	}
+	bytecode, err := hex.DecodeString(string(code))
+	if err != nil {
+		return nil, fmt.Errorf("%w: 字节码格式错误: %w", util.ErrConfig, err)
+	}
+	return bytecode, nil
+}
+
+// lookupLibrary 按完全限定名或库名查找库地址
+func lookupLibrary(libraries map[string]common.Address, source, lib string) (common.Address, string, bool) {
+	for _, key := range []string{source + ":" + lib, lib} {
+		if address, ok := libraries[key]; ok {
+			return address, key, true
+		}
+	}
+	return common.Address{}, "", false
+}
+
+// placeholders 返回库在 .bin 文件中可能的占位符
+func placeholders(name string) []string {
+	hash := hex.EncodeToString(crypto.Keccak256([]byte(name)))[:34]
+	legacy := "__" + name[:min(len(name), 36)]
+	legacy += strings.Repeat("_", 40-len(legacy))
+	return []string{"__$" + hash + "$__", legacy}
+}
@@ -0,0 +1,129 @@
+package contracts
+
+import (
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"task1/util"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// testdata 中的 Calculator 需要链接两处 contracts/Math.sol:Math 库地址
+const mathLibrary = "contracts/Math.sol:Math"
+
+// linkedCalculator 链接 address 后的 Calculator 部署字节码
+func linkedCalculator(address common.Address) []byte {
+	lib := hex.EncodeToString(address.Bytes())
+	code, _ := hex.DecodeString("608060405273" + lib + "6000f373" + lib + "f3")
+	return code
+}
+
+func TestLoadArtifact(t *testing.T) {
+	address := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	tests := []struct {
+		name      string
+		abi, bin  string
+		libraries []string
+		keys      map[string]bool // 链接时使用的键 -> 能否匹配占位符
+	}{
+		{"solc 哈希占位符", "Calculator.abi", "Calculator.bin", []string{mathLibrary},
+			// .bin 文件没有占位符位置, 哈希占位符只能按完全限定名匹配
+			map[string]bool{mathLibrary: true, "Math": false}},
+		{"solc 旧版占位符", "Calculator.abi", "CalculatorLegacy.bin", []string{"__Math__________________________________"},
+			map[string]bool{"Math": true, mathLibrary: false}},
+		{"Hardhat linkReferences", "Calculator.json", "", []string{mathLibrary},
+			map[string]bool{mathLibrary: true, "Math": true}},
+		{"Foundry bytecode.object", filepath.Join("foundry", "Calculator.json"), "", []string{mathLibrary},
+			map[string]bool{mathLibrary: true, "Math": true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			binPath := ""
+			if tt.bin != "" {
+				binPath = filepath.Join("testdata", tt.bin)
+			}
+			artifact, err := LoadArtifact(filepath.Join("testdata", tt.abi), binPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if artifact.Name != "Calculator" || artifact.ABI.Methods["square"].Name == "" {
+				t.Fatalf("合约名为 %s, 方法 %v", artifact.Name, artifact.ABI.Methods)
+			}
+			if libraries := artifact.Libraries(); !slices.Equal(libraries, tt.libraries) {
+				t.Fatalf("需要链接的库为 %q, 预期 %q", libraries, tt.libraries)
+			}
+			for key, ok := range tt.keys {
+				code, err := artifact.Link(map[string]common.Address{key: address})
+				if !ok {
+					if !errors.Is(err, util.ErrInvalidArgument) {
+						t.Errorf("按 %s 链接的错误为 %v, 预期 %v", key, err, util.ErrInvalidArgument)
+					}
+					continue
+				}
+				if err != nil {
+					t.Fatalf("按 %s 链接失败: %v", key, err)
+				}
+				if want := linkedCalculator(address); !slices.Equal(code, want) {
+					t.Fatalf("按 %s 链接后的字节码为 %x, 预期 %x", key, code, want)
+				}
+			}
+			// 没有指定库地址, 或指定了字节码中没有的库
+			for _, libraries := range []map[string]common.Address{
+				nil,
+				{mathLibrary: address, "contracts/Strings.sol:Strings": address},
+			} {
+				if _, err := artifact.Link(libraries); !errors.Is(err, util.ErrInvalidArgument) {
+					t.Errorf("链接 %v 的错误为 %v, 预期 %v", libraries, err, util.ErrInvalidArgument)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadArtifactErrors(t *testing.T) {
+	dir := t.TempDir()
+	abiJSON, err := os.ReadFile(filepath.Join("testdata", "Calculator.abi"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	write := func(name, data string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	tests := []struct {
+		name     string
+		abi, bin string
+		want     error
+	}{
+		{"只有 ABI 没有 --bin", write("abi.json", string(abiJSON)), "", util.ErrInvalidArgument},
+		{"编译产物没有 bytecode", write("nobytecode.json", `{"abi": `+string(abiJSON)+`}`), "", util.ErrInvalidArgument},
+		{"接口没有字节码", write("interface.json", `{"abi": `+string(abiJSON)+`, "bytecode": "0x"}`), "", util.ErrConfig},
+		{"bytecode 格式错误", write("badbytecode.json", `{"abi": `+string(abiJSON)+`, "bytecode": 1}`), "", util.ErrConfig},
+		{"--bin 文件不存在", write("abi2.json", string(abiJSON)), filepath.Join(dir, "missing.bin"), util.ErrConfig},
+		{"ABI 格式错误", write("badabi.json", `[{"type": "function", "inputs": 1}]`), "", util.ErrConfig},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := LoadArtifact(tt.abi, tt.bin); !errors.Is(err, tt.want) {
+				t.Fatalf("错误为 %v, 预期 %v", err, tt.want)
+			}
+		})
+	}
+
+	// 字节码中不是十六进制的内容在链接时报错
+	artifact, err := LoadArtifact(filepath.Join("testdata", "Calculator.abi"), write("bad.bin", strings.Repeat("zz", 4)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := artifact.Link(nil); !errors.Is(err, util.ErrConfig) {
+		t.Fatalf("链接非十六进制字节码的错误为 %v, 预期 %v", err, util.ErrConfig)
+	}
+}
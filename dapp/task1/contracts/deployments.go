@@ -0,0 +1,256 @@
+package contracts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"task1/errs"
+	"task1/util"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// DefaultDeploymentsFile 未通过 --deployments 指定时使用的部署记录文件
+const DefaultDeploymentsFile = "~/.task1_deployments.json"
+
+// Deployment 一次合约部署的记录
+type Deployment struct {
+	Name     string          `json:"-"`
+	ChainID  *big.Int        `json:"-"`
+	Address  common.Address  `json:"address"`
+	TxHash   common.Hash     `json:"txHash"`
+	Block    uint64          `json:"block"`
+	Deployer common.Address  `json:"deployer"`
+	Time     time.Time       `json:"time"`
+	CodeHash common.Hash     `json:"codeHash"`      // 部署后链上运行时代码的 keccak256, 用于发现地址上的合约已被替换
+	ABI      json.RawMessage `json:"abi,omitempty"` // 合约 ABI, 调用时不需要再指定 --abi
+
+	// 通过 proxy deploy 部署的代理合约: ABI 为实现合约的 ABI, 升级后更新
+	Implementation *common.Address `json:"implementation,omitempty"` // 当前实现合约地址
+	StorageLayout  json.RawMessage `json:"storageLayout,omitempty"`  // 当前实现合约的存储布局, 升级前用于兼容性检查
+}
+
+// ParseABI 解析部署记录中保存的 ABI
+func (d *Deployment) ParseABI() (*abi.ABI, error) {
+	if len(d.ABI) == 0 {
+		return nil, fmt.Errorf("%w: 合约 %s 的部署记录中没有 ABI, 请通过 --abi 指定", util.ErrInvalidArgument, d.Name)
+	}
+	contractABI, err := abi.JSON(bytes.NewReader(d.ABI))
+	if err != nil {
+		return nil, fmt.Errorf("%w: 解析合约 %s 部署记录中的 ABI 失败: %w", util.ErrConfig, d.Name, err)
+	}
+	return &contractABI, nil
+}
+
+// Check 检查部署记录的地址上是否有合约代码, 以及代码是否与部署时一致
+// 本地开发链重启或重置后, 部署记录中的地址上可能没有代码, 也可能是相同 nonce 部署的其他合约
+func (d *Deployment) Check(ctx context.Context, client util.Client) error {
+	code, err := client.CodeAt(ctx, d.Address, nil)
+	if err != nil {
+		return fmt.Errorf("查询合约 %s 的代码失败: %w", d.Name, errs.Classify(err))
+	}
+	if len(code) == 0 {
+		return fmt.Errorf("%w: 合约 %s 的地址 %s 上没有合约代码, 链可能已重置, 请重新部署或执行 contracts forget %s",
+			util.ErrNotDeployed, d.Name, d.Address.Hex(), d.Name)
+	}
+	if d.CodeHash != (common.Hash{}) && crypto.Keccak256Hash(code) != d.CodeHash {
+		return fmt.Errorf("%w: 合约 %s 的地址 %s 上的代码与部署记录不一致, 请重新部署或执行 contracts forget %s",
+			util.ErrNotDeployed, d.Name, d.Address.Hex(), d.Name)
+	}
+	return nil
+}
+
+// deploymentsFile 部署记录文件格式: 链ID -> 合约名 -> 部署记录
+type deploymentsFile map[string]map[string]*Deployment
+
+// Deployments 按 (链ID, 合约名) 保存部署记录, 同一链上同名合约重新部署时覆盖
+type Deployments struct {
+	path string
+}
+
+// OpenDeployments 打开部署记录文件, path 为空时使用 DefaultDeploymentsFile, 文件不存在时在第一次记录时创建
+func OpenDeployments(path string) *Deployments {
+	if path == "" {
+		path = DefaultDeploymentsFile
+	}
+	return &Deployments{path: util.ExpandHome(path)}
+}
+
+// Path 返回部署记录文件路径
+func (d *Deployments) Path() string {
+	return d.path
+}
+
+// Record 保存部署记录
+func (d *Deployments) Record(chainID *big.Int, name string, deployment *Deployment) error {
+	return d.update(func(file deploymentsFile) error {
+		chain := chainID.String()
+		if file[chain] == nil {
+			file[chain] = make(map[string]*Deployment)
+		}
+		file[chain][name] = deployment
+		deployment.Name, deployment.ChainID = name, chainID
+		return nil
+	})
+}
+
+// Lookup 查找部署记录, 没有记录时返回 util.ErrNotDeployed
+func (d *Deployments) Lookup(chainID *big.Int, name string) (*Deployment, error) {
+	file, err := d.load()
+	if err != nil {
+		return nil, err
+	}
+	deployment, ok := file[chainID.String()][name]
+	if !ok {
+		return nil, fmt.Errorf("%w: 链 %s 上没有合约 %s 的部署记录", util.ErrNotDeployed, chainID, name)
+	}
+	deployment.Name, deployment.ChainID = name, chainID
+	return deployment, nil
+}
+
+// Load 查找当前链上的部署记录, 并检查地址上的合约代码
+func (d *Deployments) Load(ctx context.Context, client util.Client, name string) (*Deployment, error) {
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("获取链ID失败: %w", errs.Classify(err))
+	}
+	deployment, err := d.Lookup(chainID, name)
+	if err != nil {
+		return nil, err
+	}
+	if err := deployment.Check(ctx, client); err != nil {
+		return nil, err
+	}
+	return deployment, nil
+}
+
+// Resolve 解析合约地址或当前链部署记录中的合约名, 并检查地址上的合约代码
+// 使用合约名时同时返回部署记录, 直接指定地址时部署记录为 nil
+func (d *Deployments) Resolve(ctx context.Context, client util.Client, target string) (common.Address, *Deployment, error) {
+	if !common.IsHexAddress(target) {
+		deployment, err := d.Load(ctx, client, target)
+		if err != nil {
+			return common.Address{}, nil, err
+		}
+		return deployment.Address, deployment, nil
+	}
+	address := common.HexToAddress(target)
+	code, err := client.CodeAt(ctx, address, nil)
+	if err != nil {
+		return common.Address{}, nil, fmt.Errorf("查询合约代码失败: %w", errs.Classify(err))
+	}
+	if len(code) == 0 {
+		return common.Address{}, nil, fmt.Errorf("%w: 地址 %s 上没有合约代码", util.ErrNotDeployed, address.Hex())
+	}
+	return address, nil, nil
+}
+
+// List 返回部署记录, chainID 为 nil 时返回所有链的记录, 按链ID和合约名排序
+func (d *Deployments) List(chainID *big.Int) ([]*Deployment, error) {
+	file, err := d.load()
+	if err != nil {
+		return nil, err
+	}
+	var deployments []*Deployment
+	for chain, contracts := range file {
+		id, ok := new(big.Int).SetString(chain, 10)
+		if !ok || (chainID != nil && id.Cmp(chainID) != 0) {
+			continue
+		}
+		for name, deployment := range contracts {
+			deployment.Name, deployment.ChainID = name, id
+			deployments = append(deployments, deployment)
+		}
+	}
+	slices.SortFunc(deployments, func(a, b *Deployment) int {
+		if c := a.ChainID.Cmp(b.ChainID); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return deployments, nil
+}
+
+// Forget 删除部署记录, 没有记录时返回 util.ErrNotDeployed
+func (d *Deployments) Forget(chainID *big.Int, name string) error {
+	return d.update(func(file deploymentsFile) error {
+		chain := chainID.String()
+		if _, ok := file[chain][name]; !ok {
+			return fmt.Errorf("%w: 链 %s 上没有合约 %s 的部署记录", util.ErrNotDeployed, chainID, name)
+		}
+		delete(file[chain], name)
+		if len(file[chain]) == 0 {
+			delete(file, chain)
+		}
+		return nil
+	})
+}
+
+// update 在文件锁内读取部署记录, 由 fn 修改后写回, 避免多个进程同时部署时互相覆盖记录
+func (d *Deployments) update(fn func(file deploymentsFile) error) error {
+	if err := os.MkdirAll(filepath.Dir(d.path), 0700); err != nil {
+		return fmt.Errorf("创建部署记录目录失败: %w", err)
+	}
+	unlock, err := util.LockFile(context.Background(), d.path+".lock")
+	if err != nil {
+		return fmt.Errorf("锁定部署记录失败: %w", err)
+	}
+	defer unlock()
+	file, err := d.load()
+	if err != nil {
+		return err
+	}
+	if err := fn(file); err != nil {
+		return err
+	}
+	return d.save(file)
+}
+
+func (d *Deployments) load() (deploymentsFile, error) {
+	data, err := os.ReadFile(d.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return make(deploymentsFile), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取部署记录失败: %w", err)
+	}
+	file := make(deploymentsFile)
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("%w: 解析部署记录 %s 失败: %w", util.ErrConfig, d.path, err)
+	}
+	return file, nil
+}
+
+func (d *Deployments) save(file deploymentsFile) error {
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	// 先写临时文件再重命名, 避免进程中断时留下不完整的文件; 临时文件名唯一, 不会与其他进程冲突
+	tmp, err := os.CreateTemp(filepath.Dir(d.path), filepath.Base(d.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("写入部署记录失败: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("写入部署记录失败: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("写入部署记录失败: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return fmt.Errorf("写入部署记录失败: %w", err)
+	}
+	return os.Rename(tmp.Name(), d.path)
+}
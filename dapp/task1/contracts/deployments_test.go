@@ -0,0 +1,66 @@
+package contracts
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+	"task1/util"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// TestDeploymentsConcurrentRecord 多个进程 (各自打开部署记录文件) 同时记录部署时不会互相覆盖
+func TestDeploymentsConcurrentRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deployments.json")
+	chainID := big.NewInt(1337)
+	const n = 20
+	var wg sync.WaitGroup
+	errs := make([]error, n)
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			deployment := &Deployment{Address: common.BigToAddress(big.NewInt(int64(i + 1)))}
+			errs[i] = OpenDeployments(path).Record(chainID, fmt.Sprintf("C%d", i), deployment)
+		}()
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		t.Fatal(err)
+	}
+
+	deployments := OpenDeployments(path)
+	records, err := deployments.List(chainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != n {
+		t.Fatalf("部署记录有 %d 条, 预期 %d 条", len(records), n)
+	}
+	if err := deployments.Forget(chainID, "C0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := deployments.Forget(chainID, "C0"); !errors.Is(err, util.ErrNotDeployed) {
+		t.Fatalf("重复删除的错误为 %v, 预期 %v", err, util.ErrNotDeployed)
+	}
+	if _, err := deployments.Lookup(chainID, "C1"); err != nil {
+		t.Fatal(err)
+	}
+
+	// 写入完成后不留下临时文件, 只保留部署记录和锁文件
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Name() != "deployments.json" || entries[1].Name() != "deployments.json.lock" {
+		var names []string
+		for _, entry := range entries {
+			names = append(names, entry.Name())
+		}
+		t.Fatalf("目录中的文件为 %v", names)
+	}
+}
@@ -0,0 +1,217 @@
+package contracts
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"task1/account"
+	"task1/decoder"
+	"task1/errs"
+	"task1/transactions"
+	"task1/util"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Contract 按 ABI 调用任意已部署的合约, 不需要生成绑定代码
+type Contract struct {
+	Address common.Address
+	ABI     *abi.ABI
+	client  util.Client
+}
+
+// NewContract 创建通用合约调用, client 由调用方负责关闭
+func NewContract(client util.Client, address common.Address, contractABI *abi.ABI) *Contract {
+	return &Contract{Address: address, ABI: contractABI, client: client}
+}
+
+// Method 按名称或签名查找方法, 例如 transfer 或 transfer(address,uint256)
+// 重载的方法只能按签名查找
+func (c *Contract) Method(name string) (*abi.Method, error) {
+	var overloads []abi.Method
+	for _, method := range c.ABI.Methods {
+		if method.Sig == name {
+			return &method, nil
+		}
+		if method.RawName == name {
+			overloads = append(overloads, method)
+		}
+	}
+	switch len(overloads) {
+	case 0:
+		return nil, fmt.Errorf("%w: ABI 中没有方法 %s", util.ErrInvalidArgument, name)
+	case 1:
+		return &overloads[0], nil
+	}
+	sigs := make([]string, len(overloads))
+	for i, method := range overloads {
+		sigs[i] = method.Sig
+	}
+	slices.Sort(sigs)
+	return nil, fmt.Errorf("%w: 方法 %s 有多个重载, 请使用完整签名: %s", util.ErrInvalidArgument, name, strings.Join(sigs, ", "))
+}
+
+// Pack 按方法的参数定义解析命令行参数并编码调用数据
+func (c *Contract) Pack(method *abi.Method, args []string) ([]byte, error) {
+	values, err := ParseArgs(method.Inputs, args)
+	if err != nil {
+		return nil, err
+	}
+	arguments, err := method.Inputs.Pack(values...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: 编码 %s 的参数失败: %w", util.ErrInvalidArgument, method.Sig, err)
+	}
+	return append(method.ID, arguments...), nil
+}
+
+// Call 通过 eth_call 调用方法并解码返回值, 不发送交易; from 为调用时的 msg.sender
+// block 为 nil 时使用最新区块
+func (c *Contract) Call(ctx context.Context, from common.Address, method *abi.Method, args []string, block *big.Int) ([]decoder.Arg, error) {
+	data, err := c.Pack(method, args)
+	if err != nil {
+		return nil, err
+	}
+	ret, err := c.client.CallContract(ctx, ethereum.CallMsg{From: from, To: &c.Address, Data: data}, block)
+	if err != nil {
+		return nil, util.RevertError(fmt.Sprintf("调用 %s 失败", method.Sig), err)
+	}
+	values, err := method.Outputs.Unpack(ret)
+	if err != nil {
+		return nil, fmt.Errorf("解码 %s 的返回值失败: %w", method.Sig, err)
+	}
+	outputs := make([]decoder.Arg, len(values))
+	for i, output := range method.Outputs {
+		outputs[i] = decoder.Arg{Name: output.Name, Type: output.Type.String(), Value: values[i]}
+	}
+	return outputs, nil
+}
+
+// Send 签名并发送调用方法的交易, value 为附带的金额 (wei), 只有 payable 方法可以附带金额
+// 返回交易收据, 交易执行失败时同时返回收据和 util.ErrTxFailed
+func (c *Contract) Send(ctx context.Context, method *abi.Method, args []string, value *big.Int) (*types.Receipt, error) {
+	if value == nil {
+		value = new(big.Int)
+	}
+	if value.Sign() > 0 && !method.IsPayable() {
+		return nil, fmt.Errorf("%w: 方法 %s 不是 payable, 不能附带金额", util.ErrInvalidArgument, method.Sig)
+	}
+	data, err := c.Pack(method, args)
+	if err != nil {
+		return nil, err
+	}
+	util.Logf("开始调用合约 %s 的方法 %s", c.Address.Hex(), method.Sig)
+	from, err := account.Address(ctx)
+	if err != nil {
+		return nil, err
+	}
+	chainID, err := c.client.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("获取链ID失败: %w", errs.Classify(err))
+	}
+	tx, err := util.BuildAndSend(ctx, c.client, from, func(ctx context.Context, nonce uint64) (*types.Transaction, error) {
+		fees, err := transactions.SuggestFees(ctx, c.client, transactions.CurrentFeeOptions())
+		if err != nil {
+			return nil, err
+		}
+		// 交易会回滚时估算失败, 附带解码后的回滚原因
+		gas, err := c.client.EstimateGas(ctx, ethereum.CallMsg{From: from, To: &c.Address, Value: value, Data: data})
+		if err != nil {
+			return nil, util.RevertError("估算 gas 失败", err)
+		}
+		return fees.NewTx(chainID, nonce, &c.Address, value, gas, data), nil
+	}, account.Sign(from, chainID))
+	if err != nil {
+		return nil, fmt.Errorf("调用 %s 失败: %w", method.Sig, err)
+	}
+	receipt, err := util.WaitReceipt(ctx, c.client, tx.Hash())
+	if err != nil {
+		return nil, err
+	}
+	return receipt, util.DiagnoseReceipt(ctx, c.client, receipt)
+}
+
+// Deploy 链接库地址、编码构造函数参数并部署合约, value 为附带的金额 (wei), 只有 payable 构造函数可以附带金额
+// 返回部署记录和交易收据, 交易执行失败时同时返回收据和 util.ErrTxFailed
+func Deploy(ctx context.Context, client util.Client, artifact *Artifact, libraries map[string]common.Address, args []string, value *big.Int) (*Deployment, *types.Receipt, error) {
+	if value == nil {
+		value = new(big.Int)
+	}
+	constructor := artifact.ABI.Constructor
+	if value.Sign() > 0 && !constructor.IsPayable() {
+		return nil, nil, fmt.Errorf("%w: 合约 %s 的构造函数不是 payable, 不能附带金额", util.ErrInvalidArgument, artifact.Name)
+	}
+	bytecode, err := artifact.Link(libraries)
+	if err != nil {
+		return nil, nil, err
+	}
+	values, err := ParseArgs(constructor.Inputs, args)
+	if err != nil {
+		return nil, nil, fmt.Errorf("构造函数参数: %w", err)
+	}
+	arguments, err := constructor.Inputs.Pack(values...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: 编码构造函数参数失败: %w", util.ErrInvalidArgument, err)
+	}
+	return DeployCode(ctx, client, artifact.Name, append(bytecode, arguments...), value, artifact.RawABI)
+}
+
+// DeployCode 签名并发送部署交易, data 为链接后的字节码和编码后的构造函数参数, rawABI 保存到部署记录
+// 返回部署记录和交易收据, 交易执行失败时同时返回收据和 util.ErrTxFailed
+func DeployCode(ctx context.Context, client util.Client, name string, data []byte, value *big.Int, rawABI []byte) (*Deployment, *types.Receipt, error) {
+	util.Logf("开始部署合约 %s", name)
+	from, err := account.Address(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("获取链ID失败: %w", errs.Classify(err))
+	}
+	tx, err := util.BuildAndSend(ctx, client, from, func(ctx context.Context, nonce uint64) (*types.Transaction, error) {
+		fees, err := transactions.SuggestFees(ctx, client, transactions.CurrentFeeOptions())
+		if err != nil {
+			return nil, err
+		}
+		gas, err := client.EstimateGas(ctx, ethereum.CallMsg{From: from, Value: value, Data: data})
+		if err != nil {
+			return nil, util.RevertError("估算 gas 失败", err)
+		}
+		return fees.NewTx(chainID, nonce, nil, value, gas, data), nil
+	}, account.Sign(from, chainID))
+	if err != nil {
+		return nil, nil, fmt.Errorf("部署合约 %s 失败: %w", name, err)
+	}
+	receipt, err := util.WaitReceipt(ctx, client, tx.Hash())
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := util.DiagnoseReceipt(ctx, client, receipt); err != nil {
+		return nil, receipt, err
+	}
+	deployment, err := newDeployment(ctx, client, receipt, from, rawABI)
+	return deployment, receipt, err
+}
+
+// newDeployment 根据部署交易的收据创建部署记录
+func newDeployment(ctx context.Context, client util.Client, receipt *types.Receipt, deployer common.Address, rawABI []byte) (*Deployment, error) {
+	code, err := client.CodeAt(ctx, receipt.ContractAddress, receipt.BlockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("查询合约代码失败: %w", errs.Classify(err))
+	}
+	return &Deployment{
+		Address:  receipt.ContractAddress,
+		TxHash:   receipt.TxHash,
+		Block:    receipt.BlockNumber.Uint64(),
+		Deployer: deployer,
+		Time:     time.Now().UTC(),
+		CodeHash: crypto.Keccak256Hash(code),
+		ABI:      rawABI,
+	}, nil
+}
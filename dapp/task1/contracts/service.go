@@ -2,85 +2,60 @@ package contracts
 
 import (
 	"context"
-	"crypto/ecdsa"
+	"encoding/hex"
 	"errors"
-	"log"
+	"fmt"
 	"math/big"
 	"os"
 	"strings"
+	"task1/account"
+	"task1/decoder"
+	"task1/errs"
+	"task1/transactions"
 	"task1/util"
+	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/ethclient"
 )
 
-type ContractService struct {
-	savePath   string
-	Address    string
-	client     *ethclient.Client
-	Contracts  *Contracts
-	isReDeploy bool
+func init() {
+	// 注册计数器合约的 ABI, 用于解码回滚原因
+	if contractABI, err := ContractsMetaData.GetAbi(); err == nil {
+		decoder.Register(CountingName, contractABI)
+	}
 }
 
-func NewContractService(savePath string) *ContractService {
-	res := &ContractService{savePath: savePath, client: util.LoadClient()}
-	return res.init()
+// CountingName 计数器合约在部署记录中的名称
+const CountingName = "Counting"
 
-}
-func (c *ContractService) SaveAddress() {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		log.Fatal(err)
-	}
-	path := strings.ReplaceAll(c.savePath, "~", homeDir)
-	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
-	if err != nil {
-		log.Fatal(err)
-	}
-	defer file.Close()
-	_, err = file.WriteString(c.Address)
-	if err != nil {
-		log.Fatal(err)
-	}
-	err = file.Sync()
-	if err != nil {
-		log.Fatal(err)
-	}
-}
-func (c *ContractService) Close() {
-	defer c.client.Close()
-	c.SaveAddress()
+// legacyAddressFile 旧版本保存计数器合约地址的文本文件, 没有部署记录时导入一次
+const legacyAddressFile = "~/.task1_contractsAddress"
+
+type ContractService struct {
+	deployments *Deployments
+	Address     common.Address
+	client      util.Client
+	Contracts   *Contracts
+	isReDeploy  bool
 }
 
-func (c *ContractService) init() *ContractService {
-	if c.savePath == "" {
-		c.savePath = "~/.task1_contractsAddress"
-	}
-	c.Contracts = c.LoadContract()
-	return c
+// NewContractService 创建合约服务并从部署记录加载当前链上的计数器合约
+// client 由调用方负责关闭
+func NewContractService(ctx context.Context, client util.Client, deployments *Deployments) (*ContractService, error) {
+	res := &ContractService{deployments: deployments, client: client}
+	return res.init(ctx)
 }
 
-func (c *ContractService) GetAddress() string {
-	if c.Address != "" {
-		return c.Address
-	}
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		log.Fatal(err)
-	}
-	path := strings.ReplaceAll(c.savePath, "~", homeDir)
-	data, err := os.ReadFile(path)
-	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			return ""
-		}
-		log.Println(err)
-		return ""
+func (c *ContractService) init(ctx context.Context) (*ContractService, error) {
+	contracts, err := c.LoadContract(ctx)
+	if err != nil && !errors.Is(err, util.ErrNotDeployed) {
+		return nil, err
 	}
-	c.Address = string(data)
-	return c.Address
+	c.Contracts = contracts
+	return c, nil
 }
 
 func (c *ContractService) SetReDeploy() *ContractService {
@@ -88,128 +63,184 @@ func (c *ContractService) SetReDeploy() *ContractService {
 	return c
 }
 
-// 部署合约并保存合约地址
-func (c *ContractService) Deploy() {
+// 部署合约并保存到部署记录
+// 已经部署过且未设置重新部署时返回 nil 收据
+func (c *ContractService) Deploy(ctx context.Context) (*types.Receipt, error) {
 	if c.Contracts != nil && !c.isReDeploy {
-		return
+		return nil, nil
 	}
-	log.Println("开始准备部署合约")
+	util.Logf("开始准备部署合约")
 	client := c.client
 
-	privateKey, err := crypto.HexToECDSA(util.LoadEnv("<PRIVATE_KEY>"))
+	from, err := account.Address(ctx)
 	if err != nil {
-		log.Fatal(err)
-	}
-
-	publicKey := privateKey.Public()
-	publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
-	if !ok {
-		log.Fatal("cannot assert type: publicKey is not of type *ecdsa.PublicKey")
+		return nil, err
 	}
 
-	fromAddress := crypto.PubkeyToAddress(*publicKeyECDSA)
-	nonce, err := client.PendingNonceAt(context.Background(), fromAddress)
+	chainID, err := client.ChainID(ctx)
 	if err != nil {
-		log.Fatal(err)
+		return nil, fmt.Errorf("获取链ID失败: %w", errs.Classify(err))
 	}
 
-	// gasPrice, err := client.SuggestGasPrice(context.Background())
-	// if err != nil {
-	// 	log.Fatal(err)
-	// }
-
-	chainId, err := client.NetworkID(context.Background())
+	// 绑定代码只构建交易, 由 util.BuildAndSend 负责签名、广播和重试
+	auth := account.NewUnsignedTransactor(ctx, from)
+	auth.Value = big.NewInt(0)
+	// auth.GasLimit = uint64(300000) 默认使用估算值
+	// 手续费按 --max-fee / --priority-fee / --fee-strategy / --legacy 估算
+
+	var contracts *Contracts
+	tx, err := util.BuildAndSend(ctx, client, auth.From, func(ctx context.Context, nonce uint64) (*types.Transaction, error) {
+		// 合约地址由 nonce 决定, 每次重新构建时都会更新
+		auth.Nonce = new(big.Int).SetUint64(nonce)
+		fees, err := transactions.SuggestFees(ctx, client, transactions.CurrentFeeOptions())
+		if err != nil {
+			return nil, err
+		}
+		fees.Apply(auth)
+		var tx *types.Transaction
+		_, tx, contracts, err = DeployContracts(auth, client)
+		if err != nil {
+			return nil, util.RevertError("估算 gas 失败", err)
+		}
+		return tx, nil
+	}, account.Sign(from, chainID))
 	if err != nil {
-		log.Fatal(err)
+		return nil, fmt.Errorf("部署合约失败: %w", err)
 	}
 
-	auth, err := bind.NewKeyedTransactorWithChainID(privateKey, chainId)
+	receipt, err := util.WaitReceipt(ctx, client, tx.Hash())
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
-	auth.Nonce = big.NewInt(int64(nonce))
-	auth.Value = big.NewInt(0)
-	// auth.GasLimit = uint64(300000) 默认使用估算值
-	// auth.GasPrice = gasPrice 默认使用估算值
-
-	address, tx, contracts, err := DeployContracts(auth, client)
-	if err != nil {
-		log.Fatal(err)
+	if err := util.DiagnoseReceipt(ctx, client, receipt); err != nil {
+		return receipt, err
 	}
 
-	receipt, err := util.WaitTransactionReceipt(client, 10, tx.Hash())
+	deployment, err := newDeployment(ctx, client, receipt, auth.From, []byte(ContractsMetaData.ABI))
 	if err != nil {
-		log.Fatal(err)
-	} else {
-		util.ShowReceipt(receipt)
+		return receipt, err
+	}
+	if err := c.deployments.Record(chainID, CountingName, deployment); err != nil {
+		return receipt, fmt.Errorf("保存部署记录失败: %w", err)
 	}
 
 	c.Contracts = contracts
-	c.Address = address.Hex()
+	c.Address = deployment.Address
 	c.isReDeploy = false
+	return receipt, nil
 }
 
 // 获取合约实例
-// savePath: 合约地址文件路径
-func (c *ContractService) LoadContract() *Contracts {
+// 当前链上没有部署记录, 或记录的地址上没有合约代码时返回 util.ErrNotDeployed
+func (c *ContractService) LoadContract(ctx context.Context) (*Contracts, error) {
 	if c.Contracts != nil {
-		return c.Contracts
+		return c.Contracts, nil
 	}
 
-	address := c.GetAddress()
-	if address == "" {
-		return nil
+	deployment, err := c.deployments.Load(ctx, c.client, CountingName)
+	if errors.Is(err, util.ErrNotDeployed) {
+		if imported, importErr := c.importLegacyAddress(ctx); importErr == nil {
+			deployment, err = imported, nil
+		}
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	client := c.client
-	contractsAddress := common.HexToAddress(address)
-	contracts, err := NewContracts(contractsAddress, client)
+	contracts, err := NewContracts(deployment.Address, c.client)
 	if err != nil {
-		log.Println(err)
-		return nil
+		return nil, err
 	}
-	return contracts
+	c.Address = deployment.Address
+	return contracts, nil
 }
 
-// 调用合约方法
-func (c *ContractService) Count() {
-	contracts := c.LoadContract()
-	if contracts == nil {
-		log.Fatal("contracts 没有初始化")
+// importLegacyAddress 将旧版本合约地址文件中的地址导入当前链的部署记录
+// 只有地址上的代码确实是计数器合约时才导入; 旧文件保留, 确认无误后可以手动删除
+func (c *ContractService) importLegacyAddress(ctx context.Context) (*Deployment, error) {
+	path := util.ExpandHome(legacyAddressFile)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
 	}
-	res, err := contracts.Count(nil)
+	address := strings.TrimSpace(string(data))
+	if !common.IsHexAddress(address) {
+		return nil, fmt.Errorf("%w: 旧合约地址文件 %s 中的地址格式错误", util.ErrConfig, path)
+	}
+	chainID, err := c.client.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("获取链ID失败: %w", errs.Classify(err))
+	}
+	code, err := c.client.CodeAt(ctx, common.HexToAddress(address), nil)
 	if err != nil {
-		log.Fatal(err)
+		return nil, fmt.Errorf("查询合约代码失败: %w", errs.Classify(err))
+	}
+	// 旧文件不区分链, 运行时代码包含在部署字节码中, 以此确认地址上确实是计数器合约
+	if len(code) == 0 || !strings.Contains(strings.ToLower(ContractsMetaData.Bin), hex.EncodeToString(code)) {
+		return nil, fmt.Errorf("%w: 旧合约地址文件中的地址 %s 在当前链上不是计数器合约", util.ErrNotDeployed, address)
 	}
-	log.Println("Count: ", res.Uint64())
+	deployment := &Deployment{
+		Address:  common.HexToAddress(address),
+		Time:     time.Now().UTC(),
+		CodeHash: crypto.Keccak256Hash(code),
+		ABI:      []byte(ContractsMetaData.ABI),
+	}
+	if err := c.deployments.Record(chainID, CountingName, deployment); err != nil {
+		return nil, err
+	}
+	util.Logf("已将旧合约地址文件 %s 中的地址 %s 导入部署记录 %s", path, address, c.deployments.Path())
+	return deployment, nil
 }
 
 // 调用合约方法
-func (c *ContractService) Increment() {
-	log.Println("开始调用合约方法 Increment")
-	contracts := c.LoadContract()
-	if contracts == nil {
-		log.Fatal("contracts 没有初始化")
+func (c *ContractService) Count(ctx context.Context) (*big.Int, error) {
+	contracts, err := c.LoadContract(ctx)
+	if err != nil {
+		return nil, err
 	}
-	privateKey, err := crypto.HexToECDSA(util.LoadEnv("<PRIVATE_KEY>"))
+	res, err := contracts.Count(&bind.CallOpts{Context: ctx})
 	if err != nil {
-		log.Fatal(err)
+		return nil, fmt.Errorf("调用 count 失败: %w", err)
 	}
-	chainID, err := c.client.NetworkID(context.Background())
+	return res, nil
+}
+
+// 调用合约方法
+func (c *ContractService) Increment(ctx context.Context) (*types.Receipt, error) {
+	util.Logf("开始调用合约方法 Increment")
+	contracts, err := c.LoadContract(ctx)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
-	opt, err := bind.NewKeyedTransactorWithChainID(privateKey, chainID)
+	from, err := account.Address(ctx)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
-	tx, err := contracts.Increment(opt)
+	chainID, err := c.client.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("获取链ID失败: %w", errs.Classify(err))
+	}
+	// 绑定代码只构建交易, 由 util.BuildAndSend 负责签名、广播和重试
+	opt := account.NewUnsignedTransactor(ctx, from)
+	tx, err := util.BuildAndSend(ctx, c.client, from, func(ctx context.Context, nonce uint64) (*types.Transaction, error) {
+		opt.Nonce = new(big.Int).SetUint64(nonce)
+		fees, err := transactions.SuggestFees(ctx, c.client, transactions.CurrentFeeOptions())
+		if err != nil {
+			return nil, err
+		}
+		fees.Apply(opt)
+		tx, err := contracts.Increment(opt)
+		if err != nil {
+			return nil, util.RevertError("估算 gas 失败", err)
+		}
+		return tx, nil
+	}, account.Sign(from, chainID))
 	if err != nil {
-		log.Fatal(err)
+		return nil, fmt.Errorf("调用 increment 失败: %w", err)
 	}
-	receipt, err := util.WaitTransactionReceipt(c.client, 10, tx.Hash())
+	receipt, err := util.WaitReceipt(ctx, c.client, tx.Hash())
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
-	util.ShowReceipt(receipt)
+	return receipt, util.DiagnoseReceipt(ctx, c.client, receipt)
 }
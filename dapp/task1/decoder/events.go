@@ -0,0 +1,118 @@
+package decoder
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Event 解码后的事件日志, 无法解码时 Name 为空并保留原始 topics 和 data
+type Event struct {
+	Index     uint           `json:"logIndex"`
+	Address   common.Address `json:"address"`
+	Contract  string         `json:"contract,omitempty"`  // 注册表中的合约名, 按签名数据库解码时为空
+	Name      string         `json:"event,omitempty"`     // 事件名称
+	Signature string         `json:"signature,omitempty"` // 事件签名, 如 Transfer(address,address,uint256)
+	Args      []Arg          `json:"args,omitempty"`
+	Topics    []common.Hash  `json:"topics,omitempty"`
+	Data      hexutil.Bytes  `json:"data,omitempty"`
+}
+
+// DecodeLog 解码事件日志: 先按注册表中的 ABI, 再按内置的签名数据库, 都找不到时返回原始数据
+func (r *Registry) DecodeLog(log *types.Log) *Event {
+	event := &Event{Index: log.Index, Address: log.Address}
+	if len(log.Topics) > 0 {
+		indexed := len(log.Topics) - 1
+		contract, e := r.findEvent(log.Topics[0], indexed)
+		if e == nil {
+			e = lookupSignature(log.Topics[0], indexed)
+		}
+		if e != nil {
+			if args, err := unpackLog(e, log); err == nil {
+				event.Contract, event.Name, event.Signature, event.Args = contract, e.RawName, e.Sig, args
+				return event
+			}
+		}
+	}
+	// 匿名事件或未知事件
+	event.Topics, event.Data = log.Topics, log.Data
+	return event
+}
+
+// DecodeLog 使用默认注册表解码事件日志
+func DecodeLog(log *types.Log) *Event {
+	return defaultRegistry.DecodeLog(log)
+}
+
+// DecodeLogs 使用默认注册表解码收据中的全部日志
+func DecodeLogs(logs []*types.Log) []*Event {
+	events := make([]*Event, len(logs))
+	for i, log := range logs {
+		events[i] = DecodeLog(log)
+	}
+	return events
+}
+
+// unpackLog 按事件定义解码索引参数 (topics) 和非索引参数 (data), 按定义顺序返回
+// 动态类型 (string、bytes、数组) 的索引参数只能得到其 keccak256 哈希
+func unpackLog(e *abi.Event, log *types.Log) ([]Arg, error) {
+	values := make(map[string]any)
+	// 参数没有名称时 (签名数据库中的部分事件) 按位置命名, 避免在 map 中互相覆盖
+	inputs := make(abi.Arguments, len(e.Inputs))
+	copy(inputs, e.Inputs)
+	for i := range inputs {
+		if inputs[i].Name == "" {
+			inputs[i].Name = fmt.Sprintf("arg%d", i)
+		}
+	}
+	indexed, nonIndexed := splitIndexed(inputs)
+	if err := abi.ParseTopicsIntoMap(values, indexed, log.Topics[1:]); err != nil {
+		return nil, err
+	}
+	if err := nonIndexed.UnpackIntoMap(values, log.Data); err != nil {
+		return nil, err
+	}
+	args := make([]Arg, len(inputs))
+	for i, input := range inputs {
+		args[i] = Arg{Name: e.Inputs[i].Name, Type: input.Type.String(), Value: values[input.Name], Indexed: input.Indexed}
+	}
+	return args, nil
+}
+
+func splitIndexed(inputs abi.Arguments) (indexed, nonIndexed abi.Arguments) {
+	for _, input := range inputs {
+		if input.Indexed {
+			indexed = append(indexed, input)
+		} else {
+			nonIndexed = append(nonIndexed, input)
+		}
+	}
+	return indexed, nonIndexed
+}
+
+// String 返回事件的可读描述, 例如 #0 0x5FbD...0aa3 Counting.Incremented(by=1)
+func (e *Event) String() string {
+	if e.Name == "" {
+		topics := make([]string, len(e.Topics))
+		for i, topic := range e.Topics {
+			topics[i] = topic.Hex()
+		}
+		return fmt.Sprintf("#%d %s 未知事件 topics=[%s] data=%s", e.Index, e.Address.Hex(), strings.Join(topics, ", "), e.Data)
+	}
+	args := make([]string, len(e.Args))
+	for i, arg := range e.Args {
+		args[i] = FormatValue(arg.Value)
+		if arg.Name != "" {
+			args[i] = arg.Name + "=" + args[i]
+		}
+	}
+	name := e.Name
+	if e.Contract != "" {
+		name = e.Contract + "." + name
+	}
+	return fmt.Sprintf("#%d %s %s(%s)", e.Index, e.Address.Hex(), name, strings.Join(args, ", "))
+}
@@ -0,0 +1,200 @@
+package decoder
+
+import (
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	contractAddress = common.HexToAddress("0x5FbDB2315678afecb367f032d93F642f64180aa3")
+	alice           = common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+	bob             = common.HexToAddress("0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC")
+)
+
+// packData 按类型编码日志的非索引参数
+func packData(t *testing.T, types []string, values ...any) []byte {
+	t.Helper()
+	var args abi.Arguments
+	for _, name := range types {
+		typ, err := abi.NewType(name, "", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		args = append(args, abi.Argument{Type: typ})
+	}
+	data, err := args.Pack(values...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func topic(signature string) common.Hash {
+	return crypto.Keccak256Hash([]byte(signature))
+}
+
+func TestDecodeLog(t *testing.T) {
+	ether := new(big.Int).Mul(big.NewInt(1), big.NewInt(1e18))
+	tests := []struct {
+		name      string
+		log       *types.Log
+		contract  string
+		event     string
+		signature string
+		args      string // String() 中的参数部分
+		indexed   []bool
+	}{
+		{"NFTAuction 出价", &types.Log{
+			Address: contractAddress,
+			Topics:  []common.Hash{topic("BidPlaced(uint256,address,uint256,address)"), common.BigToHash(big.NewInt(1)), common.BytesToHash(alice.Bytes())},
+			Data:    packData(t, []string{"uint256", "address"}, ether, common.Address{}),
+		}, "NFTAuction", "BidPlaced", "BidPlaced(uint256,address,uint256,address)",
+			"auctionId=1, bidder=" + alice.Hex() + ", amount=1000000000000000000, bidToken=0x0000000000000000000000000000000000000000",
+			[]bool{true, true, false, false}},
+		{"ERC20 Transfer 按签名数据库解码", &types.Log{
+			Address: contractAddress,
+			Topics:  []common.Hash{topic("Transfer(address,address,uint256)"), common.BytesToHash(alice.Bytes()), common.BytesToHash(bob.Bytes())},
+			Data:    packData(t, []string{"uint256"}, big.NewInt(2500000)),
+		}, "", "Transfer", "Transfer(address,address,uint256)",
+			"from=" + alice.Hex() + ", to=" + bob.Hex() + ", value=2500000",
+			[]bool{true, true, false}},
+		{"ERC721 Transfer 按 topic 数量区分", &types.Log{
+			Address: contractAddress,
+			Topics:  []common.Hash{topic("Transfer(address,address,uint256)"), {}, common.BytesToHash(bob.Bytes()), common.BigToHash(big.NewInt(7))},
+		}, "", "Transfer", "Transfer(address,address,uint256)",
+			"from=0x0000000000000000000000000000000000000000, to=" + bob.Hex() + ", tokenId=7",
+			[]bool{true, true, true}},
+		{"非索引的 string 参数", &types.Log{
+			Address: contractAddress,
+			Topics:  []common.Hash{topic("MintNFTURI(address,uint256,string)"), common.BytesToHash(alice.Bytes()), common.BigToHash(big.NewInt(3))},
+			Data:    packData(t, []string{"string"}, "ipfs://token/3"),
+		}, "MyERC721", "MintNFTURI", "MintNFTURI(address,uint256,string)",
+			"from=" + alice.Hex() + ", tokenId=3, uri=ipfs://token/3",
+			[]bool{true, true, false}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := DecodeLog(tt.log)
+			if event.Contract != tt.contract || event.Name != tt.event || event.Signature != tt.signature {
+				t.Fatalf("解码结果 %s.%s %s, 预期 %s.%s %s", event.Contract, event.Name, event.Signature, tt.contract, tt.event, tt.signature)
+			}
+			if !strings.HasSuffix(event.String(), "("+tt.args+")") {
+				t.Fatalf("事件描述为 %s, 预期参数 %s", event, tt.args)
+			}
+			for i, arg := range event.Args {
+				if arg.Indexed != tt.indexed[i] {
+					t.Fatalf("参数 %s 的 indexed 为 %v", arg.Name, arg.Indexed)
+				}
+			}
+			if event.Topics != nil || event.Data != nil {
+				t.Fatalf("解码成功时不应保留原始数据: %+v", event)
+			}
+		})
+	}
+}
+
+// TestDecodeLogIndexedDynamic 动态类型的索引参数只能得到 keccak256 哈希
+func TestDecodeLogIndexedDynamic(t *testing.T) {
+	r := NewRegistry()
+	if err := r.RegisterJSON("Registry", []byte(`[{"type": "event", "name": "NameRegistered", "inputs": [
+		{"name": "name", "type": "string", "indexed": true},
+		{"name": "owner", "type": "address", "indexed": true},
+		{"name": "tags", "type": "bytes32[]", "indexed": false}]}]`)); err != nil {
+		t.Fatal(err)
+	}
+	tags := [][32]byte{{1}, {2}}
+	event := r.DecodeLog(&types.Log{
+		Address: contractAddress,
+		Topics:  []common.Hash{topic("NameRegistered(string,address,bytes32[])"), crypto.Keccak256Hash([]byte("alice.eth")), common.BytesToHash(alice.Bytes())},
+		Data:    packData(t, []string{"bytes32[]"}, tags),
+	})
+	if event.Contract != "Registry" || len(event.Args) != 3 {
+		t.Fatalf("解码结果 %+v", event)
+	}
+	if hash, ok := event.Args[0].Value.(common.Hash); !ok || hash != crypto.Keccak256Hash([]byte("alice.eth")) {
+		t.Fatalf("索引的 string 参数为 %v (%T), 预期 keccak256 哈希", event.Args[0].Value, event.Args[0].Value)
+	}
+	if got := FormatValue(event.Args[2].Value); !strings.HasPrefix(got, "[0x0100") {
+		t.Fatalf("bytes32[] 参数为 %s", got)
+	}
+}
+
+// TestDecodeLogUnknown 未注册合约的未知事件和格式错误的日志保留原始数据
+func TestDecodeLogUnknown(t *testing.T) {
+	tests := []struct {
+		name string
+		log  *types.Log
+	}{
+		{"未知事件", &types.Log{Address: bob, Index: 4, Topics: []common.Hash{topic("Unknown(uint256)")}, Data: []byte{1, 2}}},
+		{"匿名事件", &types.Log{Address: bob, Index: 4, Data: []byte{1, 2}}},
+		{"topic 数量与定义不一致", &types.Log{Address: bob, Index: 4, Topics: []common.Hash{topic("Transfer(address,address,uint256)")}, Data: []byte{1, 2}}},
+		{"data 长度不足", &types.Log{Address: bob, Index: 4,
+			Topics: []common.Hash{topic("Transfer(address,address,uint256)"), {}, {}}, Data: []byte{1, 2}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := DecodeLog(tt.log)
+			if event.Name != "" || len(event.Topics) != len(tt.log.Topics) || string(event.Data) != string(tt.log.Data) {
+				t.Fatalf("解码结果 %+v, 预期保留原始数据", event)
+			}
+			if s := event.String(); !strings.HasPrefix(s, "#4 "+bob.Hex()+" 未知事件") || !strings.Contains(s, "data=0x0102") {
+				t.Fatalf("事件描述为 %s", s)
+			}
+		})
+	}
+}
+
+func TestEventJSON(t *testing.T) {
+	event := DecodeLog(&types.Log{
+		Address: contractAddress,
+		Index:   2,
+		Topics:  []common.Hash{topic("FeesWithdrawn(address,uint256,uint256)"), common.BytesToHash(alice.Bytes())},
+		Data:    packData(t, []string{"uint256", "uint256"}, new(big.Int).Lsh(big.NewInt(1), 70), big.NewInt(5)),
+	})
+	data, err := json.Marshal(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded struct {
+		Index     uint   `json:"logIndex"`
+		Contract  string `json:"contract"`
+		Event     string `json:"event"`
+		Signature string `json:"signature"`
+		Args      []struct {
+			Name    string `json:"name"`
+			Type    string `json:"type"`
+			Value   any    `json:"value"`
+			Indexed bool   `json:"indexed"`
+		} `json:"args"`
+		Topics []string `json:"topics"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Index != 2 || decoded.Contract != "NFTAuction" || decoded.Event != "FeesWithdrawn" || len(decoded.Args) != 3 || decoded.Topics != nil {
+		t.Fatalf("JSON 输出 %s", data)
+	}
+	// 大整数输出为字符串, 不丢失精度
+	if decoded.Args[1].Value != "1180591620717411303424" || decoded.Args[1].Type != "uint256" {
+		t.Fatalf("ethAmount 输出为 %v", decoded.Args[1].Value)
+	}
+	if decoded.Args[0].Value != alice.Hex() || !decoded.Args[0].Indexed {
+		t.Fatalf("recipient 输出为 %+v", decoded.Args[0])
+	}
+
+	// 无法解码的事件输出原始 topics 和 data
+	data, err = json.Marshal(DecodeLog(&types.Log{Address: bob, Topics: []common.Hash{{9}}, Data: []byte{0xab}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := string(data); strings.Contains(s, `"event"`) || !strings.Contains(s, `"data":"0xab"`) || !strings.Contains(s, `"topics":["0x09`) {
+		t.Fatalf("JSON 输出 %s", s)
+	}
+}
@@ -0,0 +1,226 @@
+// Package decoder 按已知合约的 ABI 解码交易的回滚数据和事件日志
+//
+// 默认注册表包含随程序内置的项目合约 (NFTAuction、MyNFT 等) 的事件和错误定义, 以及常用 OpenZeppelin 合约的自定义错误;
+// 合约绑定代码通过 Register 注册完整的 ABI, 用户可以通过 LoadFile 加载 ABI 文件。
+// 注册表中找不到的事件按内置的签名数据库 (ERC20、ERC721、代理升级等常用事件) 解码
+package decoder
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// knownABIsJSON 内置的项目合约事件和错误定义, 按合约名分组, 每组是一个 ABI 数组
+//
+//go:embed abis.json
+var knownABIsJSON []byte
+
+// namedABI 带合约名的 ABI
+type namedABI struct {
+	name string
+	abi  *abi.ABI
+}
+
+// Registry 已知合约 ABI 的注册表, 可以并发使用
+type Registry struct {
+	mu   sync.RWMutex
+	abis []namedABI
+}
+
+// NewRegistry 创建空的注册表
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register 注册合约 ABI, 同名的 ABI 会被替换; 选择器冲突时后注册的优先, 用户加载的 ABI 覆盖内置定义
+func (r *Registry) Register(name string, contractABI *abi.ABI) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.abis {
+		if r.abis[i].name == name {
+			r.abis[i].abi = contractABI
+			return
+		}
+	}
+	r.abis = append(r.abis, namedABI{name: name, abi: contractABI})
+}
+
+// RegisterJSON 解析 ABI JSON 并注册
+func (r *Registry) RegisterJSON(name string, data []byte) error {
+	contractABI, err := abi.JSON(strings.NewReader(string(data)))
+	if err != nil {
+		return fmt.Errorf("解析合约 %s 的 ABI 失败: %w", name, err)
+	}
+	r.Register(name, &contractABI)
+	return nil
+}
+
+// findError 按选择器查找自定义错误, 返回定义该错误的合约名
+func (r *Registry) findError(selector [4]byte) (string, *abi.Error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, entry := range slices.Backward(r.abis) {
+		if e, err := entry.abi.ErrorByID(selector); err == nil {
+			return entry.name, e
+		}
+	}
+	return "", nil
+}
+
+// LoadFile 加载 ABI 文件并注册, 返回注册的合约名
+func (r *Registry) LoadFile(path string) (string, error) {
+	name, contractABI, err := ParseFile(path)
+	if err != nil {
+		return "", err
+	}
+	r.Register(name, contractABI)
+	return name, nil
+}
+
+// ParseFile 解析 ABI 文件, 支持 ABI 数组和 Hardhat / Foundry 编译产物 (包含 abi 字段的对象)
+// 合约名取编译产物的 contractName, 没有时使用文件名
+func ParseFile(path string) (string, *abi.ABI, error) {
+	name, data, err := ReadFile(path)
+	if err != nil {
+		return "", nil, err
+	}
+	contractABI, err := abi.JSON(bytes.NewReader(data))
+	if err != nil {
+		return "", nil, fmt.Errorf("解析合约 %s 的 ABI 失败: %w", name, err)
+	}
+	return name, &contractABI, nil
+}
+
+// ReadFile 读取 ABI 文件, 返回合约名和 ABI 数组的原始 JSON
+func ReadFile(path string) (string, []byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", nil, fmt.Errorf("读取 ABI 文件失败: %w", err)
+	}
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		var artifact struct {
+			ContractName string          `json:"contractName"`
+			ABI          json.RawMessage `json:"abi"`
+		}
+		if err := json.Unmarshal(trimmed, &artifact); err != nil {
+			return "", nil, fmt.Errorf("解析 ABI 文件 %s 失败: %w", path, err)
+		}
+		if len(artifact.ABI) == 0 {
+			return "", nil, fmt.Errorf("ABI 文件 %s 中没有 abi 字段", path)
+		}
+		if artifact.ContractName != "" {
+			name = artifact.ContractName
+		}
+		data = artifact.ABI
+	}
+	return name, data, nil
+}
+
+// findEvent 按 topic0 查找事件, 要求索引参数的数量与日志的 topic 数量一致
+func (r *Registry) findEvent(topic common.Hash, indexed int) (string, *abi.Event) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, entry := range slices.Backward(r.abis) {
+		if e, err := entry.abi.EventByID(topic); err == nil && countIndexed(e) == indexed {
+			return entry.name, e
+		}
+	}
+	return "", nil
+}
+
+func countIndexed(e *abi.Event) int {
+	n := 0
+	for _, input := range e.Inputs {
+		if input.Indexed {
+			n++
+		}
+	}
+	return n
+}
+
+// signaturesJSON 内置的常用事件签名数据库, 用于解码未注册合约的日志
+// 同一签名可能有不同的索引方式 (如 ERC20 和 ERC721 的 Transfer), 按 topic 数量区分
+//
+//go:embed signatures.json
+var signaturesJSON []byte
+
+// signatures topic0 到候选事件的映射
+var signatures = loadSignatures()
+
+func loadSignatures() map[common.Hash][]*abi.Event {
+	var entries []json.RawMessage
+	if err := json.Unmarshal(signaturesJSON, &entries); err != nil {
+		panic(fmt.Errorf("内置的事件签名格式错误: %w", err))
+	}
+	events := make(map[common.Hash][]*abi.Event)
+	for _, entry := range entries {
+		// 逐条解析, 避免同名事件被 abi.JSON 重命名
+		parsed, err := abi.JSON(bytes.NewReader(append(append([]byte("["), entry...), ']')))
+		if err != nil {
+			panic(fmt.Errorf("内置的事件签名格式错误: %w", err))
+		}
+		for _, e := range parsed.Events {
+			events[e.ID] = append(events[e.ID], &e)
+		}
+	}
+	return events
+}
+
+// lookupSignature 在签名数据库中查找事件
+func lookupSignature(topic common.Hash, indexed int) *abi.Event {
+	for _, e := range signatures[topic] {
+		if countIndexed(e) == indexed {
+			return e
+		}
+	}
+	return nil
+}
+
+// defaultRegistry 默认注册表, 包含内置的项目合约事件和错误定义
+var defaultRegistry = newDefaultRegistry()
+
+func newDefaultRegistry() *Registry {
+	r := NewRegistry()
+	var groups map[string]json.RawMessage
+	if err := json.Unmarshal(knownABIsJSON, &groups); err != nil {
+		panic(fmt.Errorf("内置的 ABI 定义格式错误: %w", err))
+	}
+	// 按合约名排序, 保证选择器冲突时的结果稳定
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		if err := r.RegisterJSON(name, groups[name]); err != nil {
+			panic(err)
+		}
+	}
+	return r
+}
+
+// Default 返回默认注册表
+func Default() *Registry {
+	return defaultRegistry
+}
+
+// LoadFile 加载 ABI 文件并注册到默认注册表
+func LoadFile(path string) (string, error) {
+	return defaultRegistry.LoadFile(path)
+}
+
+// Register 向默认注册表注册合约 ABI
+func Register(name string, contractABI *abi.ABI) {
+	defaultRegistry.Register(name, contractABI)
+}
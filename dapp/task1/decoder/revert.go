@@ -0,0 +1,230 @@
+package decoder
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+	"task1/errs"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	// errorSelector require(cond, "message") 和 revert("message") 使用的 Error(string)
+	errorSelector = crypto.Keccak256([]byte("Error(string)"))[:4]
+	// panicSelector assert 失败、溢出等编译器插入的检查使用的 Panic(uint256)
+	panicSelector = crypto.Keccak256([]byte("Panic(uint256)"))[:4]
+)
+
+var (
+	stringArgs = abi.Arguments{{Type: mustNewType("string")}}
+	uintArgs   = abi.Arguments{{Type: mustNewType("uint256")}}
+)
+
+func mustNewType(t string) abi.Type {
+	typ, err := abi.NewType(t, "", nil)
+	if err != nil {
+		panic(err)
+	}
+	return typ
+}
+
+// panicReasons Solidity 0.8 的 Panic 错误码
+var panicReasons = map[uint64]string{
+	0x00: "编译器插入的通用 panic",
+	0x01: "assert 条件不成立",
+	0x11: "算术运算溢出",
+	0x12: "除以 0 或对 0 取模",
+	0x21: "转换为枚举类型时值越界",
+	0x22: "存储中的字节数组编码错误",
+	0x31: "对空数组调用 pop()",
+	0x32: "数组下标越界",
+	0x41: "分配的内存过大",
+	0x51: "调用未初始化的内部函数",
+}
+
+// Arg 解码后的自定义错误或事件参数
+type Arg struct {
+	Name    string `json:"name,omitempty"`
+	Type    string `json:"type"`
+	Value   any    `json:"value"`
+	Indexed bool   `json:"indexed,omitempty"` // 事件的索引参数
+}
+
+// MarshalJSON 大整数和字节数组输出为字符串, 避免 JSON 数字丢失精度和字节数组被编码为 base64
+func (a Arg) MarshalJSON() ([]byte, error) {
+	type arg Arg
+	out := arg(a)
+	out.Value = jsonValue(a.Value)
+	return json.Marshal(out)
+}
+
+// jsonValue 递归转换 ABI 解码出的值, 数组转换为 JSON 数组, tuple 转换为按字段名的 JSON 对象
+func jsonValue(value any) any {
+	switch value.(type) {
+	case *big.Int, []byte, common.Address:
+		return FormatValue(value)
+	}
+	rv := reflect.ValueOf(value)
+	switch {
+	case !rv.IsValid():
+		return value
+	case isByteArray(value):
+		return FormatValue(value)
+	case rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array:
+		items := make([]any, rv.Len())
+		for i := range items {
+			items[i] = jsonValue(rv.Index(i).Interface())
+		}
+		return items
+	case rv.Kind() == reflect.Struct:
+		fields := make(map[string]any, rv.NumField())
+		for i := 0; i < rv.NumField(); i++ {
+			fields[fieldName(rv.Type().Field(i))] = jsonValue(rv.Field(i).Interface())
+		}
+		return fields
+	}
+	return value
+}
+
+// fieldName 返回 tuple 字段在 Solidity 中的名称, abi 包生成的结构体在 json 标签中保留了原始名称
+func fieldName(field reflect.StructField) string {
+	if name, _, _ := strings.Cut(field.Tag.Get("json"), ","); name != "" {
+		return name
+	}
+	return field.Name
+}
+
+// Revert 解码后的回滚原因
+type Revert struct {
+	Data     []byte // 原始回滚数据
+	Contract string // 定义自定义错误的合约, Error / Panic 为空
+	Name     string // Error、Panic 或自定义错误名称, 无法识别时为空
+	Args     []Arg  // 自定义错误的参数
+	Reason   string // Error(string) 的消息或 Panic 错误码的说明
+}
+
+// DecodeRevert 解码回滚数据, 依次尝试 Error(string)、Panic(uint256) 和注册表中的自定义错误
+// 无法识别时返回只包含原始数据的 Revert
+func (r *Registry) DecodeRevert(data []byte) *Revert {
+	revert := &Revert{Data: data}
+	if len(data) < 4 {
+		return revert
+	}
+	switch {
+	case bytes.Equal(data[:4], errorSelector):
+		if values, err := stringArgs.Unpack(data[4:]); err == nil {
+			revert.Name, revert.Reason = "Error", values[0].(string)
+		}
+	case bytes.Equal(data[:4], panicSelector):
+		if values, err := uintArgs.Unpack(data[4:]); err == nil {
+			code := values[0].(*big.Int)
+			revert.Name, revert.Reason = "Panic", fmt.Sprintf("错误码 %#x", code)
+			if reason, ok := panicReasons[code.Uint64()]; code.IsUint64() && ok {
+				revert.Reason = fmt.Sprintf("%s (错误码 %#x)", reason, code)
+			}
+		}
+	default:
+		contract, e := r.findError([4]byte(data[:4]))
+		if e == nil {
+			return revert
+		}
+		values, err := e.Inputs.Unpack(data[4:])
+		if err != nil {
+			return revert
+		}
+		revert.Contract, revert.Name = contract, e.Name
+		for i, input := range e.Inputs {
+			revert.Args = append(revert.Args, Arg{Name: input.Name, Type: input.Type.String(), Value: values[i]})
+		}
+	}
+	return revert
+}
+
+// DecodeRevert 使用默认注册表解码回滚数据
+func DecodeRevert(data []byte) *Revert {
+	return defaultRegistry.DecodeRevert(data)
+}
+
+// FromError 从节点返回的 execution reverted 错误中提取并解码回滚数据, 错误不带回滚数据时返回 false
+func FromError(err error) (*Revert, bool) {
+	data, ok := errs.RevertData(err)
+	if !ok {
+		return nil, false
+	}
+	return DecodeRevert(data), true
+}
+
+// Known 判断是否识别了回滚数据
+func (r *Revert) Known() bool {
+	return r.Name != ""
+}
+
+// String 返回回滚原因的可读描述, 例如 Error("余额不足")、Panic: 算术运算溢出、NFTAuction.BidTooLow(convertedBidAmount=1, ...)
+func (r *Revert) String() string {
+	switch {
+	case len(r.Data) == 0:
+		return "没有回滚数据 (revert() 或 require 未提供错误信息)"
+	case r.Name == "Error":
+		return fmt.Sprintf("Error(%q)", r.Reason)
+	case r.Name == "Panic":
+		return "Panic: " + r.Reason
+	case r.Name == "":
+		return fmt.Sprintf("未知的自定义错误 %s (回滚数据 %s)", hexutil.Encode(r.Data[:min(4, len(r.Data))]), hexutil.Encode(r.Data))
+	}
+	args := make([]string, len(r.Args))
+	for i, arg := range r.Args {
+		args[i] = FormatValue(arg.Value)
+		if arg.Name != "" {
+			args[i] = arg.Name + "=" + args[i]
+		}
+	}
+	return fmt.Sprintf("%s.%s(%s)", r.Contract, r.Name, strings.Join(args, ", "))
+}
+
+// FormatValue 格式化 ABI 解码出的值, 字节和定长字节数组 (bytes1 ~ bytes32) 输出为十六进制,
+// 数组输出为 [a, b], tuple 输出为 (name=a, name=b)
+func FormatValue(value any) string {
+	switch v := value.(type) {
+	case []byte:
+		return hexutil.Encode(v)
+	case common.Address:
+		return v.Hex()
+	case *big.Int:
+		return v.String()
+	}
+	rv := reflect.ValueOf(value)
+	switch {
+	case !rv.IsValid():
+		return fmt.Sprint(value)
+	case isByteArray(value):
+		b := make([]byte, rv.Len())
+		reflect.Copy(reflect.ValueOf(b), rv)
+		return hexutil.Encode(b)
+	case rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array:
+		items := make([]string, rv.Len())
+		for i := range items {
+			items[i] = FormatValue(rv.Index(i).Interface())
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case rv.Kind() == reflect.Struct:
+		fields := make([]string, rv.NumField())
+		for i := range fields {
+			fields[i] = fieldName(rv.Type().Field(i)) + "=" + FormatValue(rv.Field(i).Interface())
+		}
+		return "(" + strings.Join(fields, ", ") + ")"
+	}
+	return fmt.Sprint(value)
+}
+
+// isByteArray 判断是否为 ABI 定长字节类型解码出的 [N]byte
+func isByteArray(value any) bool {
+	t := reflect.TypeOf(value)
+	return t != nil && t.Kind() == reflect.Array && t.Elem().Kind() == reflect.Uint8
+}
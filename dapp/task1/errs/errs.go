@@ -0,0 +1,363 @@
+// Package errs 将 go-ethereum 返回的错误归类为带重试策略的类型化错误
+//
+// 错误来源包括:
+//   - JSON-RPC 错误 (rpc.Error / rpc.DataError) 的错误码和节点返回的交易池错误
+//   - HTTP 状态码 (rpc.HTTPError)
+//   - 网络错误 (net.Error、连接被拒绝/重置、EOF 等)
+//
+// 调用方通过 errors.Is(err, errs.ErrNonceTooLow) 判断类型, 通过 PolicyOf(err) 获取重试策略
+package errs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/gorilla/websocket"
+)
+
+// RetryPolicy 错误的重试策略
+type RetryPolicy struct {
+	Retryable   bool          // 是否可以原样重试同一个请求
+	Resync      bool          // 需要重新获取 nonce / gas 价格等状态后重新构建交易再发送
+	Failover    bool          // 端点相关的错误, 换一个端点可能成功
+	MaxAttempts int           // 最大尝试次数(包含第一次)
+	Backoff     time.Duration // 首次重试间隔, 之后按指数增长
+	MaxBackoff  time.Duration // 最大重试间隔
+}
+
+// Delay 返回第 attempt 次重试(从 1 开始)前的等待时间
+func (p RetryPolicy) Delay(attempt int) time.Duration {
+	delay := p.Backoff
+	for i := 1; i < attempt && delay < p.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+	return delay
+}
+
+// Kind 错误类型, 同时作为哨兵错误用于 errors.Is 比较
+type Kind struct {
+	name   string
+	desc   string
+	policy RetryPolicy
+}
+
+func (k *Kind) Error() string { return k.desc }
+
+// Name 返回错误类型的英文名称
+func (k *Kind) Name() string { return k.name }
+
+// Policy 返回该类型的重试策略
+func (k *Kind) Policy() RetryPolicy { return k.policy }
+
+func newKind(name, desc string, policy RetryPolicy) *Kind {
+	return &Kind{name: name, desc: desc, policy: policy}
+}
+
+var (
+	// 端点或网络问题, 可以重试或切换端点
+	transient = RetryPolicy{Retryable: true, Failover: true, MaxAttempts: 5, Backoff: time.Second, MaxBackoff: 10 * time.Second}
+	// 需要重新构建交易
+	resync = RetryPolicy{Resync: true, MaxAttempts: 3, Backoff: time.Second, MaxBackoff: 5 * time.Second}
+	// 不可重试
+	permanent = RetryPolicy{MaxAttempts: 1}
+)
+
+var (
+	ErrNetwork      = newKind("network", "网络错误", transient)
+	ErrTimeout      = newKind("timeout", "请求超时", transient)
+	ErrRateLimited  = newKind("rate_limited", "请求频率超限", RetryPolicy{Retryable: true, Failover: true, MaxAttempts: 6, Backoff: 2 * time.Second, MaxBackoff: 30 * time.Second})
+	ErrUnavailable  = newKind("unavailable", "节点暂时不可用", transient)
+	ErrUnauthorized = newKind("unauthorized", "节点拒绝访问(请检查 API_KEY)", RetryPolicy{Failover: true, MaxAttempts: 1})
+	// ErrNotFound 查询的数据不存在, 等待交易时表示交易尚未被节点收到或打包
+	ErrNotFound = newKind("not_found", "数据不存在", RetryPolicy{Retryable: true, MaxAttempts: 10, Backoff: 2 * time.Second, MaxBackoff: 10 * time.Second})
+
+	ErrNonceTooLow            = newKind("nonce_too_low", "nonce 过低", resync)
+	ErrNonceTooHigh           = newKind("nonce_too_high", "nonce 过高", resync)
+	ErrUnderpriced            = newKind("underpriced", "gas 价格过低", resync)
+	ErrReplacementUnderpriced = newKind("replacement_underpriced", "替换交易的 gas 价格过低", resync)
+	// ErrAlreadyKnown 节点已经收到过该交易, 发送路径中可视为成功
+	ErrAlreadyKnown = newKind("already_known", "交易已存在", permanent)
+
+	ErrInsufficientFunds = newKind("insufficient_funds", "余额不足", permanent)
+	ErrExecutionReverted = newKind("execution_reverted", "合约执行回滚", permanent)
+	ErrGas               = newKind("gas", "gas 限制错误", permanent)
+	ErrInvalidRequest    = newKind("invalid_request", "无效的请求", permanent)
+	ErrMethodNotFound    = newKind("method_not_found", "节点不支持该方法", RetryPolicy{Failover: true, MaxAttempts: 1})
+	// ErrNotificationsUnsupported HTTP 连接不支持订阅, 所有端点结果相同, 不切换端点
+	ErrNotificationsUnsupported = newKind("notifications_unsupported", "连接不支持订阅", permanent)
+	ErrCanceled                 = newKind("canceled", "操作已取消", permanent)
+	ErrUnknown                  = newKind("unknown", "未知错误", permanent)
+)
+
+// Error 分类后的错误, 保留原始错误和 JSON-RPC / HTTP 上下文
+type Error struct {
+	Kind       *Kind
+	Code       int   // JSON-RPC 错误码, 0 表示没有
+	HTTPStatus int   // HTTP 状态码, 0 表示没有
+	Data       any   // rpc.DataError 携带的数据, 例如回滚数据
+	Err        error // 原始错误
+}
+
+func (e *Error) Error() string {
+	switch {
+	case e.Code != 0:
+		return fmt.Sprintf("%s (code %d): %v", e.Kind.desc, e.Code, e.Err)
+	case e.HTTPStatus != 0:
+		return fmt.Sprintf("%s (HTTP %d): %v", e.Kind.desc, e.HTTPStatus, e.Err)
+	}
+	return fmt.Sprintf("%s: %v", e.Kind.desc, e.Err)
+}
+
+// Unwrap 同时暴露错误类型和原始错误, 支持 errors.Is(err, errs.ErrXxx) 和 errors.As(err, &rpcErr)
+func (e *Error) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+// Policy 返回该错误的重试策略
+func (e *Error) Policy() RetryPolicy {
+	return e.Kind.policy
+}
+
+// 节点只以 -32000 + 错误信息的形式返回交易池和执行错误, 这里与 go-ethereum 导出的错误文本比对
+// 其他客户端(Erigon、Nethermind、Besu)的文本基本兼容
+var messageKinds = []struct {
+	kind     *Kind
+	messages []string
+}{
+	{ErrNonceTooLow, []string{core.ErrNonceTooLow.Error()}},
+	{ErrNonceTooHigh, []string{core.ErrNonceTooHigh.Error()}},
+	{ErrReplacementUnderpriced, []string{txpool.ErrReplaceUnderpriced.Error()}},
+	{ErrUnderpriced, []string{txpool.ErrUnderpriced.Error(), txpool.ErrTxGasPriceTooLow.Error(), core.ErrFeeCapTooLow.Error(), core.ErrTipAboveFeeCap.Error()}},
+	{ErrAlreadyKnown, []string{txpool.ErrAlreadyKnown.Error(), "known transaction"}},
+	{ErrInsufficientFunds, []string{core.ErrInsufficientFunds.Error(), core.ErrInsufficientFundsForTransfer.Error(), "insufficient funds"}},
+	{ErrGas, []string{core.ErrIntrinsicGas.Error(), txpool.ErrGasLimit.Error(), core.ErrGasLimitReached.Error(), "gas required exceeds allowance"}},
+	{ErrExecutionReverted, []string{"execution reverted"}},
+	{ErrRateLimited, []string{"rate limit", "too many requests"}},
+}
+
+// Classify 将错误归类为 *Error, nil 返回 nil, 已经归类的错误原样返回
+func Classify(err error) error {
+	if err == nil {
+		return nil
+	}
+	var classified *Error
+	if errors.As(err, &classified) {
+		return err
+	}
+	return classify(err)
+}
+
+func classify(err error) *Error {
+	e := &Error{Kind: ErrUnknown, Err: err}
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		e.Kind = ErrCanceled
+		return e
+	case errors.Is(err, context.DeadlineExceeded):
+		e.Kind = ErrTimeout
+		return e
+	case errors.Is(err, ethereum.NotFound):
+		e.Kind = ErrNotFound
+		return e
+	case errors.Is(err, rpc.ErrNotificationsUnsupported):
+		e.Kind = ErrNotificationsUnsupported
+		return e
+	}
+
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		e.HTTPStatus = httpErr.StatusCode
+		switch {
+		case httpErr.StatusCode == 429:
+			e.Kind = ErrRateLimited
+		case httpErr.StatusCode == 401 || httpErr.StatusCode == 403:
+			e.Kind = ErrUnauthorized
+		case httpErr.StatusCode == 408 || httpErr.StatusCode == 504:
+			e.Kind = ErrTimeout
+		case httpErr.StatusCode >= 500:
+			e.Kind = ErrUnavailable
+		}
+		return e
+	}
+
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		e.Code = rpcErr.ErrorCode()
+		var dataErr rpc.DataError
+		if errors.As(err, &dataErr) {
+			e.Data = dataErr.ErrorData()
+		}
+		switch e.Code {
+		case 3: // 带回滚数据的 execution reverted
+			e.Kind = ErrExecutionReverted
+			return e
+		case -32005: // EIP-1474: limit exceeded
+			e.Kind = ErrRateLimited
+			return e
+		case -32002: // 节点处理请求超时
+			e.Kind = ErrTimeout
+			return e
+		case -32601:
+			e.Kind = ErrMethodNotFound
+			return e
+		case -32600, -32602, -32700:
+			e.Kind = ErrInvalidRequest
+			return e
+		case -32603:
+			e.Kind = ErrUnavailable
+		}
+		if kind := kindFromMessage(rpcErr.Error()); kind != nil {
+			e.Kind = kind
+		}
+		return e
+	}
+
+	if isNetError(err) {
+		e.Kind = ErrNetwork
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			e.Kind = ErrTimeout
+		}
+		return e
+	}
+
+	// 本地模拟执行(如 bind 估算 gas)返回的错误不一定是 rpc.Error
+	if kind := kindFromMessage(err.Error()); kind != nil {
+		e.Kind = kind
+	}
+	return e
+}
+
+// kindFromMessage 根据节点返回的错误信息判断类型
+func kindFromMessage(msg string) *Kind {
+	msg = strings.ToLower(msg)
+	for _, mk := range messageKinds {
+		for _, m := range mk.messages {
+			if strings.Contains(msg, strings.ToLower(m)) {
+				return mk.kind
+			}
+		}
+	}
+	return nil
+}
+
+// isNetError 判断是否为传输层错误
+func isNetError(err error) bool {
+	var netErr net.Error
+	var closeErr *websocket.CloseError
+	return errors.As(err, &netErr) ||
+		errors.As(err, &closeErr) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, rpc.ErrClientQuit)
+}
+
+// KindOf 返回错误类型, nil 返回 nil
+func KindOf(err error) *Kind {
+	if err == nil {
+		return nil
+	}
+	var classified *Error
+	if errors.As(err, &classified) {
+		return classified.Kind
+	}
+	var kind *Kind
+	if errors.As(err, &kind) {
+		return kind
+	}
+	return classify(err).Kind
+}
+
+// RevertData 返回合约回滚错误携带的回滚数据 (节点以十六进制字符串返回), 没有回滚数据时返回 false
+func RevertData(err error) ([]byte, bool) {
+	var dataErr rpc.DataError
+	if !errors.As(err, &dataErr) {
+		return nil, false
+	}
+	hex, ok := dataErr.ErrorData().(string)
+	if !ok {
+		return nil, false
+	}
+	data, decodeErr := hexutil.Decode(hex)
+	if decodeErr != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+// PolicyOf 返回错误的重试策略
+func PolicyOf(err error) RetryPolicy {
+	if kind := KindOf(err); kind != nil {
+		return kind.policy
+	}
+	return permanent
+}
+
+// IsRetryable 判断错误是否可以原样重试
+func IsRetryable(err error) bool {
+	return PolicyOf(err).Retryable
+}
+
+// classifyKnown 归类能识别的错误, 无法识别的错误(如参数错误)原样返回
+func classifyKnown(err error) error {
+	if classified := Classify(err); KindOf(classified) != ErrUnknown {
+		return classified
+	}
+	return err
+}
+
+// permanentError 标记不再重试的错误
+type permanentError struct{ err error }
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent 包装错误, 使 Retry 不再重试并直接返回该错误(能识别的错误会被归类)
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err}
+}
+
+// Retry 按错误的重试策略执行 fn, 直到成功、遇到不可重试的错误或超过最大尝试次数
+// fn 的参数为当前尝试次数(从 1 开始); 需要重新构建交易的错误(Resync)也会重试, 由 fn 负责重新构建
+func Retry(ctx context.Context, fn func(attempt int) error) error {
+	for attempt := 1; ; attempt++ {
+		err := fn(attempt)
+		if err == nil {
+			return nil
+		}
+		var pe *permanentError
+		if errors.As(err, &pe) {
+			return classifyKnown(pe.err)
+		}
+		policy := PolicyOf(err)
+		if !policy.Retryable && !policy.Resync || attempt >= policy.MaxAttempts {
+			return classifyKnown(err)
+		}
+		select {
+		case <-ctx.Done():
+			return classifyKnown(errors.Join(ctx.Err(), err))
+		case <-time.After(policy.Delay(attempt)):
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package errs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"reflect"
+	"syscall"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// rpcError 节点返回的 JSON-RPC 错误, 实现 rpc.Error 和 rpc.DataError
+type rpcError struct {
+	code int
+	msg  string
+	data any
+}
+
+func (e *rpcError) Error() string          { return e.msg }
+func (e *rpcError) ErrorCode() int         { return e.code }
+func (e *rpcError) ErrorData() interface{} { return e.data }
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want *Kind
+	}{
+		// JSON-RPC 错误码
+		{"回滚数据", &rpcError{3, "execution reverted", "0x08c379a0"}, ErrExecutionReverted},
+		{"EIP-1474 limit exceeded", &rpcError{-32005, "limit exceeded", nil}, ErrRateLimited},
+		{"节点处理超时", &rpcError{-32002, "request timed out", nil}, ErrTimeout},
+		{"方法不存在", &rpcError{-32601, "the method eth_foo does not exist/is not available", nil}, ErrMethodNotFound},
+		{"参数错误", &rpcError{-32602, "invalid argument 0: hex string without 0x prefix", nil}, ErrInvalidRequest},
+		{"节点内部错误", &rpcError{-32603, "internal error", nil}, ErrUnavailable},
+		{"内部错误中的交易池信息", &rpcError{-32603, "nonce too low", nil}, ErrNonceTooLow},
+		{"未知错误码", &rpcError{-32099, "something else", nil}, ErrUnknown},
+
+		// -32000 + 交易池和执行错误信息
+		{"nonce 过低", &rpcError{-32000, core.ErrNonceTooLow.Error() + ": address 0x1, tx: 1 state: 2", nil}, ErrNonceTooLow},
+		{"nonce 过高", &rpcError{-32000, core.ErrNonceTooHigh.Error(), nil}, ErrNonceTooHigh},
+		{"替换交易价格过低", &rpcError{-32000, txpool.ErrReplaceUnderpriced.Error(), nil}, ErrReplacementUnderpriced},
+		{"交易价格过低", &rpcError{-32000, txpool.ErrUnderpriced.Error(), nil}, ErrUnderpriced},
+		{"fee cap 过低", &rpcError{-32000, "max fee per gas less than block base fee: address 0x1, maxFeePerGas: 1, baseFee: 2", nil}, ErrUnderpriced},
+		{"交易已存在", &rpcError{-32000, txpool.ErrAlreadyKnown.Error(), nil}, ErrAlreadyKnown},
+		{"Nethermind 交易已存在", &rpcError{-32010, "Known transaction", nil}, ErrAlreadyKnown},
+		{"余额不足", &rpcError{-32000, "insufficient funds for gas * price + value: balance 0, tx cost 1", nil}, ErrInsufficientFunds},
+		{"gas 不足", &rpcError{-32000, core.ErrIntrinsicGas.Error(), nil}, ErrGas},
+		{"gas 超出上限", &rpcError{-32000, "gas required exceeds allowance (30000000)", nil}, ErrGas},
+		{"没有回滚数据的回滚", &rpcError{-32000, "execution reverted", nil}, ErrExecutionReverted},
+		{"服务商的频率限制", &rpcError{-32000, "Your app has exceeded its compute units per second capacity, rate limit", nil}, ErrRateLimited},
+
+		// HTTP 状态码
+		{"HTTP 429", rpc.HTTPError{StatusCode: 429, Status: "429 Too Many Requests"}, ErrRateLimited},
+		{"HTTP 401", rpc.HTTPError{StatusCode: 401, Status: "401 Unauthorized"}, ErrUnauthorized},
+		{"HTTP 403", rpc.HTTPError{StatusCode: 403, Status: "403 Forbidden"}, ErrUnauthorized},
+		{"HTTP 408", rpc.HTTPError{StatusCode: 408, Status: "408 Request Timeout"}, ErrTimeout},
+		{"HTTP 504", rpc.HTTPError{StatusCode: 504, Status: "504 Gateway Timeout"}, ErrTimeout},
+		{"HTTP 502", rpc.HTTPError{StatusCode: 502, Status: "502 Bad Gateway"}, ErrUnavailable},
+		{"HTTP 404", rpc.HTTPError{StatusCode: 404, Status: "404 Not Found"}, ErrUnknown},
+		{"包装的 HTTP 错误", fmt.Errorf("查询失败: %w", rpc.HTTPError{StatusCode: 503}), ErrUnavailable},
+
+		// 网络和上下文错误
+		{"连接被拒绝", &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}, ErrNetwork},
+		{"连接被重置", fmt.Errorf("post: %w", syscall.ECONNRESET), ErrNetwork},
+		{"EOF", io.ErrUnexpectedEOF, ErrNetwork},
+		{"网络超时", &net.DNSError{Err: "i/o timeout", IsTimeout: true}, ErrTimeout},
+		{"上下文超时", fmt.Errorf("等待: %w", context.DeadlineExceeded), ErrTimeout},
+		{"上下文取消", context.Canceled, ErrCanceled},
+		{"数据不存在", ethereum.NotFound, ErrNotFound},
+		{"不支持订阅", rpc.ErrNotificationsUnsupported, ErrNotificationsUnsupported},
+
+		// 本地模拟执行返回的普通错误
+		{"本地 nonce 过低", errors.New("nonce too low"), ErrNonceTooLow},
+		{"无法识别", errors.New("boom"), ErrUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Classify(tt.err)
+			if !errors.Is(err, tt.want) || KindOf(err) != tt.want {
+				t.Fatalf("Classify(%v) 为 %v (%s), 预期 %s", tt.err, err, KindOf(err).Name(), tt.want.Name())
+			}
+			// 保留原始错误
+			var classified *Error
+			if !errors.As(err, &classified) || !reflect.DeepEqual(classified.Err, tt.err) {
+				t.Fatalf("分类后的错误 %v 丢失了原始错误 %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestClassifyContext(t *testing.T) {
+	if Classify(nil) != nil {
+		t.Fatal("Classify(nil) 应当返回 nil")
+	}
+	err := Classify(&rpcError{3, "execution reverted", "0x08c379a0"})
+	var classified *Error
+	if !errors.As(err, &classified) || classified.Code != 3 || classified.Data != "0x08c379a0" {
+		t.Fatalf("错误码和回滚数据: %+v", classified)
+	}
+	if data, ok := RevertData(err); !ok || len(data) != 4 {
+		t.Fatalf("回滚数据为 %x, %v", data, ok)
+	}
+	if Classify(err) != err {
+		t.Fatal("已经归类的错误应当原样返回")
+	}
+	httpErr := Classify(rpc.HTTPError{StatusCode: 429})
+	if !errors.As(httpErr, &classified) || classified.HTTPStatus != 429 {
+		t.Fatalf("HTTP 状态码: %+v", classified)
+	}
+	if !IsRetryable(httpErr) || !PolicyOf(httpErr).Failover {
+		t.Fatalf("频率超限的重试策略 %+v", PolicyOf(httpErr))
+	}
+	if IsRetryable(Classify(&rpcError{-32000, "insufficient funds", nil})) {
+		t.Fatal("余额不足不能重试")
+	}
+}
@@ -0,0 +1,123 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"task1/contracts"
+	"task1/errs"
+	"task1/util"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// EIP-1967 存储槽: bytes32(uint256(keccak256("eip1967.proxy.*")) - 1)
+var (
+	ImplementationSlot = common.HexToHash("0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc")
+	AdminSlot          = common.HexToHash("0xb53127684a568b3173ae13b9f8a6016e243e63b6e8ee1178d6a717850b5d6103")
+	BeaconSlot         = common.HexToHash("0xa3f0ad74e5423aebfd80d3ef4346578335a9a72aeaee59ff6cb3582b35133d50")
+)
+
+// ProxyName 内置 ERC1967 代理合约的名称
+const ProxyName = "ERC1967Proxy"
+
+// erc1967ABI 代理的构造函数与 OpenZeppelin ERC1967Proxy 相同, 另外包含 UUPS 实现合约的升级接口
+const erc1967ABI = `[
+	{"type":"constructor","stateMutability":"payable","inputs":[{"name":"implementation","type":"address"},{"name":"_data","type":"bytes"}]},
+	{"type":"function","name":"proxiableUUID","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"bytes32"}]},
+	{"type":"function","name":"upgradeToAndCall","stateMutability":"payable","inputs":[{"name":"newImplementation","type":"address"},{"name":"data","type":"bytes"}],"outputs":[]},
+	{"type":"event","name":"Upgraded","anonymous":false,"inputs":[{"name":"implementation","type":"address","indexed":true}]},
+	{"type":"error","name":"ERC1967InvalidImplementation","inputs":[{"name":"implementation","type":"address"}]},
+	{"type":"error","name":"ERC1967NonPayable","inputs":[]}
+]`
+
+// erc1967Bytecode 内置的最小 ERC1967 代理, 行为与 OpenZeppelin ERC1967Proxy 一致, 需要支持 PUSH0 (Shanghai) 的链
+//
+// 构造函数 (222 字节):
+//
+//	codecopy(0, 281, codesize - 281)                  // 构造函数参数 abi.encode(implementation, _data)
+//	if extcodesize(implementation) == 0: revert ERC1967InvalidImplementation(implementation)
+//	sstore(ImplementationSlot, implementation)
+//	log2(0, 0, Upgraded, implementation)
+//	if len(_data) > 0: delegatecall(gas, implementation, _data), 失败时原样返回回滚数据
+//	else if callvalue > 0: revert ERC1967NonPayable()
+//	return 运行时代码
+//
+// 运行时代码 (59 字节): 将调用数据原样 delegatecall 到 sload(ImplementationSlot), 原样返回或回滚返回数据
+const erc1967Bytecode = "0x61011938036101195f395f51803b61003c577f4c9c8ce3000000000000000000000000000000000000000000000000000000005f5260045260245ffd5b" +
+	"807f360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc55" +
+	"807fbc7cd75a20ee27fd9adebab32041f755214dbc6bffa90cc0225b39da2e5c2d3b5f5fa2" +
+	"604051806100be5734610096576100d2565b7fb398979f000000000000000000000000000000000000000000000000000000005f5260045ffd" +
+	"5b5f5f826060855af46100d2573d5f5f3e3d5ffd5b61003b806100de5f395ff3" +
+	"365f5f375f5f365f7f360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc545af43d5f5f3e610037573d5ffd5b3d5ff3"
+
+// Artifact 返回代理合约的编译产物, path 为空时使用内置的 ERC1967 代理,
+// 否则加载 OpenZeppelin ERC1967Proxy 等构造函数为 (address, bytes) 的编译产物
+func Artifact(path string) (*contracts.Artifact, error) {
+	if path == "" {
+		return contracts.NewArtifact(ProxyName, []byte(erc1967ABI), erc1967Bytecode)
+	}
+	artifact, err := contracts.LoadArtifact(path, "")
+	if err != nil {
+		return nil, err
+	}
+	inputs := artifact.ABI.Constructor.Inputs
+	if len(inputs) != 2 || inputs[0].Type.T != abi.AddressTy || inputs[1].Type.T != abi.BytesTy {
+		return nil, fmt.Errorf("%w: 代理合约 %s 的构造函数需要是 (address implementation, bytes data)", util.ErrInvalidArgument, artifact.Name)
+	}
+	return artifact, nil
+}
+
+// uupsABI 代理和 UUPS 实现合约的升级接口
+var uupsABI = func() *abi.ABI {
+	artifact, err := contracts.NewArtifact(ProxyName, []byte(erc1967ABI), erc1967Bytecode)
+	if err != nil {
+		panic(err)
+	}
+	return artifact.ABI
+}()
+
+// Slots 代理合约 EIP-1967 存储槽中的地址, 未使用的槽为零地址
+// UUPS 和透明代理使用 Implementation, 透明代理的 ProxyAdmin 在 Admin, 信标代理使用 Beacon
+type Slots struct {
+	Implementation common.Address `json:"implementation"`
+	Admin          common.Address `json:"admin"`
+	Beacon         common.Address `json:"beacon"`
+}
+
+// ReadSlots 读取代理合约的 EIP-1967 存储槽
+func ReadSlots(ctx context.Context, client util.Client, proxy common.Address) (*Slots, error) {
+	var slots Slots
+	for _, slot := range []struct {
+		key  common.Hash
+		dest *common.Address
+	}{
+		{ImplementationSlot, &slots.Implementation},
+		{AdminSlot, &slots.Admin},
+		{BeaconSlot, &slots.Beacon},
+	} {
+		value, err := client.StorageAt(ctx, proxy, slot.key, nil)
+		if err != nil {
+			return nil, fmt.Errorf("读取代理 %s 的存储槽失败: %w", proxy.Hex(), errs.Classify(err))
+		}
+		*slot.dest = common.BytesToAddress(value)
+	}
+	return &slots, nil
+}
+
+// CheckUUPS 检查实现合约的 proxiableUUID() 是否返回 ImplementationSlot
+// OpenZeppelin UUPSUpgradeable 在 upgradeToAndCall 中做同样的检查, 提前检查可以避免部署后无法升级的代理
+func CheckUUPS(ctx context.Context, client util.Client, implementation common.Address) error {
+	method := uupsABI.Methods["proxiableUUID"]
+	ret, err := client.CallContract(ctx, ethereum.CallMsg{To: &implementation, Data: method.ID}, nil)
+	if err != nil && !errors.Is(errs.Classify(err), errs.ErrExecutionReverted) {
+		return fmt.Errorf("调用实现合约 %s 的 proxiableUUID 失败: %w", implementation.Hex(), errs.Classify(err))
+	}
+	if err != nil || len(ret) != common.HashLength || common.BytesToHash(ret) != ImplementationSlot {
+		return fmt.Errorf("%w: 实现合约 %s 不是 UUPS 合约 (proxiableUUID() 没有返回 ERC1967 实现槽), 通过代理升级会失败",
+			util.ErrInvalidArgument, implementation.Hex())
+	}
+	return nil
+}
@@ -0,0 +1,345 @@
+package proxy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"task1/util"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// StorageLayout 合约的存储布局, 格式与 solc 的 storageLayout 输出相同
+// OpenZeppelin 可升级合约 5.x 的 Initializable、OwnableUpgradeable 等使用 ERC-7201 命名空间存储, 不出现在布局中
+type StorageLayout struct {
+	Storage []StorageItem           `json:"storage"`
+	Types   map[string]*StorageType `json:"types"`
+}
+
+// StorageItem 状态变量或结构体成员, 结构体成员的 Slot 相对于结构体的起始槽
+type StorageItem struct {
+	Contract string `json:"contract,omitempty"`
+	Label    string `json:"label"`
+	Offset   int    `json:"offset"`
+	Slot     string `json:"slot"`
+	Type     string `json:"type"`
+}
+
+// StorageType 类型定义, Members 为结构体成员 ([]StorageItem) 或枚举值 ([]string, 只出现在 OpenZeppelin Upgrades 清单中)
+type StorageType struct {
+	Encoding      string          `json:"encoding,omitempty"`
+	Label         string          `json:"label"`
+	NumberOfBytes string          `json:"numberOfBytes"`
+	Key           string          `json:"key,omitempty"`
+	Value         string          `json:"value,omitempty"`
+	Base          string          `json:"base,omitempty"`
+	Members       json.RawMessage `json:"members,omitempty"`
+}
+
+// LoadLayout 读取存储布局, path 可以是:
+//   - Foundry 编译产物 (需要在 foundry.toml 中设置 extra_output = ["storageLayout"])
+//   - Hardhat 编译产物, 从同目录 .dbg.json 指向的 build-info 中读取 (需要 @openzeppelin/hardhat-upgrades 开启 storageLayout 输出)
+//   - OpenZeppelin Upgrades 清单 .openzeppelin/<网络>.json, 按实现合约地址 implementation 查找
+//   - 只包含 {"storage": [...], "types": {...}} 的布局文件, 如 forge inspect <合约> storageLayout --json 的输出
+func LoadLayout(path string, implementation common.Address) (*StorageLayout, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: 读取存储布局失败: %w", util.ErrConfig, err)
+	}
+	var file struct {
+		Storage         json.RawMessage `json:"storage"`
+		StorageLayout   json.RawMessage `json:"storageLayout"`
+		Format          string          `json:"_format"`
+		SourceName      string          `json:"sourceName"`
+		ContractName    string          `json:"contractName"`
+		ManifestVersion string          `json:"manifestVersion"`
+		Impls           map[string]struct {
+			Address common.Address  `json:"address"`
+			Layout  json.RawMessage `json:"layout"`
+		} `json:"impls"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("%w: 解析存储布局文件 %s 失败: %w", util.ErrConfig, path, err)
+	}
+	switch {
+	case file.Storage != nil:
+		return parseLayout(path, data)
+	case isLayout(file.StorageLayout):
+		return parseLayout(path, file.StorageLayout)
+	case file.ManifestVersion != "":
+		for _, impl := range file.Impls {
+			if impl.Address == implementation {
+				return parseLayout(path, impl.Layout)
+			}
+		}
+		return nil, fmt.Errorf("%w: OpenZeppelin Upgrades 清单 %s 中没有实现合约 %s 的存储布局", util.ErrConfig, path, implementation.Hex())
+	case strings.HasPrefix(file.Format, "hh-sol-artifact"):
+		return loadBuildInfoLayout(path, file.SourceName, file.ContractName)
+	}
+	return nil, fmt.Errorf("%w: %s 中没有存储布局, Foundry 需要在 foundry.toml 中设置 extra_output = [\"storageLayout\"]", util.ErrConfig, path)
+}
+
+// loadBuildInfoLayout 通过 Hardhat 编译产物同目录的 X.dbg.json 找到 build-info, 读取其中的存储布局
+func loadBuildInfoLayout(path, sourceName, contractName string) (*StorageLayout, error) {
+	dbgPath := strings.TrimSuffix(path, ".json") + ".dbg.json"
+	data, err := os.ReadFile(dbgPath)
+	if err != nil {
+		return nil, fmt.Errorf("%w: 读取 Hardhat 调试文件失败 (需要保留 artifacts 目录中的 .dbg.json 和 build-info): %w", util.ErrConfig, err)
+	}
+	var dbg struct {
+		BuildInfo string `json:"buildInfo"`
+	}
+	if err := json.Unmarshal(data, &dbg); err != nil || dbg.BuildInfo == "" {
+		return nil, fmt.Errorf("%w: %s 中没有 buildInfo 路径", util.ErrConfig, dbgPath)
+	}
+	buildInfoPath := filepath.Join(filepath.Dir(dbgPath), dbg.BuildInfo)
+	if data, err = os.ReadFile(buildInfoPath); err != nil {
+		return nil, fmt.Errorf("%w: 读取 build-info 失败: %w", util.ErrConfig, err)
+	}
+	var buildInfo struct {
+		Output struct {
+			Contracts map[string]map[string]struct {
+				StorageLayout json.RawMessage `json:"storageLayout"`
+			} `json:"contracts"`
+		} `json:"output"`
+	}
+	if err := json.Unmarshal(data, &buildInfo); err != nil {
+		return nil, fmt.Errorf("%w: 解析 build-info %s 失败: %w", util.ErrConfig, buildInfoPath, err)
+	}
+	layout := buildInfo.Output.Contracts[sourceName][contractName].StorageLayout
+	if !isLayout(layout) {
+		return nil, fmt.Errorf("%w: build-info %s 中没有 %s:%s 的存储布局, 请在 hardhat.config 中引入 @openzeppelin/hardhat-upgrades 后重新编译",
+			util.ErrConfig, buildInfoPath, sourceName, contractName)
+	}
+	return parseLayout(buildInfoPath, layout)
+}
+
+// isLayout 判断 JSON 值是否为非空的存储布局对象
+func isLayout(data json.RawMessage) bool {
+	data = bytes.TrimSpace(data)
+	return len(data) > 0 && data[0] == '{'
+}
+
+func parseLayout(path string, data []byte) (*StorageLayout, error) {
+	var layout StorageLayout
+	if err := json.Unmarshal(data, &layout); err != nil {
+		return nil, fmt.Errorf("%w: 解析 %s 中的存储布局失败: %w", util.ErrConfig, path, err)
+	}
+	for _, item := range layout.Storage {
+		if _, ok := new(big.Int).SetString(item.Slot, 10); !ok {
+			return nil, fmt.Errorf("%w: %s 中变量 %s 的 slot 格式错误: %q", util.ErrConfig, path, item.Label, item.Slot)
+		}
+	}
+	return &layout, nil
+}
+
+// ParseLayout 解析部署记录中保存的存储布局
+func ParseLayout(data json.RawMessage) (*StorageLayout, error) {
+	return parseLayout("部署记录", data)
+}
+
+// CheckLayout 检查新实现合约的存储布局能否在旧布局的基础上升级, 规则与 OpenZeppelin Upgrades 相同:
+// 原有变量不能删除、移动或改变类型, 新变量只能追加在末尾或占用 __gap 的空间;
+// mapping 和动态数组元素的结构体可以在末尾追加成员。返回不兼容的原因和只改了变量名等提示
+func CheckLayout(old, next *StorageLayout) (problems, warnings []string) {
+	newItems := make(map[string]StorageItem, len(next.Storage))
+	for _, item := range next.Storage {
+		newItems[item.Slot+":"+fmt.Sprint(item.Offset)] = item
+	}
+	var occupied []StorageItem
+	for _, o := range old.Storage {
+		if isGap(o.Label) {
+			continue
+		}
+		occupied = append(occupied, o)
+		n, ok := newItems[o.Slot+":"+fmt.Sprint(o.Offset)]
+		if !ok || isGap(n.Label) {
+			problems = append(problems, fmt.Sprintf("变量 %s 已删除或位置改变 (原 slot %s, offset %d)", variableName(o), o.Slot, o.Offset))
+			continue
+		}
+		if reason := compareType(old, next, o.Type, n.Type, false); reason != "" {
+			if n.Label != o.Label {
+				problems = append(problems, fmt.Sprintf("变量 %s 的位置 (slot %s, offset %d) 被 %s 占用: %s", variableName(o), o.Slot, o.Offset, n.Label, reason))
+			} else {
+				problems = append(problems, fmt.Sprintf("变量 %s 的类型不兼容: %s", variableName(o), reason))
+			}
+			continue
+		}
+		if n.Label != o.Label {
+			warnings = append(warnings, fmt.Sprintf("变量 %s 改名为 %s, 存储位置和类型不变", variableName(o), n.Label))
+		}
+	}
+	// 插入到原有变量之间的新变量会与原有变量重叠; 原有变量之间因打包剩余的字节和 __gap 的空间可以使用
+	for _, n := range next.Storage {
+		if isGap(n.Label) {
+			continue
+		}
+		start, end := itemRange(next, n)
+		for _, o := range occupied {
+			if o.Slot == n.Slot && o.Offset == n.Offset {
+				continue
+			}
+			if oStart, oEnd := itemRange(old, o); start.Cmp(oEnd) < 0 && oStart.Cmp(end) < 0 {
+				problems = append(problems, fmt.Sprintf("新变量 %s (slot %s, offset %d) 与原有变量 %s 的存储位置重叠", variableName(n), n.Slot, n.Offset, variableName(o)))
+				break
+			}
+		}
+	}
+	return problems, warnings
+}
+
+// compareType 比较新旧类型的存储方式, 不兼容时返回原因; grow 为 true 时结构体可以在末尾追加成员
+// 类型 ID 中带有 AST 编号 (如 t_struct(Auction)123_storage), 重新编译后可能改变, 因此按结构比较
+func compareType(old, next *StorageLayout, oldID, newID string, grow bool) string {
+	o, n := old.Types[oldID], next.Types[newID]
+	if o == nil || n == nil {
+		if o == nil && n == nil && oldID == newID {
+			return ""
+		}
+		return fmt.Sprintf("缺少类型 %s / %s 的定义", oldID, newID)
+	}
+	if o.encoding() != n.encoding() {
+		return fmt.Sprintf("%s 变为 %s", o.Label, n.Label)
+	}
+	switch o.encoding() {
+	case "mapping":
+		if reason := compareType(old, next, o.Key, n.Key, false); reason != "" {
+			return fmt.Sprintf("%s 的键: %s", o.Label, reason)
+		}
+		if reason := compareType(old, next, o.Value, n.Value, true); reason != "" {
+			return fmt.Sprintf("%s 的值: %s", o.Label, reason)
+		}
+		return ""
+	case "dynamic_array":
+		if reason := compareType(old, next, o.Base, n.Base, true); reason != "" {
+			return fmt.Sprintf("%s 的元素: %s", o.Label, reason)
+		}
+		return ""
+	case "bytes":
+		if o.Label != n.Label {
+			return fmt.Sprintf("%s 变为 %s", o.Label, n.Label)
+		}
+		return ""
+	}
+
+	oldSize, newSize := o.size(), n.size()
+	if oldSize != newSize && !(grow && newSize > oldSize) {
+		return fmt.Sprintf("%s 变为 %s, 占用的字节数从 %d 变为 %d", o.Label, n.Label, oldSize, newSize)
+	}
+	oldMembers, oldStruct := o.structMembers()
+	newMembers, newStruct := n.structMembers()
+	switch {
+	case oldStruct != newStruct:
+		return fmt.Sprintf("%s 变为 %s", o.Label, n.Label)
+	case oldStruct:
+		for i, om := range oldMembers {
+			if i >= len(newMembers) {
+				return fmt.Sprintf("%s 删除了成员 %s", o.Label, om.Label)
+			}
+			nm := newMembers[i]
+			if om.Slot != nm.Slot || om.Offset != nm.Offset {
+				return fmt.Sprintf("%s 的成员 %s 位置改变", o.Label, om.Label)
+			}
+			if reason := compareType(old, next, om.Type, nm.Type, false); reason != "" {
+				return fmt.Sprintf("%s 的成员 %s: %s", o.Label, om.Label, reason)
+			}
+		}
+		return ""
+	case o.Base != "" || n.Base != "":
+		if reason := compareType(old, next, o.Base, n.Base, false); reason != "" {
+			return fmt.Sprintf("%s 的元素: %s", o.Label, reason)
+		}
+		return ""
+	case strings.HasPrefix(o.Label, "enum ") && strings.HasPrefix(n.Label, "enum "):
+		// solc 输出不包含枚举值, 只有清单中才能检查
+		oldValues, newValues := o.enumValues(), n.enumValues()
+		if oldValues != nil && newValues != nil && !slices.Equal(oldValues, newValues[:min(len(oldValues), len(newValues))]) {
+			return fmt.Sprintf("%s 的枚举值只能在末尾追加: %v 变为 %v", o.Label, oldValues, newValues)
+		}
+		return ""
+	}
+	if canonicalLabel(o.Label) != canonicalLabel(n.Label) {
+		return fmt.Sprintf("%s 变为 %s", o.Label, n.Label)
+	}
+	return ""
+}
+
+// encoding 返回类型的存储方式, OpenZeppelin Upgrades 清单中没有 encoding 字段, 按类型名推断
+func (t *StorageType) encoding() string {
+	switch {
+	case t.Encoding != "":
+		return t.Encoding
+	case strings.HasPrefix(t.Label, "mapping("):
+		return "mapping"
+	case strings.HasSuffix(t.Label, "[]"):
+		return "dynamic_array"
+	case t.Label == "string" || t.Label == "bytes":
+		return "bytes"
+	}
+	return "inplace"
+}
+
+// size 返回类型占用的字节数
+func (t *StorageType) size() int64 {
+	var n big.Int
+	if _, ok := n.SetString(t.NumberOfBytes, 10); !ok || !n.IsInt64() {
+		return 0
+	}
+	return n.Int64()
+}
+
+// structMembers 返回结构体成员, 不是结构体时返回 false
+func (t *StorageType) structMembers() ([]StorageItem, bool) {
+	if !strings.HasPrefix(t.Label, "struct ") {
+		return nil, false
+	}
+	var members []StorageItem
+	json.Unmarshal(t.Members, &members)
+	return members, true
+}
+
+// enumValues 返回枚举值, 没有记录时返回 nil
+func (t *StorageType) enumValues() []string {
+	var values []string
+	if json.Unmarshal(t.Members, &values) != nil {
+		return nil
+	}
+	return values
+}
+
+// canonicalLabel 合约类型和 address 的存储方式相同, 合约类型改名或改为 address 不影响存储
+func canonicalLabel(label string) string {
+	if strings.HasPrefix(label, "contract ") || label == "address payable" {
+		return "address"
+	}
+	return label
+}
+
+// itemRange 返回变量占用的字节范围 [start, end), 以 slot * 32 + offset 计
+func itemRange(layout *StorageLayout, item StorageItem) (*big.Int, *big.Int) {
+	start, _ := new(big.Int).SetString(item.Slot, 10)
+	start.Lsh(start, 5).Add(start, big.NewInt(int64(item.Offset)))
+	size := int64(32)
+	if t := layout.Types[item.Type]; t != nil && t.size() > 0 {
+		size = t.size()
+	}
+	return start, new(big.Int).Add(start, big.NewInt(size))
+}
+
+// isGap 判断是否为预留的存储间隔, 如 uint256[50] private __gap
+func isGap(label string) bool {
+	return strings.HasPrefix(label, "__gap")
+}
+
+// variableName 返回带合约名的变量名, 如 contracts/NFTAuction.sol:NFTAuction.feeRate
+func variableName(item StorageItem) string {
+	if item.Contract == "" {
+		return item.Label
+	}
+	return item.Contract + "." + item.Label
+}
@@ -0,0 +1,171 @@
+package proxy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"task1/util"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// layoutTypes 测试布局共用的类型定义, 与 solc storageLayout 输出的格式相同
+var layoutTypes = map[string]*StorageType{
+	"t_address":                    {Encoding: "inplace", Label: "address", NumberOfBytes: "20"},
+	"t_bool":                       {Encoding: "inplace", Label: "bool", NumberOfBytes: "1"},
+	"t_uint64":                     {Encoding: "inplace", Label: "uint64", NumberOfBytes: "8"},
+	"t_uint256":                    {Encoding: "inplace", Label: "uint256", NumberOfBytes: "32"},
+	"t_array(t_uint256)50_storage": {Encoding: "inplace", Label: "uint256[50]", NumberOfBytes: "1600", Base: "t_uint256"},
+	"t_array(t_uint256)49_storage": {Encoding: "inplace", Label: "uint256[49]", NumberOfBytes: "1568", Base: "t_uint256"},
+	"t_mapping(t_uint256,t_struct(Bid)10_storage)": {Encoding: "mapping", Label: "mapping(uint256 => struct Auction.Bid)", NumberOfBytes: "32",
+		Key: "t_uint256", Value: "t_struct(Bid)10_storage"},
+	"t_struct(Bid)10_storage": {Encoding: "inplace", Label: "struct Auction.Bid", NumberOfBytes: "64",
+		Members: json.RawMessage(`[{"label": "bidder", "offset": 0, "slot": "0", "type": "t_address"},
+			{"label": "amount", "offset": 0, "slot": "1", "type": "t_uint256"}]`)},
+	// 重新编译后 AST 编号改变, 并在末尾追加了成员 time
+	"t_mapping(t_uint256,t_struct(Bid)20_storage)": {Encoding: "mapping", Label: "mapping(uint256 => struct Auction.Bid)", NumberOfBytes: "32",
+		Key: "t_uint256", Value: "t_struct(Bid)20_storage"},
+	"t_struct(Bid)20_storage": {Encoding: "inplace", Label: "struct Auction.Bid", NumberOfBytes: "96",
+		Members: json.RawMessage(`[{"label": "bidder", "offset": 0, "slot": "0", "type": "t_address"},
+			{"label": "amount", "offset": 0, "slot": "1", "type": "t_uint256"},
+			{"label": "time", "offset": 0, "slot": "2", "type": "t_uint64"}]`)},
+	// 删除了成员 amount
+	"t_mapping(t_uint256,t_struct(Bid)30_storage)": {Encoding: "mapping", Label: "mapping(uint256 => struct Auction.Bid)", NumberOfBytes: "32",
+		Key: "t_uint256", Value: "t_struct(Bid)30_storage"},
+	"t_struct(Bid)30_storage": {Encoding: "inplace", Label: "struct Auction.Bid", NumberOfBytes: "32",
+		Members: json.RawMessage(`[{"label": "bidder", "offset": 0, "slot": "0", "type": "t_address"}]`)},
+}
+
+func item(label string, slot, offset int, typ string) StorageItem {
+	return StorageItem{Contract: "Auction", Label: label, Offset: offset, Slot: fmt.Sprint(slot), Type: typ}
+}
+
+func layout(items ...StorageItem) *StorageLayout {
+	return &StorageLayout{Storage: items, Types: layoutTypes}
+}
+
+const bidsType = "t_mapping(t_uint256,t_struct(Bid)10_storage)"
+
+func TestCheckLayout(t *testing.T) {
+	old := layout(
+		item("owner", 0, 0, "t_address"),
+		item("paused", 0, 20, "t_bool"),
+		item("feeRate", 1, 0, "t_uint256"),
+		item("bids", 2, 0, bidsType),
+		item("__gap", 3, 0, "t_array(t_uint256)50_storage"),
+	)
+	tests := []struct {
+		name     string
+		next     *StorageLayout
+		problems int
+		warnings int
+	}{
+		{"布局不变", old, 0, 0},
+		{"在末尾追加变量", layout(append(old.Storage, item("extra", 53, 0, "t_uint256"))...), 0, 0},
+		{"使用打包剩余的字节", layout(append(old.Storage, item("round", 0, 21, "t_uint64"))...), 0, 0},
+		{"占用 __gap", layout(
+			item("owner", 0, 0, "t_address"),
+			item("paused", 0, 20, "t_bool"),
+			item("feeRate", 1, 0, "t_uint256"),
+			item("bids", 2, 0, bidsType),
+			item("extra", 3, 0, "t_uint256"),
+			item("__gap", 4, 0, "t_array(t_uint256)49_storage"),
+		), 0, 0},
+		{"在中间插入变量", layout(
+			item("owner", 0, 0, "t_address"),
+			item("paused", 0, 20, "t_bool"),
+			item("beneficiary", 1, 0, "t_address"),
+			item("feeRate", 2, 0, "t_uint256"),
+			item("bids", 3, 0, bidsType),
+			item("__gap", 4, 0, "t_array(t_uint256)50_storage"),
+		), 2, 0},
+		{"删除变量", layout(
+			item("owner", 0, 0, "t_address"),
+			item("feeRate", 1, 0, "t_uint256"),
+			item("bids", 2, 0, bidsType),
+			item("__gap", 3, 0, "t_array(t_uint256)50_storage"),
+		), 1, 0},
+		{"改变类型", layout(
+			item("owner", 0, 0, "t_address"),
+			item("paused", 0, 20, "t_bool"),
+			item("feeRate", 1, 0, "t_uint64"),
+			item("bids", 2, 0, bidsType),
+			item("__gap", 3, 0, "t_array(t_uint256)50_storage"),
+		), 1, 0},
+		{"改名", layout(
+			item("owner", 0, 0, "t_address"),
+			item("paused", 0, 20, "t_bool"),
+			item("fee", 1, 0, "t_uint256"),
+			item("bids", 2, 0, bidsType),
+			item("__gap", 3, 0, "t_array(t_uint256)50_storage"),
+		), 0, 1},
+		{"mapping 中的结构体追加成员", layout(
+			item("owner", 0, 0, "t_address"),
+			item("paused", 0, 20, "t_bool"),
+			item("feeRate", 1, 0, "t_uint256"),
+			item("bids", 2, 0, "t_mapping(t_uint256,t_struct(Bid)20_storage)"),
+			item("__gap", 3, 0, "t_array(t_uint256)50_storage"),
+		), 0, 0},
+		{"mapping 中的结构体删除成员", layout(
+			item("owner", 0, 0, "t_address"),
+			item("paused", 0, 20, "t_bool"),
+			item("feeRate", 1, 0, "t_uint256"),
+			item("bids", 2, 0, "t_mapping(t_uint256,t_struct(Bid)30_storage)"),
+			item("__gap", 3, 0, "t_array(t_uint256)50_storage"),
+		), 1, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problems, warnings := CheckLayout(old, tt.next)
+			if len(problems) != tt.problems || len(warnings) != tt.warnings {
+				t.Fatalf("不兼容原因 %q, 提示 %q, 预期 %d 个原因 %d 个提示", problems, warnings, tt.problems, tt.warnings)
+			}
+		})
+	}
+
+	// 直接存储的结构体不能追加成员, 否则会覆盖后面的变量
+	structOld := layout(item("bid", 0, 0, "t_struct(Bid)10_storage"), item("feeRate", 2, 0, "t_uint256"))
+	structNew := layout(item("bid", 0, 0, "t_struct(Bid)20_storage"), item("feeRate", 3, 0, "t_uint256"))
+	if problems, _ := CheckLayout(structOld, structNew); len(problems) == 0 {
+		t.Fatal("直接存储的结构体追加成员应当不兼容")
+	}
+}
+
+// TestLoadLayoutManifest 从 OpenZeppelin Upgrades 清单中按实现合约地址读取布局, 清单中的枚举记录了枚举值
+func TestLoadLayoutManifest(t *testing.T) {
+	v1 := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	v2 := common.HexToAddress("0x00000000000000000000000000000000000000a2")
+	v3 := common.HexToAddress("0x00000000000000000000000000000000000000a3")
+	impl := func(address common.Address, values string) string {
+		return fmt.Sprintf(`"%x": {"address": "%s", "layout": {
+			"storage": [{"contract": "Auction", "label": "status", "offset": 0, "slot": "0", "type": "t_enum(Status)5"}],
+			"types": {"t_enum(Status)5": {"label": "enum Auction.Status", "numberOfBytes": "1", "members": %s}}}}`, address, address.Hex(), values)
+	}
+	manifest := fmt.Sprintf(`{"manifestVersion": "3.2", "impls": {%s, %s, %s}}`,
+		impl(v1, `["Active", "Ended"]`), impl(v2, `["Active", "Ended", "Cancelled"]`), impl(v3, `["Ended", "Active"]`))
+	path := filepath.Join(t.TempDir(), "unknown-1337.json")
+	if err := os.WriteFile(path, []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	load := func(address common.Address) *StorageLayout {
+		t.Helper()
+		layout, err := LoadLayout(path, address)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return layout
+	}
+	old := load(v1)
+	if problems, _ := CheckLayout(old, load(v2)); len(problems) != 0 {
+		t.Errorf("在末尾追加枚举值应当兼容: %q", problems)
+	}
+	if problems, _ := CheckLayout(old, load(v3)); len(problems) != 1 {
+		t.Errorf("调整枚举值顺序应当不兼容: %q", problems)
+	}
+	if _, err := LoadLayout(path, common.Address{}); !errors.Is(err, util.ErrConfig) {
+		t.Errorf("清单中没有实现合约时的错误为 %v, 预期 %v", err, util.ErrConfig)
+	}
+}
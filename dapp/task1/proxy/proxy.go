@@ -0,0 +1,263 @@
+// Package proxy 部署和升级 UUPS 可升级合约, 对应 solidity/task3 中 Hardhat 的 upgrades.deployProxy / upgrades.upgradeProxy
+//
+// 部署时先部署实现合约, 再部署 ERC1967 代理并在代理的构造函数中调用初始化方法 (如 __NFTAuction_init);
+// 部署记录保存代理地址、实现合约地址、实现合约的 ABI 和存储布局, 升级前用保存的存储布局检查新实现合约是否兼容,
+// 作用与 Hardhat 脚本写入 utils/.cfg/.cfgCache 的实现合约记录相同
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"task1/account"
+	"task1/contracts"
+	"task1/util"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// DeployOptions 部署代理的参数
+type DeployOptions struct {
+	Implementation *contracts.Artifact       // 实现合约的编译产物, 构造函数不能有参数
+	Libraries      map[string]common.Address // 实现合约需要链接的库
+	Proxy          *contracts.Artifact       // 代理合约的编译产物, nil 时使用内置的 ERC1967 代理
+	Initializer    string                    // 初始化方法名或签名, 为空时不调用
+	Args           []string                  // 初始化方法的参数
+	Value          *big.Int                  // 附带的金额 (wei), 通过代理转给 payable 的初始化方法
+	Layout         *StorageLayout            // 实现合约的存储布局, 保存到部署记录, nil 时升级前需要另外指定
+}
+
+// UpgradeOptions 升级代理的参数
+type UpgradeOptions struct {
+	Implementation *contracts.Artifact       // 新实现合约的编译产物
+	Libraries      map[string]common.Address // 新实现合约需要链接的库
+	Call           string                    // 升级后通过 upgradeToAndCall 调用的方法名或签名, 如 __NFTAuctionV2_init, 为空时不调用
+	Args           []string                  // 升级后调用的方法的参数
+	Value          *big.Int                  // 附带的金额 (wei), 只能用于 payable 方法
+	Layout         *StorageLayout            // 新实现合约的存储布局
+	OldLayout      *StorageLayout            // 当前实现合约的存储布局, nil 时使用部署记录中保存的布局
+	SkipLayout     bool                      // 跳过存储布局兼容性检查
+}
+
+// Deploy 部署实现合约和 ERC1967 代理, 在代理的构造函数中调用初始化方法
+// 返回代理的部署记录 (ABI 为实现合约的 ABI) 和每笔交易的收据, 交易执行失败时同时返回已有的收据
+func Deploy(ctx context.Context, client util.Client, opts DeployOptions) (*contracts.Deployment, []*types.Receipt, error) {
+	implementation := opts.Implementation
+	if err := checkUpgradeable(implementation); err != nil {
+		return nil, nil, err
+	}
+	data, err := encodeCall(implementation, opts.Initializer, opts.Args, opts.Value)
+	if err != nil {
+		return nil, nil, err
+	}
+	proxyArtifact := opts.Proxy
+	if proxyArtifact == nil {
+		if proxyArtifact, err = Artifact(""); err != nil {
+			return nil, nil, err
+		}
+	}
+	proxyCode, err := proxyArtifact.Link(nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	impl, receipts, err := deployImplementation(ctx, client, implementation, opts.Libraries)
+	if err != nil {
+		return nil, receipts, err
+	}
+	arguments, err := proxyArtifact.ABI.Constructor.Inputs.Pack(impl, data)
+	if err != nil {
+		return nil, receipts, fmt.Errorf("%w: 编码代理合约的构造函数参数失败: %w", util.ErrInvalidArgument, err)
+	}
+	deployment, receipt, err := contracts.DeployCode(ctx, client, proxyArtifact.Name, append(proxyCode, arguments...), opts.Value, implementation.RawABI)
+	if receipt != nil {
+		receipts = append(receipts, receipt)
+	}
+	if err != nil {
+		return nil, receipts, err
+	}
+	if err := checkImplementation(ctx, client, deployment.Address, impl); err != nil {
+		return nil, receipts, err
+	}
+	deployment.Implementation = &impl
+	if opts.Layout != nil {
+		if deployment.StorageLayout, err = json.Marshal(opts.Layout); err != nil {
+			return nil, receipts, err
+		}
+	}
+	util.Logf("代理 %s 已部署, 实现合约 %s", deployment.Address.Hex(), impl.Hex())
+	return deployment, receipts, nil
+}
+
+// Upgrade 部署新的实现合约, 通过代理调用 upgradeToAndCall 升级
+// proxy 为代理的部署记录, 直接指定地址时只需要填写 Address; 升级成功后更新 proxy 的实现合约、ABI 和存储布局
+// 返回每笔交易的收据, 交易执行失败时同时返回已有的收据
+func Upgrade(ctx context.Context, client util.Client, proxy *contracts.Deployment, opts UpgradeOptions) ([]*types.Receipt, error) {
+	implementation := opts.Implementation
+	slots, err := ReadSlots(ctx, client, proxy.Address)
+	if err != nil {
+		return nil, err
+	}
+	if slots.Implementation == (common.Address{}) {
+		return nil, fmt.Errorf("%w: 地址 %s 不是 ERC1967 代理 (实现合约槽为空)", util.ErrInvalidArgument, proxy.Address.Hex())
+	}
+	if err := checkUpgradeable(implementation); err != nil {
+		return nil, err
+	}
+	if opts.SkipLayout {
+		util.Logf("已跳过存储布局兼容性检查, 不兼容的升级会破坏代理中已有的数据")
+	} else if err := checkUpgradeLayout(proxy, slots.Implementation, opts); err != nil {
+		return nil, err
+	}
+	data, err := encodeCall(implementation, opts.Call, opts.Args, opts.Value)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkOwner(ctx, client, proxy.Address, implementation.ABI); err != nil {
+		return nil, err
+	}
+
+	impl, receipts, err := deployImplementation(ctx, client, implementation, opts.Libraries)
+	if err != nil {
+		return receipts, err
+	}
+	util.Logf("开始升级代理 %s: %s -> %s", proxy.Address.Hex(), slots.Implementation.Hex(), impl.Hex())
+	contract := contracts.NewContract(client, proxy.Address, uupsABI)
+	method := uupsABI.Methods["upgradeToAndCall"]
+	receipt, err := contract.Send(ctx, &method, []string{impl.Hex(), hexutil.Encode(data)}, opts.Value)
+	if receipt != nil {
+		receipts = append(receipts, receipt)
+	}
+	if err != nil {
+		return receipts, err
+	}
+	if err := checkImplementation(ctx, client, proxy.Address, impl); err != nil {
+		return receipts, err
+	}
+	proxy.Implementation = &impl
+	proxy.ABI = implementation.RawABI
+	proxy.StorageLayout = nil
+	if opts.Layout != nil {
+		if proxy.StorageLayout, err = json.Marshal(opts.Layout); err != nil {
+			return receipts, err
+		}
+	}
+	util.Logf("代理 %s 已升级, 实现合约 %s", proxy.Address.Hex(), impl.Hex())
+	return receipts, nil
+}
+
+// checkUpgradeLayout 用当前实现合约的存储布局检查新实现合约的存储布局
+func checkUpgradeLayout(proxy *contracts.Deployment, current common.Address, opts UpgradeOptions) error {
+	old := opts.OldLayout
+	if old == nil {
+		// 代理在其他地方 (如 Hardhat 脚本) 升级过时, 部署记录中的布局已经过期
+		if proxy.Implementation != nil && *proxy.Implementation != current {
+			return fmt.Errorf("%w: 部署记录中的实现合约 %s 与链上的 %s 不一致, 请通过 --old-layout 指定当前实现合约的存储布局",
+				util.ErrInvalidArgument, proxy.Implementation.Hex(), current.Hex())
+		}
+		if len(proxy.StorageLayout) == 0 {
+			return fmt.Errorf("%w: 没有代理 %s 当前实现合约的存储布局, 请通过 --old-layout 指定", util.ErrInvalidArgument, proxy.Address.Hex())
+		}
+		var err error
+		if old, err = ParseLayout(proxy.StorageLayout); err != nil {
+			return err
+		}
+	}
+	if opts.Layout == nil {
+		return fmt.Errorf("%w: 没有新实现合约 %s 的存储布局, 请通过 --layout 指定", util.ErrInvalidArgument, opts.Implementation.Name)
+	}
+	problems, warnings := CheckLayout(old, opts.Layout)
+	for _, warning := range warnings {
+		util.Logf("存储布局: %s", warning)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: 新实现合约 %s 的存储布局与当前实现合约不兼容:\n  - %s",
+			util.ErrInvalidArgument, opts.Implementation.Name, strings.Join(problems, "\n  - "))
+	}
+	util.Logf("存储布局兼容性检查通过")
+	return nil
+}
+
+// checkUpgradeable 实现合约需要提供 upgradeToAndCall, 否则代理部署或升级后无法再次升级
+func checkUpgradeable(artifact *contracts.Artifact) error {
+	if _, ok := artifact.ABI.Methods["upgradeToAndCall"]; !ok {
+		return fmt.Errorf("%w: 实现合约 %s 的 ABI 中没有 upgradeToAndCall, 不是 UUPS 合约, 使用后代理将无法再升级", util.ErrInvalidArgument, artifact.Name)
+	}
+	return nil
+}
+
+// checkOwner 实现合约有 owner() 时检查当前账户是否为代理的 owner, 避免部署新的实现合约后才发现无权升级
+func checkOwner(ctx context.Context, client util.Client, proxy common.Address, contractABI *abi.ABI) error {
+	method, ok := contractABI.Methods["owner"]
+	if !ok || len(method.Inputs) != 0 || len(method.Outputs) != 1 || method.Outputs[0].Type.T != abi.AddressTy {
+		return nil
+	}
+	from, err := account.Address(ctx)
+	if err != nil {
+		return err
+	}
+	ret, err := client.CallContract(ctx, ethereum.CallMsg{To: &proxy, Data: method.ID}, nil)
+	if err != nil {
+		return util.RevertError("查询代理的 owner 失败", err)
+	}
+	if owner := common.BytesToAddress(ret); len(ret) == common.HashLength && owner != from {
+		return fmt.Errorf("%w: 当前账户 %s 不是代理 %s 的 owner (%s), 无法升级", util.ErrInvalidArgument, from.Hex(), proxy.Hex(), owner.Hex())
+	}
+	return nil
+}
+
+// encodeCall 编码通过代理调用实现合约方法的数据, name 为空时返回空数据
+func encodeCall(artifact *contracts.Artifact, name string, args []string, value *big.Int) ([]byte, error) {
+	if name == "" {
+		if value != nil && value.Sign() > 0 {
+			return nil, fmt.Errorf("%w: 不调用初始化方法时不能附带金额", util.ErrInvalidArgument)
+		}
+		if len(args) > 0 {
+			return nil, fmt.Errorf("%w: 指定了参数但没有指定调用的方法", util.ErrInvalidArgument)
+		}
+		return nil, nil
+	}
+	contract := contracts.NewContract(nil, common.Address{}, artifact.ABI)
+	method, err := contract.Method(name)
+	if err != nil {
+		return nil, err
+	}
+	if value != nil && value.Sign() > 0 && !method.IsPayable() {
+		return nil, fmt.Errorf("%w: 方法 %s 不是 payable, 不能附带金额", util.ErrInvalidArgument, method.Sig)
+	}
+	return contract.Pack(method, args)
+}
+
+// deployImplementation 部署实现合约并检查 proxiableUUID
+func deployImplementation(ctx context.Context, client util.Client, artifact *contracts.Artifact, libraries map[string]common.Address) (common.Address, []*types.Receipt, error) {
+	var receipts []*types.Receipt
+	deployment, receipt, err := contracts.Deploy(ctx, client, artifact, libraries, nil, nil)
+	if receipt != nil {
+		receipts = append(receipts, receipt)
+	}
+	if err != nil {
+		return common.Address{}, receipts, fmt.Errorf("部署实现合约失败: %w", err)
+	}
+	if err := CheckUUPS(ctx, client, deployment.Address); err != nil {
+		return common.Address{}, receipts, err
+	}
+	return deployment.Address, receipts, nil
+}
+
+// checkImplementation 检查代理的实现合约槽是否为预期的地址
+func checkImplementation(ctx context.Context, client util.Client, proxy, implementation common.Address) error {
+	slots, err := ReadSlots(ctx, client, proxy)
+	if err != nil {
+		return err
+	}
+	if slots.Implementation != implementation {
+		return fmt.Errorf("%w: 代理 %s 的实现合约为 %s, 预期为 %s", util.ErrTxFailed, proxy.Hex(), slots.Implementation.Hex(), implementation.Hex())
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+package transactions
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"task1/errs"
+	"task1/util"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// FeeStrategy 手续费策略, 按最近区块小费的百分位数估算优先费
+type FeeStrategy string
+
+const (
+	FeeSlow   FeeStrategy = "slow"
+	FeeNormal FeeStrategy = "normal"
+	FeeFast   FeeStrategy = "fast"
+)
+
+// feePercentiles 各策略使用的 eth_feeHistory 小费百分位数
+var feePercentiles = map[FeeStrategy]float64{
+	FeeSlow:   10,
+	FeeNormal: 50,
+	FeeFast:   90,
+}
+
+// feeHistoryBlocks 估算优先费时参考的区块数量
+const feeHistoryBlocks = 20
+
+// FeeStrategies 返回所有手续费策略名称
+func FeeStrategies() []string {
+	return []string{string(FeeSlow), string(FeeNormal), string(FeeFast)}
+}
+
+// ParseFeeStrategy 解析手续费策略, 空字符串表示使用节点建议的优先费
+func ParseFeeStrategy(name string) (FeeStrategy, error) {
+	strategy := FeeStrategy(strings.ToLower(strings.TrimSpace(name)))
+	if strategy == "" {
+		return "", nil
+	}
+	if _, ok := feePercentiles[strategy]; !ok {
+		return "", fmt.Errorf("%w: 未知的手续费策略 %q, 可选: %s", util.ErrInvalidArgument, name, strings.Join(FeeStrategies(), ", "))
+	}
+	return strategy, nil
+}
+
+// FeeOptions 手续费配置
+type FeeOptions struct {
+	Strategy    FeeStrategy // 手续费策略, 为空时使用 SuggestGasTipCap
+	MaxFee      *big.Int    // --max-fee 指定的最高费用(wei), 传统交易时作为 gasPrice
+	PriorityFee *big.Int    // --priority-fee 指定的优先费(wei)
+	Legacy      bool        // 使用传统交易(gasPrice), 用于不支持 London 升级的链
+}
+
+// feeOptions 通过命令行参数指定的手续费配置
+var feeOptions FeeOptions
+
+// SetFeeOptions 设置发送交易时使用的手续费配置
+func SetFeeOptions(options FeeOptions) {
+	feeOptions = options
+}
+
+// CurrentFeeOptions 返回当前的手续费配置
+func CurrentFeeOptions() FeeOptions {
+	return feeOptions
+}
+
+// Fees 估算得到的交易手续费
+type Fees struct {
+	Legacy    bool
+	GasPrice  *big.Int // 传统交易的 gasPrice
+	BaseFee   *big.Int // 最新区块的基础费用
+	GasTipCap *big.Int // 优先费上限 maxPriorityFeePerGas
+	GasFeeCap *big.Int // 费用上限 maxFeePerGas
+}
+
+// SuggestFees 根据手续费配置估算交易手续费
+//
+// EIP-1559 交易的基础费用取自最新区块头, 优先费按以下顺序确定:
+//
+//  1. --priority-fee 指定的值
+//  2. 指定了策略时, 最近 20 个区块小费的对应百分位数的中位数
+//  3. 节点的 eth_maxPriorityFeePerGas 建议值
+//
+// 费用上限默认为 2 * 基础费用 + 优先费, 可以承受连续 6 个满区块的基础费用上涨;
+// 只指定了 --max-fee 时, 估算的优先费不超过最高费用
+func SuggestFees(ctx context.Context, client util.Client, options FeeOptions) (*Fees, error) {
+	if options.Legacy {
+		if options.PriorityFee != nil {
+			return nil, fmt.Errorf("%w: 传统交易不支持指定优先费", util.ErrInvalidArgument)
+		}
+		gasPrice := options.MaxFee
+		if gasPrice == nil {
+			var err error
+			if gasPrice, err = client.SuggestGasPrice(ctx); err != nil {
+				return nil, fmt.Errorf("获取 gas 价格失败: %w", errs.Classify(err))
+			}
+		}
+		util.Logf("gas 价格: %s gwei (传统交易)", util.FormatUnits(gasPrice, 9))
+		return &Fees{Legacy: true, GasPrice: gasPrice}, nil
+	}
+
+	header, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("获取最新区块失败: %w", errs.Classify(err))
+	}
+	if header.BaseFee == nil {
+		return nil, fmt.Errorf("%w: 当前网络未启用 EIP-1559 (London), 请使用 --legacy 发送传统交易", util.ErrConfig)
+	}
+
+	tip := options.PriorityFee
+	if tip == nil && options.Strategy != "" {
+		if tip, err = tipFromHistory(ctx, client, options.Strategy); err != nil {
+			util.Logf("通过 eth_feeHistory 估算优先费失败, 使用节点建议值: %v", err)
+		}
+	}
+	if tip == nil {
+		if tip, err = client.SuggestGasTipCap(ctx); err != nil {
+			return nil, fmt.Errorf("获取建议优先费失败: %w", errs.Classify(err))
+		}
+	}
+
+	feeCap := options.MaxFee
+	if feeCap == nil {
+		feeCap = new(big.Int).Add(new(big.Int).Mul(header.BaseFee, big.NewInt(2)), tip)
+	} else if options.PriorityFee == nil && feeCap.Cmp(tip) < 0 {
+		util.Logf("估算的优先费 %s gwei 高于最高费用, 优先费降为 %s gwei", util.FormatUnits(tip, 9), util.FormatUnits(feeCap, 9))
+		tip = feeCap
+	}
+	if feeCap.Cmp(tip) < 0 {
+		return nil, fmt.Errorf("%w: 最高费用 %s gwei 低于优先费 %s gwei", util.ErrInvalidArgument, util.FormatUnits(feeCap, 9), util.FormatUnits(tip, 9))
+	}
+	if feeCap.Cmp(header.BaseFee) < 0 {
+		util.Logf("警告: 最高费用 %s gwei 低于当前基础费用 %s gwei, 交易需要等待基础费用下降", util.FormatUnits(feeCap, 9), util.FormatUnits(header.BaseFee, 9))
+	}
+	util.Logf("gas 费用: 基础费用 %s gwei, 优先费 %s gwei, 最高费用 %s gwei", util.FormatUnits(header.BaseFee, 9), util.FormatUnits(tip, 9), util.FormatUnits(feeCap, 9))
+	return &Fees{BaseFee: header.BaseFee, GasTipCap: tip, GasFeeCap: feeCap}, nil
+}
+
+// tipFromHistory 取最近区块中对应百分位小费的中位数, 忽略空区块
+func tipFromHistory(ctx context.Context, client util.Client, strategy FeeStrategy) (*big.Int, error) {
+	history, err := client.FeeHistory(ctx, feeHistoryBlocks, nil, []float64{feePercentiles[strategy]})
+	if err != nil {
+		return nil, errs.Classify(err)
+	}
+	var rewards []*big.Int
+	for i, reward := range history.Reward {
+		if len(reward) == 0 || (i < len(history.GasUsedRatio) && history.GasUsedRatio[i] == 0) {
+			continue
+		}
+		rewards = append(rewards, reward[0])
+	}
+	if len(rewards) == 0 {
+		return nil, fmt.Errorf("最近 %d 个区块都没有交易", feeHistoryBlocks)
+	}
+	slices.SortFunc(rewards, func(a, b *big.Int) int { return a.Cmp(b) })
+	tip := rewards[len(rewards)/2]
+	util.Logf("最近 %d 个区块第 %.0f 百分位小费的中位数: %s gwei (%s)", len(rewards), feePercentiles[strategy], util.FormatUnits(tip, 9), strategy)
+	return tip, nil
+}
+
+// NewTx 使用估算的手续费构建未签名交易, to 为 nil 表示部署合约
+func (f *Fees) NewTx(chainID *big.Int, nonce uint64, to *common.Address, value *big.Int, gas uint64, data []byte) *types.Transaction {
+	if f.Legacy {
+		return types.NewTx(&types.LegacyTx{
+			Nonce:    nonce,
+			GasPrice: f.GasPrice,
+			Gas:      gas,
+			To:       to,
+			Value:    value,
+			Data:     data,
+		})
+	}
+	return types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     nonce,
+		GasTipCap: f.GasTipCap,
+		GasFeeCap: f.GasFeeCap,
+		Gas:       gas,
+		To:        to,
+		Value:     value,
+		Data:      data,
+	})
+}
+
+// Apply 将手续费写入 bind 的交易参数
+func (f *Fees) Apply(opts *bind.TransactOpts) {
+	if f.Legacy {
+		opts.GasPrice = f.GasPrice
+		return
+	}
+	opts.GasFeeCap = f.GasFeeCap
+	opts.GasTipCap = f.GasTipCap
+}
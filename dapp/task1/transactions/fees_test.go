@@ -0,0 +1,121 @@
+package transactions
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"task1/util"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// feeClient 只实现 SuggestFees 使用的方法
+type feeClient struct {
+	util.Client
+	baseFee     *big.Int
+	history     *ethereum.FeeHistory
+	percentiles []float64 // eth_feeHistory 请求的百分位数
+}
+
+func (c *feeClient) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return &types.Header{Number: big.NewInt(100), BaseFee: c.baseFee}, nil
+}
+
+func (c *feeClient) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return gwei(2), nil
+}
+
+func (c *feeClient) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return gwei(15), nil
+}
+
+func (c *feeClient) FeeHistory(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (*ethereum.FeeHistory, error) {
+	c.percentiles = rewardPercentiles
+	if c.history == nil {
+		return nil, errors.New("the method eth_feeHistory does not exist/is not available")
+	}
+	return c.history, nil
+}
+
+func gwei(n int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(n), big.NewInt(params.GWei))
+}
+
+// feeHistory 每个区块的小费和 gas 使用率, 使用率为 0 表示空区块
+func feeHistory(tips []int64, ratios []float64) *ethereum.FeeHistory {
+	history := &ethereum.FeeHistory{GasUsedRatio: ratios}
+	for _, tip := range tips {
+		history.Reward = append(history.Reward, []*big.Int{gwei(tip)})
+	}
+	return history
+}
+
+func TestSuggestFees(t *testing.T) {
+	tests := []struct {
+		name       string
+		options    FeeOptions
+		baseFee    *big.Int
+		history    *ethereum.FeeHistory
+		tip, cap   int64 // 预期的优先费和最高费用 (gwei)
+		gasPrice   int64 // 预期的传统交易 gas 价格 (gwei), 0 表示 EIP-1559 交易
+		percentile float64
+		err        error
+	}{
+		{name: "节点建议的优先费", baseFee: gwei(10), tip: 2, cap: 22},
+		{name: "指定优先费", options: FeeOptions{PriorityFee: gwei(3)}, baseFee: gwei(10), tip: 3, cap: 23},
+		{name: "指定最高费用", options: FeeOptions{MaxFee: gwei(50)}, baseFee: gwei(10), tip: 2, cap: 50},
+		{name: "最高费用低于建议优先费", options: FeeOptions{MaxFee: gwei(1)}, baseFee: gwei(10), tip: 1, cap: 1},
+		{name: "最高费用低于指定优先费", options: FeeOptions{MaxFee: gwei(1), PriorityFee: gwei(2)}, baseFee: gwei(10), err: util.ErrInvalidArgument},
+		{name: "fast 策略忽略空区块", options: FeeOptions{Strategy: FeeFast}, baseFee: gwei(10),
+			history: feeHistory([]int64{1, 5, 100, 3, 9}, []float64{0.5, 0.5, 0, 0.5, 0.5}), tip: 5, cap: 25, percentile: 90},
+		{name: "slow 策略", options: FeeOptions{Strategy: FeeSlow}, baseFee: gwei(10),
+			history: feeHistory([]int64{1, 2, 3}, []float64{0.5, 0.5, 0.5}), tip: 2, cap: 22, percentile: 10},
+		{name: "全部是空区块时使用节点建议值", options: FeeOptions{Strategy: FeeNormal}, baseFee: gwei(10),
+			history: feeHistory([]int64{0, 0}, []float64{0, 0}), tip: 2, cap: 22, percentile: 50},
+		{name: "节点不支持 eth_feeHistory 时使用节点建议值", options: FeeOptions{Strategy: FeeNormal}, baseFee: gwei(10), tip: 2, cap: 22, percentile: 50},
+		{name: "指定优先费时不查询历史", options: FeeOptions{Strategy: FeeFast, PriorityFee: gwei(4)}, baseFee: gwei(10), tip: 4, cap: 24},
+		{name: "传统交易", options: FeeOptions{Legacy: true}, gasPrice: 15},
+		{name: "传统交易指定最高费用", options: FeeOptions{Legacy: true, MaxFee: gwei(20)}, gasPrice: 20},
+		{name: "传统交易不支持优先费", options: FeeOptions{Legacy: true, PriorityFee: gwei(1)}, err: util.ErrInvalidArgument},
+		{name: "未启用 London", err: util.ErrConfig},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &feeClient{baseFee: tt.baseFee, history: tt.history}
+			fees, err := SuggestFees(context.Background(), client, tt.options)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("错误为 %v, 预期 %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.percentile != 0 && (len(client.percentiles) != 1 || client.percentiles[0] != tt.percentile) {
+				t.Errorf("请求的百分位数为 %v, 预期 %v", client.percentiles, tt.percentile)
+			}
+			if tt.percentile == 0 && client.percentiles != nil {
+				t.Errorf("不应查询 eth_feeHistory")
+			}
+			if tt.gasPrice != 0 {
+				if !fees.Legacy || fees.GasPrice.Cmp(gwei(tt.gasPrice)) != 0 {
+					t.Fatalf("手续费 %+v, 预期 gasPrice %d gwei", fees, tt.gasPrice)
+				}
+				if tx := fees.NewTx(big.NewInt(1), 0, nil, nil, 21000, nil); tx.Type() != types.LegacyTxType {
+					t.Fatalf("交易类型为 %d", tx.Type())
+				}
+				return
+			}
+			if fees.Legacy || fees.GasTipCap.Cmp(gwei(tt.tip)) != 0 || fees.GasFeeCap.Cmp(gwei(tt.cap)) != 0 {
+				t.Fatalf("优先费 %s, 最高费用 %s, 预期 %d 和 %d gwei", fees.GasTipCap, fees.GasFeeCap, tt.tip, tt.cap)
+			}
+			if tx := fees.NewTx(big.NewInt(1), 0, nil, nil, 21000, nil); tx.Type() != types.DynamicFeeTxType || tx.GasFeeCap().Cmp(fees.GasFeeCap) != 0 {
+				t.Fatalf("交易类型为 %d", tx.Type())
+			}
+		})
+	}
+}
@@ -0,0 +1,279 @@
+package transactions
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strings"
+	"task1/account"
+	"task1/errs"
+	"task1/util"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// OfflineTx 离线签名流程中在机器之间传递的交易文件
+//
+// Tx 使用 go-ethereum types.Transaction 的 JSON 格式, 未签名时 v/r/s 为 0;
+// 签名后 Raw 为交易的 RLP 编码 (types.Transaction.MarshalBinary), 可以直接通过 eth_sendRawTransaction 广播
+type OfflineTx struct {
+	Network string             `json:"network,omitempty"` // 构建交易时使用的网络, 仅用于展示
+	ChainID *hexutil.Big       `json:"chainId"`           // 签名使用的链ID, 传统交易的 JSON 中不包含链ID
+	From    common.Address     `json:"from"`              // 预期的签名账户
+	Tx      *types.Transaction `json:"tx"`
+	Raw     hexutil.Bytes      `json:"raw,omitempty"` // 签名后的 RLP 编码
+}
+
+// BuildRequest 构建未签名交易的参数
+type BuildRequest struct {
+	From  common.Address
+	To    *common.Address // 为 nil 表示部署合约
+	Value *big.Int
+	Data  []byte
+	Gas   uint64  // 为 0 时通过 eth_estimateGas 估算
+	Nonce *uint64 // 为 nil 时由共享的 nonce 管理器分配, 需要启用持久化
+}
+
+// Build 在联网机器上构建未签名交易, 填充 nonce、手续费和链ID
+//
+// 离线签名通常需要较长时间, 并且每次构建都是单独的进程: 未指定 nonce 时要求 nonce 管理器启用持久化,
+// 分配的 nonce 记录为已构建, 在广播或 24 小时内不会再分配给其他交易
+func Build(ctx context.Context, client util.Client, request BuildRequest) (offline *OfflineTx, err error) {
+	if request.Value == nil {
+		request.Value = new(big.Int)
+	}
+	if request.Value.Sign() < 0 {
+		return nil, fmt.Errorf("%w: 转账金额不能为负数", util.ErrInvalidArgument)
+	}
+	if request.To == nil && len(request.Data) == 0 {
+		return nil, fmt.Errorf("%w: 需要指定接收地址或合约部署数据", util.ErrInvalidArgument)
+	}
+	if request.Nonce == nil && !util.Nonces().Persistent() {
+		return nil, fmt.Errorf("%w: 离线构建交易需要通过 --nonce 指定 nonce, 或通过 --nonce-store 启用持久化的 nonce 管理器, 避免连续构建的交易使用相同的 nonce", util.ErrInvalidArgument)
+	}
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("获取链ID失败: %w", errs.Classify(err))
+	}
+
+	var nonce uint64
+	if request.Nonce != nil {
+		nonce = *request.Nonce
+	} else {
+		lease, err := util.Nonces().Acquire(ctx, client, chainID, request.From)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			if err != nil {
+				lease.Release(ctx)
+			} else {
+				lease.Built(ctx)
+			}
+		}()
+		nonce = lease.Nonce
+	}
+
+	gas := request.Gas
+	if gas == 0 {
+		gas, err = client.EstimateGas(ctx, ethereum.CallMsg{From: request.From, To: request.To, Value: request.Value, Data: request.Data})
+		if err != nil {
+			return nil, fmt.Errorf("估算 gas 失败: %w", errs.Classify(err))
+		}
+	}
+
+	fees, err := SuggestFees(ctx, client, CurrentFeeOptions())
+	if err != nil {
+		return nil, err
+	}
+	offline = &OfflineTx{
+		ChainID: (*hexutil.Big)(chainID),
+		From:    request.From,
+		Tx:      fees.NewTx(chainID, nonce, request.To, request.Value, gas, request.Data),
+	}
+	if network, err := util.CurrentNetwork(); err == nil {
+		offline.Network = network.Name
+	}
+	util.Logf("已构建未签名交易: from %s, nonce %d, gas %d, 链ID %s", request.From.Hex(), nonce, gas, chainID)
+	return offline, nil
+}
+
+// Signed 判断交易是否已签名
+func (o *OfflineTx) Signed() bool {
+	v, r, s := o.Tx.RawSignatureValues()
+	return v.Sign() != 0 || r.Sign() != 0 || s.Sign() != 0
+}
+
+// Sign 使用签名器签名交易, 本地账户签名时不需要连接网络
+// 签名账户必须与文件中的 from 一致, 避免在离线机器上用错私钥
+func (o *OfflineTx) Sign(ctx context.Context, signer account.Signer) error {
+	if o.Signed() {
+		return fmt.Errorf("%w: 交易 %s 已经签名", util.ErrInvalidArgument, o.Tx.Hash().Hex())
+	}
+	if address := signer.Address(); address != o.From {
+		return fmt.Errorf("%w: 签名账户 %s 与交易发送方 %s 不一致", util.ErrInvalidArgument, address.Hex(), o.From.Hex())
+	}
+	signedTx, err := signer.SignTx(ctx, o.Tx, o.ChainID.ToInt())
+	if err != nil {
+		return fmt.Errorf("交易签名失败: %w", err)
+	}
+	raw, err := signedTx.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("交易编码失败: %w", err)
+	}
+	o.Tx, o.Raw = signedTx, raw
+	util.Logf("已签名交易 %s (nonce %d)", signedTx.Hash().Hex(), signedTx.Nonce())
+	return nil
+}
+
+// validate 检查交易文件的字段是否完整且相互一致
+func (o *OfflineTx) validate() error {
+	if o.Tx == nil || o.ChainID == nil {
+		return fmt.Errorf("%w: 交易文件缺少 tx 或 chainId 字段", util.ErrInvalidArgument)
+	}
+	// 未签名的传统交易没有链ID, 签名后从 v 推导
+	if o.Tx.Type() != types.LegacyTxType || o.Signed() && o.Tx.Protected() {
+		if txChainID := o.Tx.ChainId(); txChainID.Cmp(o.ChainID.ToInt()) != 0 {
+			return fmt.Errorf("%w: 交易的链ID %s 与文件中的链ID %s 不一致", util.ErrInvalidArgument, txChainID, o.ChainID)
+		}
+	}
+	if len(o.Raw) == 0 {
+		return nil
+	}
+	raw, err := DecodeRawTx(o.Raw)
+	if err != nil {
+		return err
+	}
+	if raw.Hash() != o.Tx.Hash() {
+		return fmt.Errorf("%w: 交易文件的 raw 字段 (%s) 与 tx 字段 (%s) 不一致", util.ErrInvalidArgument, raw.Hash().Hex(), o.Tx.Hash().Hex())
+	}
+	return nil
+}
+
+// ReadOfflineTx 读取交易文件, path 为 "-" 时从标准输入读取
+func ReadOfflineTx(path string) (*OfflineTx, error) {
+	data, err := readInput(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseOfflineTx(data, path)
+}
+
+func parseOfflineTx(data []byte, path string) (*OfflineTx, error) {
+	var offline OfflineTx
+	if err := json.Unmarshal(data, &offline); err != nil {
+		return nil, fmt.Errorf("%w: 解析交易文件 %s 失败: %w", util.ErrInvalidArgument, path, err)
+	}
+	if err := offline.validate(); err != nil {
+		return nil, err
+	}
+	return &offline, nil
+}
+
+// Write 将交易文件写入 path, path 为空或 "-" 时输出到标准输出
+func (o *OfflineTx) Write(path string) error {
+	data, err := json.MarshalIndent(o, "", "  ")
+	if err != nil {
+		return fmt.Errorf("交易编码失败: %w", err)
+	}
+	data = append(data, '\n')
+	if path == "" || path == "-" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("写入交易文件失败: %w", err)
+	}
+	util.Logf("交易文件已写入 %s", path)
+	return nil
+}
+
+// DecodeRawTx 解码签名交易的 RLP 编码, 支持传统交易和类型化交易 (EIP-2718)
+func DecodeRawTx(raw []byte) (*types.Transaction, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(raw); err != nil {
+		return nil, fmt.Errorf("%w: 解码交易失败: %w", util.ErrInvalidArgument, err)
+	}
+	return tx, nil
+}
+
+// LoadSignedTx 读取待广播的签名交易, 输入可以是 tx sign 输出的交易文件, 也可以是 0x 开头的 RLP 十六进制字符串
+func LoadSignedTx(path string) (*types.Transaction, error) {
+	data, err := readInput(path)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimSpace(data)
+	if !bytes.HasPrefix(data, []byte("{")) {
+		return ParseRawTx(string(data))
+	}
+	offline, err := parseOfflineTx(data, path)
+	if err != nil {
+		return nil, err
+	}
+	if !offline.Signed() {
+		return nil, fmt.Errorf("%w: 交易文件 %s 尚未签名, 请先执行 tx sign", util.ErrInvalidArgument, path)
+	}
+	return offline.Tx, nil
+}
+
+// ParseRawTx 解析 0x 开头的签名交易 RLP 十六进制字符串
+func ParseRawTx(raw string) (*types.Transaction, error) {
+	data, err := hexutil.Decode(strings.TrimSpace(raw))
+	if err != nil {
+		return nil, fmt.Errorf("%w: 签名交易格式错误: %w", util.ErrInvalidArgument, err)
+	}
+	return DecodeRawTx(data)
+}
+
+// Broadcast 广播已签名的交易并等待确认, 可以在任意联网机器上执行
+func Broadcast(ctx context.Context, client util.Client, tx *types.Transaction) (*types.Receipt, error) {
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("获取链ID失败: %w", errs.Classify(err))
+	}
+	if tx.Protected() && tx.ChainId().Cmp(chainID) != 0 {
+		return nil, fmt.Errorf("%w: 交易的链ID %s 与当前网络的链ID %s 不一致", util.ErrInvalidArgument, tx.ChainId(), chainID)
+	}
+	from, err := types.Sender(types.LatestSignerForChainID(chainID), tx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: 无法恢复交易发送方, 交易可能未签名: %w", util.ErrInvalidArgument, err)
+	}
+	if err := util.DryRunTx(ctx, client, from, tx); err != nil {
+		return nil, err
+	}
+	util.Logf("广播交易 %s: from %s, nonce %d", tx.Hash().Hex(), from.Hex(), tx.Nonce())
+
+	if err := util.SendTransaction(ctx, client, tx); err != nil {
+		return nil, fmt.Errorf("发送交易失败: %w", err)
+	}
+	util.Nonces().Sent(ctx, chainID, from, tx)
+
+	receipt, err := util.WaitReceipt(ctx, client, tx.Hash())
+	if err != nil {
+		return nil, err
+	}
+	return receipt, util.DiagnoseReceipt(ctx, client, receipt)
+}
+
+// readInput 读取文件内容, path 为 "-" 时从标准输入读取
+func readInput(path string) ([]byte, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: 读取交易文件失败: %w", util.ErrInvalidArgument, err)
+	}
+	return data, nil
+}
@@ -0,0 +1,190 @@
+package transactions_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"path/filepath"
+	"task1/account"
+	"task1/auction/auctiontest"
+	"task1/transactions"
+	"task1/util"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// TestOfflineRoundTrip 构建、写入、读取、签名、读取签名交易并广播, 传统交易和 EIP-1559 交易都经过 JSON 和 RLP 往返
+func TestOfflineRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	chain := auctiontest.New(t, 2)
+	if err := util.SetNonceStore(filepath.Join(t.TempDir(), "nonces.json")); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { transactions.SetFeeOptions(transactions.FeeOptions{}) })
+	chain.Use(0)
+	signer, err := account.LoadSigner(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, legacy := range []bool{true, false} {
+		transactions.SetFeeOptions(transactions.FeeOptions{Legacy: legacy})
+		to := chain.Accounts[1]
+		request := transactions.BuildRequest{From: chain.Accounts[0], To: &to, Value: big.NewInt(1e15)}
+		built, err := transactions.Build(ctx, chain.Client, request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// 第一笔交易广播前再次构建, 已构建的 nonce 不会被重新分配
+		next, err := transactions.Build(ctx, chain.Client, request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if next.Tx.Nonce() != built.Tx.Nonce()+1 {
+			t.Fatalf("连续构建的 nonce 为 %d 和 %d", built.Tx.Nonce(), next.Tx.Nonce())
+		}
+		wantType := uint8(types.DynamicFeeTxType)
+		if legacy {
+			wantType = types.LegacyTxType
+		}
+		if built.Tx.Type() != wantType {
+			t.Fatalf("交易类型为 %d, 预期 %d", built.Tx.Type(), wantType)
+		}
+
+		unsigned := filepath.Join(t.TempDir(), "unsigned.json")
+		if err := built.Write(unsigned); err != nil {
+			t.Fatal(err)
+		}
+		read, err := transactions.ReadOfflineTx(unsigned)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if read.Signed() || read.Tx.Hash() != built.Tx.Hash() || read.From != built.From || read.ChainID.ToInt().Cmp(built.ChainID.ToInt()) != 0 {
+			t.Fatalf("读取的未签名交易与构建的交易不一致: %+v", read)
+		}
+		if _, err := transactions.LoadSignedTx(unsigned); !errors.Is(err, util.ErrInvalidArgument) {
+			t.Fatalf("读取未签名交易作为签名交易的错误为 %v", err)
+		}
+
+		if err := read.Sign(ctx, signer); err != nil {
+			t.Fatal(err)
+		}
+		signed := filepath.Join(t.TempDir(), "signed.json")
+		if err := read.Write(signed); err != nil {
+			t.Fatal(err)
+		}
+		tx, err := transactions.LoadSignedTx(signed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		raw, err := transactions.ParseRawTx(hexutil.Encode(read.Raw))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tx.Hash() != read.Tx.Hash() || raw.Hash() != tx.Hash() {
+			t.Fatalf("签名交易 %s 经 JSON / RLP 读取后为 %s / %s", read.Tx.Hash().Hex(), tx.Hash().Hex(), raw.Hash().Hex())
+		}
+
+		receipt, err := transactions.Broadcast(ctx, chain.Client, tx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if receipt.TxHash != tx.Hash() || receipt.Status != types.ReceiptStatusSuccessful {
+			t.Fatalf("交易 %s 的收据为 %+v", tx.Hash().Hex(), receipt)
+		}
+		// 没有广播的第二笔交易在下一轮使用 --nonce 重新构建时覆盖
+		nonce := next.Tx.Nonce()
+		request.Nonce = &nonce
+		replacement, err := transactions.Build(ctx, chain.Client, request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := replacement.Sign(ctx, signer); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := transactions.Broadcast(ctx, chain.Client, replacement.Tx); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestOfflineBuildRequiresNonce(t *testing.T) {
+	chain := auctiontest.New(t, 2)
+	to := chain.Accounts[1]
+	request := transactions.BuildRequest{From: chain.Accounts[0], To: &to, Value: big.NewInt(1)}
+	if _, err := transactions.Build(context.Background(), chain.Client, request); !errors.Is(err, util.ErrInvalidArgument) {
+		t.Fatalf("未启用 nonce 持久化且未指定 nonce 时的错误为 %v, 预期 %v", err, util.ErrInvalidArgument)
+	}
+	nonce := uint64(0)
+	request.Nonce = &nonce
+	if _, err := transactions.Build(context.Background(), chain.Client, request); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestOfflineChainIDMismatch 交易文件中的链ID与交易本身的链ID或签名不一致时拒绝读取
+func TestOfflineChainIDMismatch(t *testing.T) {
+	ctx := context.Background()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := account.NewKeySigner(key, "测试私钥")
+	to := common.HexToAddress("0x00000000000000000000000000000000000000bb")
+	dynamic := types.NewTx(&types.DynamicFeeTx{ChainID: big.NewInt(1), To: &to, Gas: 21000, GasFeeCap: big.NewInt(1), GasTipCap: big.NewInt(1)})
+	legacy := types.NewTx(&types.LegacyTx{To: &to, Gas: 21000, GasPrice: big.NewInt(1)})
+	signedLegacy, err := signer.SignTx(ctx, legacy, big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	signedDynamic, err := signer.SignTx(ctx, dynamic, big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherRaw, err := signedLegacy.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		chainID int64
+		tx      *types.Transaction
+		raw     []byte
+		wantErr bool
+	}{
+		{"未签名的 EIP-1559 交易链ID一致", 1, dynamic, nil, false},
+		{"未签名的 EIP-1559 交易链ID不一致", 5, dynamic, nil, true},
+		{"未签名的传统交易没有链ID", 5, legacy, nil, false},
+		{"签名的传统交易链ID一致", 1, signedLegacy, otherRaw, false},
+		{"签名的传统交易链ID不一致", 5, signedLegacy, otherRaw, true},
+		{"签名的 EIP-1559 交易链ID不一致", 5, signedDynamic, nil, true},
+		{"raw 与 tx 不一致", 1, signedDynamic, otherRaw, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "tx.json")
+			offline := &transactions.OfflineTx{ChainID: (*hexutil.Big)(big.NewInt(tt.chainID)), From: signer.Address(), Tx: tt.tx, Raw: tt.raw}
+			if err := offline.Write(path); err != nil {
+				t.Fatal(err)
+			}
+			_, err := transactions.ReadOfflineTx(path)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("读取交易文件的错误为 %v, 预期出错: %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, util.ErrInvalidArgument) {
+				t.Fatalf("错误 %v 不是 %v", err, util.ErrInvalidArgument)
+			}
+		})
+	}
+
+	// 广播时交易的链ID必须与当前网络一致
+	chain := auctiontest.New(t, 1)
+	if _, err := transactions.Broadcast(ctx, chain.Client, signedDynamic); !errors.Is(err, util.ErrInvalidArgument) {
+		t.Fatalf("广播其他链的交易的错误为 %v, 预期 %v", err, util.ErrInvalidArgument)
+	}
+}
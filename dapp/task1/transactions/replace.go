@@ -0,0 +1,190 @@
+package transactions
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"task1/account"
+	"task1/errs"
+	"task1/util"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// priceBump 替换交易至少需要提高的手续费百分比, 与 geth 交易池默认的 --txpool.pricebump 一致
+const priceBump = 10
+
+// cancelGas 取消交易使用的 gas 限制 (0 金额转账给自己)
+const cancelGas = 21000
+
+// SpeedUp 使用更高的手续费重新广播卡住的交易, 交易内容(接收地址、金额、数据、gas 限制)不变
+// 返回原交易或替换交易中先被打包的那一笔的收据
+func SpeedUp(ctx context.Context, client util.Client, hash common.Hash) (*types.Receipt, error) {
+	return replace(ctx, client, hash, false)
+}
+
+// Cancel 使用同一 nonce 和更高的手续费向自己发送 0 金额的交易, 使卡住的交易失效
+// 返回原交易或取消交易中先被打包的那一笔的收据
+func Cancel(ctx context.Context, client util.Client, hash common.Hash) (*types.Receipt, error) {
+	return replace(ctx, client, hash, true)
+}
+
+// replace 使用原交易的 nonce 构建并发送替换交易, 然后等待两笔交易中任意一笔确认
+func replace(ctx context.Context, client util.Client, hash common.Hash, cancel bool) (*types.Receipt, error) {
+	action := "加速"
+	if cancel {
+		action = "取消"
+	}
+	original, pending, err := client.TransactionByHash(ctx, hash)
+	if err != nil {
+		return nil, fmt.Errorf("查询交易 %s 失败: %w", hash.Hex(), errs.Classify(err))
+	}
+	if !pending {
+		return nil, fmt.Errorf("%w: 交易 %s 已经被打包, 无需%s", util.ErrInvalidArgument, hash.Hex(), action)
+	}
+	if original.Type() != types.LegacyTxType && original.Type() != types.AccessListTxType && original.Type() != types.DynamicFeeTxType {
+		return nil, fmt.Errorf("%w: 不支持%s类型为 %d 的交易", util.ErrInvalidArgument, action, original.Type())
+	}
+
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("获取链ID失败: %w", errs.Classify(err))
+	}
+	fromAddress, err := account.Address(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sender, err := types.Sender(types.LatestSignerForChainID(chainID), original)
+	if err != nil {
+		return nil, fmt.Errorf("%w: 无法恢复交易 %s 的发送方: %w", util.ErrInvalidArgument, hash.Hex(), err)
+	}
+	if sender != fromAddress {
+		return nil, fmt.Errorf("%w: 交易 %s 的发送方 %s 不是当前账户 %s", util.ErrInvalidArgument, hash.Hex(), sender.Hex(), fromAddress.Hex())
+	}
+	util.Logf("%s交易 %s (nonce %d)", action, hash.Hex(), original.Nonce())
+
+	// 原交易是传统交易时替换交易也使用 gasPrice, 避免节点按不同的规则比较手续费
+	options := CurrentFeeOptions()
+	options.Legacy = original.Type() != types.DynamicFeeTxType
+	fees, err := SuggestFees(ctx, client, options)
+	if err != nil {
+		return nil, err
+	}
+	bumpFees(fees, original)
+
+	// 替换交易手续费不足时继续在上一次的基础上提高; nonce 过低说明原交易已经被打包
+	var replacement *types.Transaction
+	sign := account.Sign(fromAddress, chainID)
+	err = errs.Retry(ctx, func(attempt int) error {
+		tx := newReplacement(chainID, original, fees, fromAddress, cancel)
+		// --dry-run 时模拟未签名的交易, 不签名
+		if err := util.DryRunTx(ctx, client, fromAddress, tx); err != nil {
+			return errs.Permanent(err)
+		}
+		signedTx, err := sign(ctx, tx)
+		if err != nil {
+			return errs.Permanent(err)
+		}
+		err = util.SendTransaction(ctx, client, signedTx)
+		if err == nil {
+			replacement = signedTx
+			return nil
+		}
+		switch errs.KindOf(err) {
+		case errs.ErrNonceTooLow:
+			util.Logf("nonce %d 已被使用, 原交易可能已经被打包", original.Nonce())
+			return nil
+		case errs.ErrReplacementUnderpriced, errs.ErrUnderpriced:
+			util.Logf("第 %d 次发送替换交易 %s 失败, 提高手续费后重试: %v", attempt, signedTx.Hash().Hex(), err)
+			bumpFees(fees, signedTx)
+			return err
+		}
+		return errs.Permanent(err)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("发送%s交易失败: %w", action, err)
+	}
+
+	hashes := []common.Hash{hash}
+	if replacement != nil {
+		util.Nonces().Sent(ctx, chainID, fromAddress, replacement)
+		util.Logf("已发送%s交易 %s, 等待原交易或替换交易被打包", action, replacement.Hash().Hex())
+		hashes = append(hashes, replacement.Hash())
+	}
+
+	receipt, err := util.NewWaiter(client, util.DefaultWaitConfig()).WaitAny(ctx, hashes)
+	if err != nil {
+		return nil, err
+	}
+	if receipt.TxHash == hash {
+		util.Logf("原交易 %s 先被打包, %s未生效", hash.Hex(), action)
+	} else {
+		util.Logf("%s交易 %s 已被打包, 原交易 %s 已失效", action, receipt.TxHash.Hex(), hash.Hex())
+	}
+	return receipt, util.DiagnoseReceipt(ctx, client, receipt)
+}
+
+// bumpFees 将手续费提高到不低于 tx 手续费的 110%, 满足节点的替换规则
+func bumpFees(fees *Fees, tx *types.Transaction) {
+	if fees.Legacy {
+		fees.GasPrice = bigMax(fees.GasPrice, bump(tx.GasPrice()))
+		util.Logf("替换交易 gas 价格: %s gwei (原交易 %s gwei)", util.FormatUnits(fees.GasPrice, 9), util.FormatUnits(tx.GasPrice(), 9))
+		return
+	}
+	fees.GasTipCap = bigMax(fees.GasTipCap, bump(tx.GasTipCap()))
+	fees.GasFeeCap = bigMax(fees.GasFeeCap, bump(tx.GasFeeCap()), fees.GasTipCap)
+	util.Logf("替换交易优先费 %s gwei (原交易 %s gwei), 最高费用 %s gwei (原交易 %s gwei)",
+		util.FormatUnits(fees.GasTipCap, 9), util.FormatUnits(tx.GasTipCap(), 9),
+		util.FormatUnits(fees.GasFeeCap, 9), util.FormatUnits(tx.GasFeeCap(), 9))
+}
+
+// bump 返回 value * (100 + priceBump) / 100 + 1, 加 1 避免整数除法向下取整后恰好低于要求
+func bump(value *big.Int) *big.Int {
+	bumped := new(big.Int).Mul(value, big.NewInt(100+priceBump))
+	bumped.Div(bumped, big.NewInt(100))
+	return bumped.Add(bumped, big.NewInt(1))
+}
+
+func bigMax(values ...*big.Int) *big.Int {
+	var highest *big.Int
+	for _, v := range values {
+		if highest == nil || v.Cmp(highest) > 0 {
+			highest = v
+		}
+	}
+	return highest
+}
+
+// newReplacement 构建与 original 使用相同 nonce 的未签名替换交易
+func newReplacement(chainID *big.Int, original *types.Transaction, fees *Fees, from common.Address, cancel bool) *types.Transaction {
+	if cancel {
+		return fees.NewTx(chainID, original.Nonce(), &from, new(big.Int), cancelGas, nil)
+	}
+	if original.Type() == types.DynamicFeeTxType {
+		return types.NewTx(&types.DynamicFeeTx{
+			ChainID:    chainID,
+			Nonce:      original.Nonce(),
+			GasTipCap:  fees.GasTipCap,
+			GasFeeCap:  fees.GasFeeCap,
+			Gas:        original.Gas(),
+			To:         original.To(),
+			Value:      original.Value(),
+			Data:       original.Data(),
+			AccessList: original.AccessList(),
+		})
+	}
+	if original.Type() == types.AccessListTxType {
+		return types.NewTx(&types.AccessListTx{
+			ChainID:    chainID,
+			Nonce:      original.Nonce(),
+			GasPrice:   fees.GasPrice,
+			Gas:        original.Gas(),
+			To:         original.To(),
+			Value:      original.Value(),
+			Data:       original.Data(),
+			AccessList: original.AccessList(),
+		})
+	}
+	return fees.NewTx(chainID, original.Nonce(), original.To(), original.Value(), original.Gas(), original.Data())
+}
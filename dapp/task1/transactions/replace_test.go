@@ -0,0 +1,125 @@
+package transactions
+
+import (
+	"math/big"
+	"slices"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestBump(t *testing.T) {
+	for _, tt := range []struct{ value, want int64 }{
+		{0, 1},
+		{1, 2},
+		{10, 12},
+		{1000000000, 1100000001},
+	} {
+		if got := bump(big.NewInt(tt.value)); got.Int64() != tt.want {
+			t.Errorf("bump(%d) = %s, 预期 %d", tt.value, got, tt.want)
+		}
+	}
+}
+
+// atLeastBumped 判断 value 是否达到 original 的 110%
+func atLeastBumped(value, original *big.Int) bool {
+	return new(big.Int).Mul(value, big.NewInt(100)).Cmp(new(big.Int).Mul(original, big.NewInt(100+priceBump))) >= 0
+}
+
+func TestBumpFees(t *testing.T) {
+	to := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	dynamic := func(tip, feeCap int64) *types.Transaction {
+		return types.NewTx(&types.DynamicFeeTx{GasTipCap: gwei(tip), GasFeeCap: gwei(feeCap), Gas: 21000, To: &to})
+	}
+	tests := []struct {
+		name     string
+		original *types.Transaction
+		fees     Fees // 当前网络估算的手续费
+		want     Fees
+	}{
+		{"EIP-1559 当前手续费较低", dynamic(2, 30), Fees{GasTipCap: gwei(1), GasFeeCap: gwei(10)},
+			Fees{GasTipCap: bump(gwei(2)), GasFeeCap: bump(gwei(30))}},
+		{"EIP-1559 当前手续费已经足够", dynamic(2, 30), Fees{GasTipCap: gwei(5), GasFeeCap: gwei(50)},
+			Fees{GasTipCap: gwei(5), GasFeeCap: gwei(50)}},
+		{"EIP-1559 只有最高费用足够", dynamic(2, 30), Fees{GasTipCap: gwei(1), GasFeeCap: gwei(50)},
+			Fees{GasTipCap: bump(gwei(2)), GasFeeCap: gwei(50)}},
+		{"EIP-1559 最高费用不低于优先费", dynamic(10, 10), Fees{GasTipCap: gwei(20), GasFeeCap: gwei(5)},
+			Fees{GasTipCap: gwei(20), GasFeeCap: gwei(20)}},
+		{"传统交易", types.NewTx(&types.LegacyTx{GasPrice: gwei(20), Gas: 21000, To: &to}), Fees{Legacy: true, GasPrice: gwei(15)},
+			Fees{Legacy: true, GasPrice: bump(gwei(20))}},
+		{"EIP-2930 交易", types.NewTx(&types.AccessListTx{GasPrice: gwei(20), Gas: 21000, To: &to}), Fees{Legacy: true, GasPrice: gwei(30)},
+			Fees{Legacy: true, GasPrice: gwei(30)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fees := tt.fees
+			bumpFees(&fees, tt.original)
+			if fees.Legacy {
+				if fees.GasPrice.Cmp(tt.want.GasPrice) != 0 || !atLeastBumped(fees.GasPrice, tt.original.GasPrice()) {
+					t.Fatalf("gas 价格为 %s, 预期 %s", fees.GasPrice, tt.want.GasPrice)
+				}
+				return
+			}
+			if fees.GasTipCap.Cmp(tt.want.GasTipCap) != 0 || fees.GasFeeCap.Cmp(tt.want.GasFeeCap) != 0 {
+				t.Fatalf("优先费 %s, 最高费用 %s, 预期 %s 和 %s", fees.GasTipCap, fees.GasFeeCap, tt.want.GasTipCap, tt.want.GasFeeCap)
+			}
+			// 节点要求优先费和最高费用都提高 10%
+			if !atLeastBumped(fees.GasTipCap, tt.original.GasTipCap()) || !atLeastBumped(fees.GasFeeCap, tt.original.GasFeeCap()) {
+				t.Fatalf("优先费 %s, 最高费用 %s 没有比原交易提高 %d%%", fees.GasTipCap, fees.GasFeeCap, priceBump)
+			}
+		})
+	}
+}
+
+func TestNewReplacement(t *testing.T) {
+	chainID := big.NewInt(1337)
+	from := common.HexToAddress("0x00000000000000000000000000000000000000f0")
+	to := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	accessList := types.AccessList{{Address: to, StorageKeys: []common.Hash{{1}}}}
+	data := []byte{0xd0, 0x9d, 0xe0, 0x8a}
+	dynamicFees := &Fees{GasTipCap: gwei(3), GasFeeCap: gwei(40)}
+	legacyFees := &Fees{Legacy: true, GasPrice: gwei(25)}
+
+	tests := []struct {
+		name     string
+		original *types.Transaction
+		fees     *Fees
+		cancel   bool
+		wantType uint8
+	}{
+		{"加速 EIP-1559 交易", types.NewTx(&types.DynamicFeeTx{ChainID: chainID, Nonce: 7, GasTipCap: gwei(1), GasFeeCap: gwei(20), Gas: 60000,
+			To: &to, Value: big.NewInt(5), Data: data, AccessList: accessList}), dynamicFees, false, types.DynamicFeeTxType},
+		{"加速 EIP-2930 交易", types.NewTx(&types.AccessListTx{ChainID: chainID, Nonce: 7, GasPrice: gwei(20), Gas: 60000,
+			To: &to, Value: big.NewInt(5), Data: data, AccessList: accessList}), legacyFees, false, types.AccessListTxType},
+		{"加速传统交易", types.NewTx(&types.LegacyTx{Nonce: 7, GasPrice: gwei(20), Gas: 60000, To: &to, Value: big.NewInt(5), Data: data}),
+			legacyFees, false, types.LegacyTxType},
+		{"取消 EIP-1559 交易", types.NewTx(&types.DynamicFeeTx{ChainID: chainID, Nonce: 7, GasTipCap: gwei(1), GasFeeCap: gwei(20), Gas: 60000,
+			To: &to, Value: big.NewInt(5), Data: data, AccessList: accessList}), dynamicFees, true, types.DynamicFeeTxType},
+		{"取消传统交易", types.NewTx(&types.LegacyTx{Nonce: 7, GasPrice: gwei(20), Gas: 60000, To: &to, Value: big.NewInt(5), Data: data}),
+			legacyFees, true, types.LegacyTxType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := newReplacement(chainID, tt.original, tt.fees, from, tt.cancel)
+			if tx.Type() != tt.wantType || tx.Nonce() != tt.original.Nonce() {
+				t.Fatalf("替换交易类型 %d, nonce %d, 预期 %d 和 %d", tx.Type(), tx.Nonce(), tt.wantType, tt.original.Nonce())
+			}
+			if tt.fees.Legacy && tx.GasPrice().Cmp(tt.fees.GasPrice) != 0 ||
+				!tt.fees.Legacy && (tx.GasTipCap().Cmp(tt.fees.GasTipCap) != 0 || tx.GasFeeCap().Cmp(tt.fees.GasFeeCap) != 0) {
+				t.Fatalf("替换交易没有使用提高后的手续费: %+v", tx)
+			}
+			if tt.cancel {
+				// 取消交易是向自己发送的 0 金额交易
+				if *tx.To() != from || tx.Value().Sign() != 0 || len(tx.Data()) != 0 || tx.Gas() != cancelGas {
+					t.Fatalf("取消交易: to %s, value %s, data %x, gas %d", tx.To().Hex(), tx.Value(), tx.Data(), tx.Gas())
+				}
+				return
+			}
+			if *tx.To() != *tt.original.To() || tx.Value().Cmp(tt.original.Value()) != 0 || !slices.Equal(tx.Data(), tt.original.Data()) ||
+				tx.Gas() != tt.original.Gas() || len(tx.AccessList()) != len(tt.original.AccessList()) {
+				t.Fatalf("加速交易的内容与原交易不一致: %+v", tx)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package transactions_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"task1/auction/auctiontest"
+	"task1/transactions"
+	"task1/util"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// sendStuck 由 Accounts[0] 发送手续费较低的交易, 不出块, 交易留在交易池中
+func sendStuck(t *testing.T, chain *auctiontest.Chain, to common.Address) *types.Transaction {
+	t.Helper()
+	ctx := context.Background()
+	client := chain.Backend.Client()
+	nonce, err := client.PendingNonceAt(ctx, chain.Accounts[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	header, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := chain.Transactor(t, 0)
+	tx, err := opts.Signer(opts.From, types.NewTx(&types.DynamicFeeTx{
+		ChainID:   params.AllDevChainProtocolChanges.ChainID,
+		Nonce:     nonce,
+		GasTipCap: big.NewInt(params.GWei),
+		GasFeeCap: new(big.Int).Add(header.BaseFee, big.NewInt(params.GWei)),
+		Gas:       21000,
+		To:        &to,
+		Value:     big.NewInt(1e15),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.SendTransaction(ctx, tx); err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+func TestSpeedUpAndCancel(t *testing.T) {
+	ctx := context.Background()
+	chain := auctiontest.New(t, 2)
+	chain.Use(0)
+	to := chain.Accounts[1]
+
+	for _, cancel := range []bool{false, true} {
+		original := sendStuck(t, chain, to)
+		replace := transactions.SpeedUp
+		if cancel {
+			replace = transactions.Cancel
+		}
+		receipt, err := replace(ctx, chain.Client, original.Hash())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if receipt.TxHash == original.Hash() {
+			t.Fatal("预期替换交易先被打包")
+		}
+		if _, err := chain.Client.TransactionReceipt(ctx, original.Hash()); !errors.Is(err, ethereum.NotFound) {
+			t.Fatalf("原交易的收据查询结果为 %v, 预期已失效", err)
+		}
+		replacement, _, err := chain.Client.TransactionByHash(ctx, receipt.TxHash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if replacement.Nonce() != original.Nonce() {
+			t.Fatalf("替换交易的 nonce 为 %d, 预期 %d", replacement.Nonce(), original.Nonce())
+		}
+		if replacement.GasTipCap().Cmp(original.GasTipCap()) <= 0 || replacement.GasFeeCap().Cmp(original.GasFeeCap()) <= 0 {
+			t.Fatalf("替换交易的手续费 %s/%s 没有提高", replacement.GasTipCap(), replacement.GasFeeCap())
+		}
+		wantTo, wantValue := to, original.Value()
+		if cancel {
+			wantTo, wantValue = chain.Accounts[0], new(big.Int)
+		}
+		if *replacement.To() != wantTo || replacement.Value().Cmp(wantValue) != 0 {
+			t.Fatalf("替换交易发送 %s 到 %s, 预期 %s 到 %s", replacement.Value(), replacement.To().Hex(), wantValue, wantTo.Hex())
+		}
+	}
+
+	// 已经打包的交易不能替换
+	mined := sendStuck(t, chain, to)
+	chain.Backend.Commit()
+	if _, err := transactions.SpeedUp(ctx, chain.Client, mined.Hash()); !errors.Is(err, util.ErrInvalidArgument) {
+		t.Fatalf("加速已打包交易的错误为 %v, 预期 %v", err, util.ErrInvalidArgument)
+	}
+}
@@ -2,15 +2,14 @@ package transactions
 
 import (
 	"context"
-	"crypto/ecdsa"
-	"log"
-	"math"
+	"fmt"
 	"math/big"
+	"task1/account"
+	"task1/errs"
 	"task1/util"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/crypto"
 )
 
 // 准备一个 Sepolia 测试网络的以太坊账户，并获取其私钥。
@@ -21,78 +20,56 @@ import (
 // Transactions 函数用于执行以太坊转账交易
 // 参数:
 //
+//	ctx - 取消或超时后停止等待收据
+//	client - 以太坊客户端
 //	to - 接收地址的十六进制字符串
-//	amount - 转账金额(整数形式)
-//	digits - 小数位数
+//	value - 转账金额(wei), 可以通过 util.ParseAmount 从 "1.5ether" / "250gwei" 等字符串转换
 //
-// 最终价值: ( amount * 10^digits )wei
-//
-// 例如:
-//
-//	amount:1 digits:18 表示转账 1 ETH
-//	amount:1 digits:15 表示转账 0.00001 ETH
-//	amount:1 digits:1 表示转账 1*10^-18 ETH
-func Transactions(to string, amount int64, digits uint) {
-	log.Printf("准备向 %s 转账 %d wei 约 %f eth \n", to, amount*int64(math.Pow10(int(digits))), float64(amount)*math.Pow10(int(digits-18)))
-	// 加载以太坊客户端
-	client := util.LoadClient()
-	// 加载私钥
-	// 从环境变量中获取私钥字符串并转换为ECDSA私钥对象
-	privateKey, err := crypto.HexToECDSA(util.LoadEnv("<PRIVATE_KEY>"))
-	if err != nil {
-		log.Fatal("私钥解析失败")
+// 返回交易收据, 交易执行失败时同时返回收据和 util.ErrTxFailed
+func Transactions(ctx context.Context, client util.Client, to string, value *big.Int) (*types.Receipt, error) {
+	if !common.IsHexAddress(to) {
+		return nil, fmt.Errorf("%w: 接收地址格式错误: %s", util.ErrInvalidArgument, to)
 	}
-	// 获取公钥
-	// 从私钥对象获取对应的公钥
-	publicKey := privateKey.Public()
-	publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
-	if !ok {
-		log.Fatal("cannot assert type: publicKey is not of type *ecdsa.PublicKey")
+	if value == nil || value.Sign() <= 0 {
+		return nil, fmt.Errorf("%w: 转账金额必须为正数", util.ErrInvalidArgument)
 	}
-	// 从公钥生成发送者地址
-	// 将公钥转换为以太坊地址格式
-	fromAddress := crypto.PubkeyToAddress(*publicKeyECDSA)
-	// 获取账户当前Nonce
-	// Nonce用于确保交易顺序的唯一性
-	nonce, err := client.PendingNonceAt(context.Background(), fromAddress)
+	network, err := util.CurrentNetwork()
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
-	// 设置转账金额和Gas参数
-	value := big.NewInt(int64(math.Pow10(int(digits))) * amount) // 转账金额, 例如: 10^(18-5) (以wei为单位) => 0.00001 ETH
-	gasLimit := uint64(21000)                                    // Gas限制: 21000 (标准ETH转账)
-	// 获取建议的Gas价格
-	// SuggestGasPrice 返回网络当前建议的Gas价格
-	gasPrice, err := client.SuggestGasPrice(context.Background())
+	util.Logf("[%s] 准备向 %s 转账 %s wei (%s %s)", network.Name, to, value, util.FormatUnits(value, int(network.Decimals)), network.Symbol)
+	// 发送者地址, 签名器 (本地私钥、keystore 账户或外部签名服务) 在签名时才加载
+	fromAddress, err := account.Address(ctx)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
-	// 构建未签名交易
-	// types.NewTransaction 创建新的交易对象
-	tx := types.NewTransaction(nonce, common.HexToAddress(to), value, gasLimit, gasPrice, nil)
-	// 获取网络ID并签名交易
-	// NetworkID 返回当前连接的以太坊网络ID
-	chainID, err := client.NetworkID(context.Background())
+	// 设置Gas参数
+	gasLimit := uint64(21000) // Gas限制: 21000 (标准ETH转账)
+	// 获取链ID, EIP-1559 交易和 EIP-155 签名都需要
+	chainID, err := client.ChainID(ctx)
 	if err != nil {
-		log.Fatal(err)
+		return nil, fmt.Errorf("获取链ID失败: %w", errs.Classify(err))
 	}
-	// 使用EIP-155签名器签名交易
-	// types.SignTx 使用私钥对交易进行签名
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), privateKey)
-	if err != nil {
-		log.Fatal(err)
-	}
-	// 发送签名交易到网络
-	// SendTransaction 广播签名后的交易到以太坊网络
-	err = client.SendTransaction(context.Background(), signedTx)
+
+	// nonce 过低、手续费过低等错误需要重新获取 nonce 和手续费后重新构建交易
+	// Nonce 由共享的 nonce 管理器分配, 保证同一账户连续或并发发送时不冲突
+	signedTx, err := util.BuildAndSend(ctx, client, fromAddress, func(ctx context.Context, nonce uint64) (*types.Transaction, error) {
+		// 估算手续费: 默认 EIP-1559 动态费用交易, --legacy 时使用 gasPrice
+		fees, err := SuggestFees(ctx, client, CurrentFeeOptions())
+		if err != nil {
+			return nil, err
+		}
+		// 构建未签名交易, 签名器使用最新的签名规则, 同时支持 EIP-155 传统交易和 EIP-1559 交易
+		toAddress := common.HexToAddress(to)
+		return fees.NewTx(chainID, nonce, &toAddress, value, gasLimit, nil), nil
+	}, account.Sign(fromAddress, chainID))
 	if err != nil {
-		log.Fatal(err)
+		return nil, fmt.Errorf("发送交易失败: %w", err)
 	}
 
-	receipt, err := util.WaitTransactionReceipt(client, 10, signedTx.Hash())
+	receipt, err := util.WaitReceipt(ctx, client, signedTx.Hash())
 	if err != nil {
-		log.Fatal(err)
-	} else {
-		util.ShowReceipt(receipt)
+		return nil, err
 	}
+	return receipt, util.DiagnoseReceipt(ctx, client, receipt)
 }
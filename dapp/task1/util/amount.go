@@ -0,0 +1,101 @@
+package util
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+// units 金额单位对应的小数位数
+var units = map[string]int{
+	"wei":        0,
+	"kwei":       3,
+	"mwei":       6,
+	"gwei":       9,
+	"szabo":      12,
+	"microether": 12,
+	"finney":     15,
+	"milliether": 15,
+	"ether":      18,
+	"eth":        18,
+}
+
+// amountPattern 十进制数字加可选单位, 例如 1.5ether / 250 gwei / 0.001 / 1000wei
+var amountPattern = regexp.MustCompile(`^(\d*)(?:\.(\d*))?\s*([a-zA-Z]*)$`)
+
+// ParseAmount 将十进制金额字符串精确转换为 wei
+// 支持的单位: wei, kwei, mwei, gwei, szabo, finney, ether (eth), 不区分大小写;
+// 没有单位时使用 defaultUnit, 例如转账金额默认为 ether, 手续费默认为 gwei
+// 不接受负数、科学计数法、小于 1 wei 的精度和超出 uint256 的金额
+func ParseAmount(value, defaultUnit string) (*big.Int, error) {
+	value = strings.ReplaceAll(strings.TrimSpace(value), "_", "")
+	match := amountPattern.FindStringSubmatch(value)
+	if match == nil || match[1]+match[2] == "" {
+		return nil, fmt.Errorf("%w: 金额格式错误 %q, 示例: 1.5ether, 250gwei, 0.001, 1000wei", ErrInvalidArgument, value)
+	}
+	whole, frac, unit := match[1], match[2], strings.ToLower(match[3])
+	if unit == "" {
+		unit = strings.ToLower(defaultUnit)
+	}
+	decimals, ok := units[unit]
+	if !ok {
+		return nil, fmt.Errorf("%w: 未知的金额单位 %q, 可选: wei, kwei, mwei, gwei, szabo, finney, ether", ErrInvalidArgument, unit)
+	}
+
+	wei, ok := scaleDecimal(whole, frac, decimals)
+	if !ok {
+		return nil, fmt.Errorf("%w: 金额 %q 的精度超过 1 wei (%s 最多 %d 位小数)", ErrInvalidArgument, value, unit, decimals)
+	}
+	if wei.BitLen() > 256 {
+		return nil, fmt.Errorf("%w: 金额 %q 超出 uint256 的范围", ErrInvalidArgument, value)
+	}
+	return wei, nil
+}
+
+// ParseUnits 将十进制代币金额按代币的小数位数精确转换为最小单位, 例如 decimals 为 6 时 1.5 转换为 1500000
+// 不接受单位、负数、科学计数法、超过 decimals 的精度和超出 uint256 的金额
+func ParseUnits(value string, decimals int) (*big.Int, error) {
+	value = strings.ReplaceAll(strings.TrimSpace(value), "_", "")
+	match := amountPattern.FindStringSubmatch(value)
+	if match == nil || match[1]+match[2] == "" || match[3] != "" {
+		return nil, fmt.Errorf("%w: 代币金额格式错误 %q, 示例: 100, 1.5", ErrInvalidArgument, value)
+	}
+	amount, ok := scaleDecimal(match[1], match[2], decimals)
+	if !ok {
+		return nil, fmt.Errorf("%w: 代币金额 %q 的精度超过代币的 %d 位小数", ErrInvalidArgument, value, decimals)
+	}
+	if amount.BitLen() > 256 {
+		return nil, fmt.Errorf("%w: 代币金额 %q 超出 uint256 的范围", ErrInvalidArgument, value)
+	}
+	return amount, nil
+}
+
+// scaleDecimal 将整数部分和小数部分乘以 10^decimals, 超出精度的小数位只能是 0, 否则会丢失精度
+func scaleDecimal(whole, frac string, decimals int) (*big.Int, bool) {
+	if len(frac) > decimals {
+		if strings.Trim(frac[decimals:], "0") != "" {
+			return nil, false
+		}
+		frac = frac[:decimals]
+	}
+	digits := strings.TrimLeft(whole+frac+strings.Repeat("0", decimals-len(frac)), "0")
+	if digits == "" {
+		return new(big.Int), true
+	}
+	// amountPattern 只匹配数字, 不会解析失败
+	n, _ := new(big.Int).SetString(digits, 10)
+	return n, true
+}
+
+// FormatUnits 将 wei 精确转换为指定小数位数的十进制字符串, 去掉末尾多余的 0
+func FormatUnits(wei *big.Int, decimals int) string {
+	if wei == nil {
+		return "0"
+	}
+	s := new(big.Rat).SetFrac(wei, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)).FloatString(decimals)
+	if strings.Contains(s, ".") {
+		s = strings.TrimSuffix(strings.TrimRight(s, "0"), ".")
+	}
+	return s
+}
@@ -0,0 +1,101 @@
+package util
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestParseAmount(t *testing.T) {
+	maxUint256 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+	tests := []struct {
+		value string
+		unit  string // 默认单位
+		want  string // 为空表示预期解析失败
+	}{
+		{"1.5ether", "ether", "1500000000000000000"},
+		{"1.5 ETH", "gwei", "1500000000000000000"},
+		{"250gwei", "ether", "250000000000"},
+		{"250", "gwei", "250000000000"},
+		{"0.001", "ether", "1000000000000000"},
+		{".5", "ether", "500000000000000000"},
+		{"1.", "ether", "1000000000000000000"},
+		{"1_000wei", "ether", "1000"},
+		{"1.0wei", "ether", "1"},
+		{"0", "ether", "0"},
+		{"1.000000000000000001ether", "ether", "1000000000000000001"},
+		{"1.5gwei", "ether", "1500000000"},
+		{maxUint256.String() + "wei", "ether", maxUint256.String()},
+		{"1.0000000000000000001ether", "ether", ""}, // 精度超过 1 wei
+		{"1.5wei", "ether", ""},
+		{"0.0000000001gwei", "ether", ""},
+		{new(big.Int).Add(maxUint256, big.NewInt(1)).String() + "wei", "ether", ""}, // 超出 uint256
+		{"1" + strings.Repeat("0", 60) + "ether", "ether", ""},
+		{"-1ether", "ether", ""},
+		{"1e18", "wei", ""},
+		{"1.5btc", "ether", ""},
+		{"1.5", "btc", ""},
+		{"ether", "ether", ""},
+		{".", "ether", ""},
+		{"", "ether", ""},
+	}
+	for _, tt := range tests {
+		got, err := ParseAmount(tt.value, tt.unit)
+		if tt.want == "" {
+			if !errors.Is(err, ErrInvalidArgument) {
+				t.Errorf("ParseAmount(%q, %q) = %v, %v, 预期 %v", tt.value, tt.unit, got, err, ErrInvalidArgument)
+			}
+			continue
+		}
+		if err != nil || got.String() != tt.want {
+			t.Errorf("ParseAmount(%q, %q) = %v, %v, 预期 %s", tt.value, tt.unit, got, err, tt.want)
+		}
+	}
+}
+
+func TestParseUnits(t *testing.T) {
+	tests := []struct {
+		value    string
+		decimals int
+		want     string // 为空表示预期解析失败
+	}{
+		{"1.5", 6, "1500000"},
+		{"100", 6, "100000000"},
+		{"0.000001", 6, "1"},
+		{"1.0000000", 6, "1000000"},
+		{"0.0000001", 6, ""},
+		{"1.5usdc", 6, ""},
+		{"1" + strings.Repeat("0", 80), 0, ""},
+	}
+	for _, tt := range tests {
+		got, err := ParseUnits(tt.value, tt.decimals)
+		if tt.want == "" {
+			if !errors.Is(err, ErrInvalidArgument) {
+				t.Errorf("ParseUnits(%q, %d) = %v, %v, 预期 %v", tt.value, tt.decimals, got, err, ErrInvalidArgument)
+			}
+			continue
+		}
+		if err != nil || got.String() != tt.want {
+			t.Errorf("ParseUnits(%q, %d) = %v, %v, 预期 %s", tt.value, tt.decimals, got, err, tt.want)
+		}
+	}
+}
+
+func TestFormatUnits(t *testing.T) {
+	for _, tt := range []struct {
+		wei      *big.Int
+		decimals int
+		want     string
+	}{
+		{big.NewInt(1500000000000000000), 18, "1.5"},
+		{big.NewInt(1), 18, "0.000000000000000001"},
+		{big.NewInt(250000000000), 9, "250"},
+		{big.NewInt(0), 18, "0"},
+		{nil, 18, "0"},
+	} {
+		if got := FormatUnits(tt.wei, tt.decimals); got != tt.want {
+			t.Errorf("FormatUnits(%v, %d) = %s, 预期 %s", tt.wei, tt.decimals, got, tt.want)
+		}
+	}
+}
@@ -1,18 +1,18 @@
 package util
 
 import (
+	"bytes"
 	"context"
-	"encoding/hex"
+	"crypto/ecdsa"
 	"fmt"
-	"log"
 	"net/http"
-	"net/url"
 	"strings"
+	"task1/errs"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/gorilla/websocket"
 )
@@ -21,150 +21,177 @@ const (
 	EMPTY_ADDRESS = "0x0000000000000000000000000000000000000000"
 )
 
-func loadProxyClient() *http.Client {
-	// 1. 设置代理服务器地址
-	proxyUrl, _ := url.Parse("socks5://127.0.0.1:7897")
-	// 2. 创建一个自定义的 HTTP Transport，配置代理
-	customTransport := &http.Transport{
-		Proxy: http.ProxyURL(proxyUrl),
-		// 可根据需要配置其他参数，如DialContext、TLS握手超时等
-	}
-	// 3. 使用自定义的 Transport 创建 HTTP 客户端
-	httpClient := &http.Client{
-		Transport: customTransport,
+func loadProxyClient() (*http.Client, error) {
+	// 按 --proxy / 环境文件 / 系统环境变量 加载代理配置, 未配置时直连
+	proxyConfig, err := LoadProxyConfig()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrConfig, err)
 	}
-	return httpClient
+	return proxyConfig.HTTPClient(), nil
 }
 
-func LoadClient() *ethclient.Client {
-	return loadClientBase(LoadEnv("https://sepolia.infura.io/v3/<API_KEY>"), rpc.WithHTTPClient(loadProxyClient()))
+// LoadClient 使用当前网络的 HTTP 端点创建连接池
+func LoadClient(ctx context.Context) (*Pool, error) {
+	return loadClientBase(ctx, false)
 }
 
-func LoadClientWs() *ethclient.Client {
-	// return loadClientBase(LoadEnv("wss://sepolia.infura.io/ws/v3/<API_KEY>"), rpc.WithWebsocketDialer(loadClientWithWs()))
-	// wss://ethereum-sepolia-rpc.publicnode.com 上面那个官方地址有问题，查询出来的区块信息异常在链上查不到，得用下面这个
-	return loadClientBase(LoadEnv("wss://ethereum-sepolia-rpc.publicnode.com/<API_KEY>"), rpc.WithWebsocketDialer(loadClientWithWs()))
+// LoadClientWs 使用当前网络的 WebSocket 端点创建连接池
+func LoadClientWs(ctx context.Context) (*Pool, error) {
+	return loadClientBase(ctx, true)
 }
 
-func loadClientWithWs() websocket.Dialer {
-	proxyUrl, _ := url.Parse("socks5://127.0.0.1:7897")
-	websocket.DefaultDialer.Proxy = http.ProxyURL(proxyUrl)
-	return *websocket.DefaultDialer
+func loadClientWithWs() (websocket.Dialer, error) {
+	proxyConfig, err := LoadProxyConfig()
+	if err != nil {
+		return websocket.Dialer{}, fmt.Errorf("%w: %w", ErrConfig, err)
+	}
+	return proxyConfig.WebsocketDialer(), nil
 }
 
-func loadClientBase(url string, opt ...rpc.ClientOption) *ethclient.Client {
+func loadClientBase(ctx context.Context, ws bool) (*Pool, error) {
 	// 通过infura连接到以太坊网络，构建连接client
 	// API_KEY是在infura申请获得的，小狐狸钱包本身就是申请的infura所以可以查询到对应API_KEY
-	// 4. 创建可配置的 RPC 客户端
-	rpcClient, err := rpc.DialOptions(
-		context.Background(),
-		url,
-		opt...,
-	)
+	network, err := CurrentNetwork()
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
-	// 5. 将 RPC 客户端包装为以太坊客户端
-	return ethclient.NewClient(rpcClient)
+	return NewNetworkPool(ctx, network, ws)
 }
 
-// WaitTransactionReceipt 获取交易收据，支持重试机制
+// NewNetworkPool 使用网络配置中的全部端点创建连接池
+// ws 为 true 时使用 WebSocket 端点, 否则使用 HTTP 端点, 两者都会应用代理配置
+// 连接池会校验每个端点的链ID、区块新鲜度和延迟, 调用失败时自动切换端点
+func NewNetworkPool(ctx context.Context, network *Network, ws bool) (*Pool, error) {
+	if ws {
+		dialer, err := loadClientWithWs()
+		if err != nil {
+			return nil, err
+		}
+		return NewPool(ctx, network.Name, network.WSURLs, DefaultPoolConfig(network), rpc.WithWebsocketDialer(dialer))
+	}
+	httpClient, err := loadProxyClient()
+	if err != nil {
+		return nil, err
+	}
+	return NewPool(ctx, network.Name, network.HTTPURLs, DefaultPoolConfig(network), rpc.WithHTTPClient(httpClient))
+}
+
+// LoadPrivateKey 从环境文件的 PRIVATE_KEY 加载签名私钥
+func LoadPrivateKey() (*ecdsa.PrivateKey, error) {
+	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(LoadEnv("<PRIVATE_KEY>"), "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("%w: 私钥解析失败: %w", ErrConfig, err)
+	}
+	return privateKey, nil
+}
+
+// WaitTransactionReceipt 以轮询方式等待交易被打包, 最多等待 maxRetries 个 5 秒
+// ctx: 取消或超时后停止等待
 // client: 以太坊客户端
-// maxRetries: 最大重试次数
+// maxRetries: 最大轮询次数
 // txHash: 交易哈希
 // 返回值: 交易收据和错误信息
-func WaitTransactionReceipt(client *ethclient.Client, maxRetries int, txHash common.Hash) (*types.Receipt, error) {
+//
+// 需要确认数、订阅新区块或同时等待多笔交易时使用 WaitReceipt / NewWaiter
+func WaitTransactionReceipt(ctx context.Context, client Client, maxRetries int, txHash common.Hash) (*types.Receipt, error) {
 	if maxRetries <= 0 {
-		return nil, fmt.Errorf("maxRetries must be greater than 0")
-	}
-
-	if client == nil {
-		return nil, fmt.Errorf("ethclient cannot be nil")
+		return nil, fmt.Errorf("%w: maxRetries must be greater than 0", ErrInvalidArgument)
 	}
+	pollInterval := 5 * time.Second
+	return NewWaiter(client, WaitConfig{
+		Confirmations: 1,
+		Timeout:       time.Duration(maxRetries) * pollInterval,
+		PollInterval:  pollInterval,
+	}).Wait(ctx, txHash)
+}
 
-	timeLimit := 5
-
-	ticker := time.NewTicker(time.Duration(timeLimit) * time.Second)
-	defer ticker.Stop()
-
-	log.Printf("开始监听交易: %s, 最大重试次数: %d", txHash.Hex(), maxRetries)
-
-	for attempt := 1; attempt <= maxRetries; attempt++ {
-		<-ticker.C
-
-		// 检查交易状态
-		_, isPending, err := client.TransactionByHash(context.Background(), txHash)
-		if err != nil {
-			// 如果是网络错误，继续重试
-			if isNetworkError(err) {
-				log.Printf("第 %d 次尝试: 网络错误, 交易: %s, 错误: %v", attempt, txHash.Hex(), err)
-				continue
+// SendTransaction 广播已签名的交易
+// 网络错误和限流时按 errs 的重试策略原样重发同一笔交易(交易哈希不变, 重复发送是安全的),
+// 节点返回交易已存在时视为成功; 重发后返回 nonce 过低时检查交易是否已经上链
+func SendTransaction(ctx context.Context, client Client, tx *types.Transaction) error {
+	resent := false
+	return errs.Retry(ctx, func(attempt int) error {
+		err := client.SendTransaction(ctx, tx)
+		if err == nil {
+			return nil
+		}
+		switch kind := errs.KindOf(err); {
+		case kind == errs.ErrAlreadyKnown:
+			Logf("交易 %s 已在节点交易池中", tx.Hash().Hex())
+			return nil
+		case kind == errs.ErrNonceTooLow && resent:
+			// 前一次发送可能已经成功, 只是响应丢失
+			if _, _, lookupErr := client.TransactionByHash(ctx, tx.Hash()); lookupErr == nil {
+				return nil
 			}
-			return nil, fmt.Errorf("获取交易状态失败: %w", err)
+		case kind.Policy().Retryable:
+			resent = true
+			Logf("第 %d 次发送交易 %s 失败, 准备重试: %v", attempt, tx.Hash().Hex(), errs.Classify(err))
+			return err
 		}
+		// 需要重新构建交易的错误(如 nonce 过低)交给调用方处理
+		return errs.Permanent(err)
+	})
+}
 
-		if isPending {
-			log.Printf("第 %d 次尝试: 交易 %s 仍在处理中...", attempt, txHash.Hex())
-			continue
-		}
+// SignFunc 对构建好的未签名交易签名
+type SignFunc func(ctx context.Context, tx *types.Transaction) (*types.Transaction, error)
 
-		// 交易已确认，获取收据
-		receipt, err := client.TransactionReceipt(context.Background(), txHash)
+// BuildAndSend 从共享的 nonce 管理器为 from 分配 nonce, 构建、签名并广播交易
+// build 使用传入的 nonce 构建未签名的交易, 每次调用都应重新获取 gas 价格; sign 对交易签名; 节点返回 nonce 过低、
+// gas 价格过低等需要重新构建交易的错误时释放 nonce 并按 errs 的重试策略重新分配后再次构建和签名, 返回最终发送成功的交易
+// --dry-run 模式下只模拟执行第一次构建的未签名交易, 不调用 sign (不会提示输入密码或请求签名服务审批), 模拟成功时返回 ErrDryRun
+func BuildAndSend(ctx context.Context, client Client, from common.Address, build func(ctx context.Context, nonce uint64) (*types.Transaction, error), sign SignFunc) (*types.Transaction, error) {
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("获取链ID失败: %w", errs.Classify(err))
+	}
+	var tx *types.Transaction
+	err = errs.Retry(ctx, func(attempt int) error {
+		lease, err := Nonces().Acquire(ctx, client, chainID, from)
 		if err != nil {
-			return nil, fmt.Errorf("获取交易收据失败: %w", err)
+			return errs.Permanent(err)
 		}
-
-		log.Printf("交易 %s 已确认! 耗时: %d 秒", txHash.Hex(), attempt*timeLimit)
-		return receipt, nil
-	}
-
-	return nil, fmt.Errorf("交易 %s 在 %d 秒内未确认", txHash.Hex(), maxRetries)
-}
-
-func ShowReceipt(receipt *types.Receipt) {
-	log.Printf("交易: %s, 状态: %v\n", receipt.TxHash.Hex(), receipt.Status == 1)
-	log.Printf("区块哈希: %s\n", receipt.BlockHash.Hex())
-	log.Printf("区块号: %d\n", receipt.BlockNumber)
-	log.Printf("交易索引: %d\n", receipt.TransactionIndex)
-	if receipt.ContractAddress.Hex() != EMPTY_ADDRESS {
-		log.Println("部署的合约地址: ", receipt.ContractAddress.Hex())
-	}
-	log.Printf("logs(%d): \n %+v", len(receipt.Logs), receipt.Logs)
-	if receipt.Status != 1 && receipt.ContractAddress.Hex() == EMPTY_ADDRESS {
-		panic("交易失败")
-	}
-}
-
-// isNetworkError 判断是否为网络错误（可重试的错误）
-func isNetworkError(err error) bool {
-	if err == nil {
-		return false
+		tx, err = build(ctx, lease.Nonce)
+		if err != nil {
+			lease.Release(ctx)
+			return errs.Permanent(err)
+		}
+		if dryRun {
+			lease.Release(ctx)
+			return errs.Permanent(DryRunTx(ctx, client, from, tx))
+		}
+		if tx, err = sign(ctx, tx); err != nil {
+			lease.Release(ctx)
+			return errs.Permanent(err)
+		}
+		if err = SendTransaction(ctx, client, tx); err == nil {
+			lease.Sent(ctx, tx)
+			return nil
+		}
+		lease.Release(ctx)
+		if !errs.PolicyOf(err).Resync {
+			return errs.Permanent(err)
+		}
+		Logf("第 %d 次发送交易 %s (nonce %d) 失败, 重新构建交易: %v", attempt, tx.Hash().Hex(), lease.Nonce, err)
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
-	// 常见的网络错误类型
-	errorMsg := err.Error()
-	return strings.Contains(errorMsg, "connection") ||
-		strings.Contains(errorMsg, "timeout") ||
-		strings.Contains(errorMsg, "network") ||
-		strings.Contains(errorMsg, "EOF")
+	return tx, nil
 }
 
-// HexToASCII 将十六进制字符串转换为 ASCII 字符串
+// HexByteToASCII 将任意长度的字节转换为可读的 ASCII 字符串
+// 去掉 ABI 编码末尾补齐的 0, 不可打印的字节按 \xNN 转义, 不会 panic
 func HexByteToASCII(hexBytes []byte) string {
-	hexStr := common.BytesToHash(hexBytes).Hex()
-	// 清理字符串：去除空格和前缀
-	hexStr = strings.ReplaceAll(hexStr, " ", "")
-	hexStr = strings.TrimPrefix(hexStr, "0x")
-	hexStr = strings.TrimPrefix(hexStr, "0X")
-
-	// 检查长度是否为偶数
-	if len(hexStr)%2 != 0 {
-		panic(fmt.Errorf("hex string length must be even"))
-	}
-
-	decoded, err := hex.DecodeString(hexStr)
-	if err != nil {
-		panic(err)
+	var b strings.Builder
+	for _, c := range bytes.TrimRight(hexBytes, "\x00") {
+		if c >= 0x20 && c < 0x7f {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "\\x%02x", c)
+		}
 	}
-
-	return string(decoded)
+	return b.String()
 }
@@ -5,9 +5,12 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"log"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 	"github.com/valyala/fasttemplate"
@@ -40,9 +43,9 @@ func InitConfig(customEnvFile string) error {
 	err := viper.ReadInConfig()
 	if err != nil {
 		if errors.As(err, &viper.ConfigFileNotFoundError{}) {
-			log.Printf("配置文件未找到, 请在命令运行目录添加: .env 文件, 文件格式模板如下:\n\n```.env\n%s\n```\n\n", ENV_TEMPLATE)
+			Logf("配置文件未找到, 请在命令运行目录添加: .env 文件, 文件格式模板如下:\n\n```.env\n%s\n```\n\n", ENV_TEMPLATE)
 		}
-		return fmt.Errorf("fatal error config file: %w", err)
+		return fmt.Errorf("%w: 读取配置文件失败: %w", ErrConfig, err)
 	}
 
 	// 实时监听修改, 并更新配置
@@ -62,7 +65,7 @@ func ReloadConfig() error {
 
 	err := viper.ReadInConfig()
 	if err != nil {
-		return fmt.Errorf("重新加载配置文件失败: %w", err)
+		return fmt.Errorf("%w: 重新加载配置文件失败: %w", ErrConfig, err)
 	}
 	return nil
 }
@@ -91,13 +94,6 @@ func ValidateEnvFile(filePath string) error {
 	return nil
 }
 
-func init() {
-	// 保持向后兼容性，使用默认配置初始化
-	if err := InitConfig(""); err != nil {
-		panic(err)
-	}
-}
-
 func LoadEnv(in string) (out string) {
 	t := fasttemplate.New(in, "<", ">")
 	out = t.ExecuteFuncString(func(w io.Writer, tag string) (int, error) {
@@ -112,7 +108,7 @@ func LoadEnv(in string) (out string) {
 func findFileDirFrom(maxLevel int, file string) string {
 	dir, err := os.Getwd()
 	if err != nil {
-		log.Printf("获取当前工作目录失败: %+v\n", err)
+		Logf("获取当前工作目录失败: %+v\n", err)
 		return ""
 	}
 
@@ -124,13 +120,13 @@ func findFileDirFrom(maxLevel int, file string) string {
 		if _, err := os.Stat(envPath); err == nil {
 			return dir
 		} else if !os.IsNotExist(err) {
-			log.Printf("检查.env文件失败: %+v\n", err)
+			Logf("检查.env文件失败: %+v\n", err)
 			return ""
 		}
 
 		// 检查是否达到最大搜索层数
 		if maxLevel > 0 && level >= maxLevel {
-			log.Printf("在%d层目录内未找到.env文件", maxLevel)
+			Logf("在%d层目录内未找到.env文件", maxLevel)
 			return ""
 		}
 
@@ -139,7 +135,7 @@ func findFileDirFrom(maxLevel int, file string) string {
 
 		// 如果已经到达根目录，停止遍历
 		if parentDir == dir {
-			log.Printf("在目录路径中未找到.env文件\n")
+			Logf("在目录路径中未找到.env文件\n")
 			return ""
 		}
 		dir = parentDir
@@ -147,6 +143,19 @@ func findFileDirFrom(maxLevel int, file string) string {
 	}
 }
 
+// ExpandHome 将路径开头的 ~ 或 ~/ 展开为当前用户的主目录
+// 只处理前缀, 路径中间的 ~ 和 ~user 形式保持不变
+func ExpandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") && !strings.HasPrefix(path, "~"+string(filepath.Separator)) {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[1:])
+}
+
 // GenerateEnvTemplate 生成环境变量模板文件
 func GenerateEnvTemplate(outputPath string) error {
 	// 如果未指定输出路径，使用当前目录下的 .env.template
@@ -174,3 +183,207 @@ func GenerateEnvTemplate(outputPath string) error {
 
 	return nil
 }
+
+// Network 网络配置档案
+type Network struct {
+	Name     string   // 网络名称, 通过 --network 选择
+	HTTPURLs []string // HTTP RPC 端点, 支持 <API_KEY> 等环境变量占位符
+	WSURLs   []string // WebSocket RPC 端点, 支持 <API_KEY> 等环境变量占位符
+	ChainID  uint64   // 期望的链ID, 0 表示不校验
+	Explorer string   // 区块浏览器地址, 为空表示没有浏览器
+	Symbol   string   // 原生代币符号
+	Decimals uint8    // 原生代币小数位数
+	// MaxHeadAge 健康检查时最新区块允许的最大时间间隔, 0 表示不检查
+	// 本地节点 (local, mainnet-fork) 没有交易时不出块, 因此不检查
+	MaxHeadAge time.Duration
+}
+
+// DEFAULT_NETWORK 未通过 --network 或 NETWORK 环境变量指定时使用的网络
+const DEFAULT_NETWORK = "sepolia"
+
+// builtinNetworks 内置的网络配置档案
+var builtinNetworks = map[string]Network{
+	"mainnet": {
+		Name:       "mainnet",
+		HTTPURLs:   []string{"https://mainnet.infura.io/v3/<API_KEY>"},
+		WSURLs:     []string{"wss://mainnet.infura.io/ws/v3/<API_KEY>"},
+		ChainID:    1,
+		Explorer:   "https://etherscan.io",
+		Symbol:     "ETH",
+		Decimals:   18,
+		MaxHeadAge: 2 * time.Minute,
+	},
+	"mainnet-fork": {
+		Name:     "mainnet-fork",
+		HTTPURLs: []string{"http://127.0.0.1:8545"},
+		WSURLs:   []string{"ws://127.0.0.1:8545"},
+		ChainID:  1,
+		Symbol:   "ETH",
+		Decimals: 18,
+	},
+	"sepolia": {
+		Name:     "sepolia",
+		HTTPURLs: []string{"https://sepolia.infura.io/v3/<API_KEY>"},
+		// wss://sepolia.infura.io/ws/v3/<API_KEY> 这个官方地址有问题，查询出来的区块信息异常在链上查不到，得用下面这个
+		WSURLs:     []string{"wss://ethereum-sepolia-rpc.publicnode.com/<API_KEY>"},
+		ChainID:    11155111,
+		Explorer:   "https://sepolia.etherscan.io",
+		Symbol:     "ETH",
+		Decimals:   18,
+		MaxHeadAge: 2 * time.Minute,
+	},
+	"holesky": {
+		Name:       "holesky",
+		HTTPURLs:   []string{"https://holesky.infura.io/v3/<API_KEY>"},
+		WSURLs:     []string{"wss://ethereum-holesky-rpc.publicnode.com"},
+		ChainID:    17000,
+		Explorer:   "https://holesky.etherscan.io",
+		Symbol:     "ETH",
+		Decimals:   18,
+		MaxHeadAge: 2 * time.Minute,
+	},
+	"local": {
+		Name:     "local",
+		HTTPURLs: []string{"http://127.0.0.1:8545"},
+		WSURLs:   []string{"ws://127.0.0.1:8545"},
+		ChainID:  31337,
+		Symbol:   "ETH",
+		Decimals: 18,
+	},
+}
+
+// currentNetwork 当前选中的网络, 为空时在首次使用时按默认规则解析
+var currentNetwork *Network
+
+// NetworkNames 返回所有内置网络名称(已排序)
+func NetworkNames() []string {
+	names := make([]string, 0, len(builtinNetworks))
+	for name := range builtinNetworks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetNetwork 选择当前使用的网络
+// name 为空时依次使用环境变量 NETWORK 和 DEFAULT_NETWORK
+func SetNetwork(name string) error {
+	network, err := LookupNetwork(name)
+	if err != nil {
+		return err
+	}
+	currentNetwork = network
+	return nil
+}
+
+// CurrentNetwork 返回当前选中的网络, 未选择时按默认规则解析
+func CurrentNetwork() (*Network, error) {
+	if currentNetwork == nil {
+		if err := SetNetwork(""); err != nil {
+			return nil, err
+		}
+	}
+	return currentNetwork, nil
+}
+
+// LookupNetwork 根据名称查找网络配置并应用环境变量覆盖
+// 环境变量以网络名称大写(- 替换为 _)为前缀, 例如 sepolia 网络:
+//
+//	SEPOLIA_HTTP_URLS - 逗号分隔的 HTTP 端点
+//	SEPOLIA_WS_URLS   - 逗号分隔的 WebSocket 端点
+//	SEPOLIA_CHAIN_ID  - 期望的链ID
+//	SEPOLIA_EXPLORER  - 区块浏览器地址
+//
+//	SEPOLIA_MAX_HEAD_AGE - 最新区块最大间隔, 例如 2m, 0 表示不检查
+//
+// 未内置的网络只要配置了 <NAME>_HTTP_URLS 也可以使用
+func LookupNetwork(name string) (*Network, error) {
+	if name == "" {
+		name = viper.GetString("NETWORK")
+	}
+	if name == "" {
+		name = DEFAULT_NETWORK
+	}
+	name = strings.ToLower(name)
+	prefix := strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+
+	network, ok := builtinNetworks[name]
+	if !ok {
+		network = Network{Name: name, Symbol: "ETH", Decimals: 18}
+	}
+	// 复制切片, 避免环境变量覆盖修改内置配置
+	network.HTTPURLs = append([]string(nil), network.HTTPURLs...)
+	network.WSURLs = append([]string(nil), network.WSURLs...)
+
+	if urls := splitList(viper.GetString(prefix + "HTTP_URLS")); len(urls) > 0 {
+		network.HTTPURLs = urls
+	}
+	if urls := splitList(viper.GetString(prefix + "WS_URLS")); len(urls) > 0 {
+		network.WSURLs = urls
+	}
+	if chainID := viper.GetString(prefix + "CHAIN_ID"); chainID != "" {
+		id, err := strconv.ParseUint(chainID, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: 网络 %s 的链ID配置无效 %q: %w", ErrConfig, name, chainID, err)
+		}
+		network.ChainID = id
+	}
+	if explorer := viper.GetString(prefix + "EXPLORER"); explorer != "" {
+		network.Explorer = explorer
+	}
+	if maxHeadAge := viper.GetString(prefix + "MAX_HEAD_AGE"); maxHeadAge != "" {
+		age, err := time.ParseDuration(maxHeadAge)
+		if err != nil {
+			return nil, fmt.Errorf("%w: 网络 %s 的区块间隔配置无效 %q: %w", ErrConfig, name, maxHeadAge, err)
+		}
+		network.MaxHeadAge = age
+	}
+
+	if len(network.HTTPURLs) == 0 && len(network.WSURLs) == 0 {
+		return nil, fmt.Errorf("%w: 未知网络: %s (可选: %s, 或在环境文件中配置 %sHTTP_URLS)", ErrConfig, name, strings.Join(NetworkNames(), ", "), prefix)
+	}
+	return &network, nil
+}
+
+// HTTPURL 返回第一个 HTTP 端点(已替换环境变量占位符)
+func (n *Network) HTTPURL() string {
+	if len(n.HTTPURLs) == 0 {
+		return ""
+	}
+	return LoadEnv(n.HTTPURLs[0])
+}
+
+// WSURL 返回第一个 WebSocket 端点(已替换环境变量占位符)
+func (n *Network) WSURL() string {
+	if len(n.WSURLs) == 0 {
+		return ""
+	}
+	return LoadEnv(n.WSURLs[0])
+}
+
+// TxURL 返回交易在区块浏览器中的地址, 没有浏览器时返回空字符串
+func (n *Network) TxURL(hash string) string {
+	if n.Explorer == "" {
+		return ""
+	}
+	return strings.TrimSuffix(n.Explorer, "/") + "/tx/" + hash
+}
+
+// AddressURL 返回地址在区块浏览器中的地址, 没有浏览器时返回空字符串
+func (n *Network) AddressURL(address string) string {
+	if n.Explorer == "" {
+		return ""
+	}
+	return strings.TrimSuffix(n.Explorer, "/") + "/address/" + address
+}
+
+// splitList 拆分逗号分隔的配置项, 忽略空白项
+func splitList(in string) []string {
+	var out []string
+	for _, item := range strings.Split(in, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
@@ -0,0 +1,96 @@
+package util
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// setConfig 设置环境文件中的配置, 测试结束后清除
+func setConfig(t *testing.T, config map[string]string) {
+	t.Helper()
+	for key, value := range config {
+		viper.Set(key, value)
+		t.Cleanup(func() { viper.Set(key, "") })
+	}
+}
+
+func TestLookupNetwork(t *testing.T) {
+	// builtin 返回修改后的内置配置副本
+	builtin := func(name string, modify func(*Network)) Network {
+		network := builtinNetworks[name]
+		network.HTTPURLs = append([]string(nil), network.HTTPURLs...)
+		network.WSURLs = append([]string(nil), network.WSURLs...)
+		if modify != nil {
+			modify(&network)
+		}
+		return network
+	}
+	tests := []struct {
+		name    string
+		network string
+		config  map[string]string
+		want    Network
+		err     error
+	}{
+		{name: "默认网络", want: builtin(DEFAULT_NETWORK, nil)},
+		{name: "NETWORK 配置", config: map[string]string{"NETWORK": "local"}, want: builtin("local", nil)},
+		{name: "名称不区分大小写", network: "Mainnet", config: map[string]string{"NETWORK": "local"}, want: builtin("mainnet", nil)},
+		{name: "覆盖内置网络", network: "sepolia", config: map[string]string{
+			"SEPOLIA_HTTP_URLS":    " https://a.example , ,https://b.example",
+			"SEPOLIA_WS_URLS":      "wss://ws.example",
+			"SEPOLIA_CHAIN_ID":     "11155112",
+			"SEPOLIA_EXPLORER":     "https://sepolia.otterscan.io",
+			"SEPOLIA_MAX_HEAD_AGE": "0",
+		}, want: builtin("sepolia", func(n *Network) {
+			n.HTTPURLs = []string{"https://a.example", "https://b.example"}
+			n.WSURLs = []string{"wss://ws.example"}
+			n.ChainID = 11155112
+			n.Explorer = "https://sepolia.otterscan.io"
+			n.MaxHeadAge = 0
+		})},
+		{name: "网络名称中的 - 替换为 _", network: "mainnet-fork", config: map[string]string{
+			"MAINNET_FORK_HTTP_URLS":    "http://127.0.0.1:8546",
+			"MAINNET_FORK_MAX_HEAD_AGE": "30s",
+			"MAINNET_HTTP_URLS":         "http://mainnet.example",
+		}, want: builtin("mainnet-fork", func(n *Network) {
+			n.HTTPURLs = []string{"http://127.0.0.1:8546"}
+			n.MaxHeadAge = 30 * time.Second
+		})},
+		{name: "自定义网络", network: "base-sepolia", config: map[string]string{
+			"BASE_SEPOLIA_HTTP_URLS": "https://sepolia.base.org",
+			"BASE_SEPOLIA_CHAIN_ID":  "84532",
+		}, want: Network{Name: "base-sepolia", HTTPURLs: []string{"https://sepolia.base.org"}, ChainID: 84532, Symbol: "ETH", Decimals: 18}},
+		{name: "只配置 WebSocket 的自定义网络", config: map[string]string{"NETWORK": "anvil", "ANVIL_WS_URLS": "ws://127.0.0.1:8545"},
+			want: Network{Name: "anvil", WSURLs: []string{"ws://127.0.0.1:8545"}, Symbol: "ETH", Decimals: 18}},
+		{name: "未配置端点的自定义网络", network: "base-sepolia", config: map[string]string{"BASE_SEPOLIA_CHAIN_ID": "84532"}, err: ErrConfig},
+		{name: "链ID无效", network: "local", config: map[string]string{"LOCAL_CHAIN_ID": "0x7a69"}, err: ErrConfig},
+		{name: "区块间隔无效", network: "local", config: map[string]string{"LOCAL_MAX_HEAD_AGE": "120"}, err: ErrConfig},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setConfig(t, tt.config)
+			network, err := LookupNetwork(tt.network)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("错误为 %v, 预期 %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(*network, tt.want) {
+				t.Fatalf("网络配置为 %+v, 预期 %+v", *network, tt.want)
+			}
+		})
+	}
+
+	// 环境变量覆盖不修改内置配置
+	if urls := builtinNetworks["sepolia"].HTTPURLs; len(urls) != 1 || urls[0] != "https://sepolia.infura.io/v3/<API_KEY>" {
+		t.Fatalf("内置配置被修改: %v", urls)
+	}
+}
@@ -0,0 +1,44 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// 错误分类, 调用方可以通过 errors.Is 判断错误类型
+// 连接池和一致性校验的错误见 ErrNoHealthyEndpoint / ErrQuorumUnavailable / QuorumError
+var (
+	ErrInvalidArgument = errors.New("参数错误")
+	ErrConfig          = errors.New("配置错误")
+	ErrTxFailed        = errors.New("交易执行失败")
+	ErrTimeout         = errors.New("等待超时")
+	ErrNotDeployed     = errors.New("合约未部署")
+)
+
+// logger 库内部的进度日志, 默认丢弃, 由命令行入口通过 SetLogger 开启
+var logger = log.New(io.Discard, "", 0)
+
+// SetLogger 设置进度日志输出, 传入 nil 表示关闭
+func SetLogger(l *log.Logger) {
+	if l == nil {
+		l = log.New(io.Discard, "", 0)
+	}
+	logger = l
+}
+
+// Logf 输出进度日志
+func Logf(format string, args ...any) {
+	logger.Printf(format, args...)
+}
+
+// CheckReceipt 检查交易收据状态, 执行失败时返回 ErrTxFailed
+func CheckReceipt(receipt *types.Receipt) error {
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return fmt.Errorf("%w: 交易 %s (区块 %d)", ErrTxFailed, receipt.TxHash.Hex(), receipt.BlockNumber)
+	}
+	return nil
+}
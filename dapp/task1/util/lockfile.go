@@ -0,0 +1,37 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+)
+
+// LockFile 通过操作系统的文件锁 (flock / LockFileEx) 实现跨进程互斥, 返回释放函数
+// 持有锁的进程崩溃时锁由操作系统自动释放, 不需要判断锁是否过期;
+// 锁文件释放后保留, 删除会使等待中的进程锁住已被删除的文件
+func LockFile(ctx context.Context, path string) (func(), error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		ok, err := tryLock(f)
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("锁定 %s 失败: %w", path, err)
+		}
+		if ok {
+			return func() {
+				unlock(f)
+				f.Close()
+			}, nil
+		}
+		select {
+		case <-ctx.Done():
+			f.Close()
+			return nil, ctx.Err()
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLockFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.lock")
+	unlock, err := LockFile(context.Background(), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 锁被持有时等待到 ctx 结束
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := LockFile(ctx, path); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("错误为 %v, 预期 %v", err, context.DeadlineExceeded)
+	}
+	unlock()
+	unlock2, err := LockFile(context.Background(), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unlock2()
+}
+
+// TestLockFileExclusive 并发地在锁内读取、修改和写回计数, 不会丢失更新
+func TestLockFileExclusive(t *testing.T) {
+	dir := t.TempDir()
+	counter := filepath.Join(dir, "counter")
+	const n = 20
+	var wg sync.WaitGroup
+	for range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			unlock, err := LockFile(context.Background(), filepath.Join(dir, "counter.lock"))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			defer unlock()
+			data, _ := os.ReadFile(counter)
+			count, _ := strconv.Atoi(string(data))
+			time.Sleep(time.Millisecond)
+			if err := os.WriteFile(counter, []byte(strconv.Itoa(count+1)), 0600); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if data, _ := os.ReadFile(counter); string(data) != strconv.Itoa(n) {
+		t.Fatalf("计数为 %s, 预期 %d", data, n)
+	}
+}
@@ -0,0 +1,22 @@
+//go:build unix
+
+package util
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// tryLock 以非阻塞方式获取排他锁, 锁被其他进程持有时返回 false
+func tryLock(f *os.File) (bool, error) {
+	err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+	if errors.Is(err, syscall.EWOULDBLOCK) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func unlock(f *os.File) {
+	syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+}
@@ -0,0 +1,359 @@
+package util
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"task1/errs"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	// nonceReserveTTL 已分配但未发送的 nonce 的保留时间, 超时视为发送方已退出
+	nonceReserveTTL = 2 * time.Minute
+	// nonceBuiltTTL 离线构建的交易在离线签名、广播之前保留 nonce 的时间
+	nonceBuiltTTL = 24 * time.Hour
+	// nonceSentGrace 刚发送的交易可能还没有同步到全部端点, 这段时间内不检查交易是否存在
+	nonceSentGrace = time.Minute
+)
+
+// nonceRecord 已分配的 nonce, Hash 为空表示已分配但尚未发送, Built 表示已离线构建等待签名和广播
+type nonceRecord struct {
+	Hash  common.Hash `json:"hash,omitempty"`
+	Built bool        `json:"built,omitempty"`
+	Time  time.Time   `json:"time"`
+}
+
+// nonceAccount 单个 (链, 地址) 已分配且尚未被节点 pending nonce 覆盖的 nonce
+type nonceAccount map[uint64]nonceRecord
+
+// NonceManager 按 (链ID, 地址) 分配 nonce
+//
+// 每次分配都会以节点的 pending nonce 为起点, 跳过本进程或其他进程(启用持久化时)已分配且仍有效的 nonce:
+//   - 已分配未发送的 nonce 保留 2 分钟, 离线构建的交易保留 24 小时
+//   - 已发送的 nonce 在交易仍能从节点查到时保留
+//
+// 发送失败的 nonce 会被释放, 被节点丢弃的交易留下的空洞会在下次分配时优先填补
+type NonceManager struct {
+	mu       sync.Mutex
+	path     string // 持久化文件, 为空表示只保存在内存中
+	accounts map[string]nonceAccount
+}
+
+// NewNonceManager 创建 nonce 管理器, path 为空时不持久化
+// 持久化文件可以被多个进程共享, 通过锁文件串行访问
+func NewNonceManager(path string) (*NonceManager, error) {
+	m := &NonceManager{path: path, accounts: make(map[string]nonceAccount)}
+	if path == "" {
+		return m, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("%w: 创建 nonce 存储目录失败: %w", ErrConfig, err)
+	}
+	if err := m.load(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrConfig, err)
+	}
+	return m, nil
+}
+
+// nonces 所有发送路径共享的 nonce 管理器
+var nonces, _ = NewNonceManager("")
+
+// SetNonceStore 设置共享 nonce 管理器的持久化文件, 为空表示只保存在内存中
+func SetNonceStore(path string) error {
+	m, err := NewNonceManager(path)
+	if err != nil {
+		return err
+	}
+	nonces = m
+	return nil
+}
+
+// Persistent 判断是否启用了持久化, 未启用时分配的 nonce 只在当前进程内有效
+func (m *NonceManager) Persistent() bool {
+	return m.path != ""
+}
+
+// Nonces 返回所有发送路径共享的 nonce 管理器
+func Nonces() *NonceManager {
+	return nonces
+}
+
+// NonceLease 已分配的 nonce, 发送成功后调用 Sent, 失败时调用 Release
+type NonceLease struct {
+	Nonce   uint64
+	manager *NonceManager
+	key     string
+}
+
+func nonceKey(chainID *big.Int, address common.Address) string {
+	return chainID.String() + ":" + address.Hex()
+}
+
+// Acquire 为 address 分配下一个可用的 nonce
+//
+// 查询节点中的交易可能较慢, 不能在持有持久化文件锁时进行 (会阻塞其他进程分配 nonce):
+// 需要查询时先释放锁, 查询后重新加锁, 按查询结果重新分配
+func (m *NonceManager) Acquire(ctx context.Context, client Client, chainID *big.Int, address common.Address) (*NonceLease, error) {
+	pending, err := client.PendingNonceAt(ctx, address)
+	if err != nil {
+		return nil, fmt.Errorf("获取 nonce 失败: %w", errs.Classify(err))
+	}
+	key := nonceKey(chainID, address)
+
+	checked := make(map[uint64]nonceCheck)
+	for round := 0; ; round++ {
+		var nonce uint64
+		var unchecked map[uint64]common.Hash
+		err := m.update(ctx, func() error {
+			nonce, unchecked = m.allocate(key, address, pending, checked, round < nonceCheckRounds)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(unchecked) > 0 {
+			for n, hash := range unchecked {
+				checked[n] = nonceCheck{hash: hash, live: txLive(ctx, client, n, hash)}
+			}
+			continue
+		}
+		if nonce > pending {
+			Logf("节点 pending nonce 为 %d, 跳过已分配的 nonce, 使用 %d", pending, nonce)
+		}
+		return &NonceLease{Nonce: nonce, manager: m, key: key}, nil
+	}
+}
+
+// nonceCheckRounds 分配时最多查询节点的轮数, 超过后未查询的交易保守地视为仍被占用
+const nonceCheckRounds = 3
+
+// nonceCheck 在锁外查询的交易是否仍在节点中
+type nonceCheck struct {
+	hash common.Hash
+	live bool
+}
+
+// allocate 在锁内从 pending 开始分配第一个空闲的 nonce
+// 已发送的交易需要查询节点才能确定是否仍被占用, 没有查询结果且 check 为 true 时不分配, 返回需要查询的交易
+func (m *NonceManager) allocate(key string, address common.Address, pending uint64, checked map[uint64]nonceCheck, check bool) (uint64, map[uint64]common.Hash) {
+	account := m.account(key)
+	// 低于 pending nonce 的交易已经被节点接收, 不再需要跟踪
+	for n := range account {
+		if n < pending {
+			delete(account, n)
+		}
+	}
+	unchecked := make(map[uint64]common.Hash)
+	nonce := pending
+	for ; ; nonce++ {
+		record, ok := account[nonce]
+		if !ok {
+			break
+		}
+		live, known := recordLive(nonce, record)
+		if !known {
+			if result, ok := checked[nonce]; ok && result.hash == record.Hash {
+				live = result.live
+			} else {
+				unchecked[nonce] = record.Hash
+				live = true
+			}
+		}
+		if !live {
+			break
+		}
+	}
+	if check && len(unchecked) > 0 {
+		return 0, unchecked
+	}
+	if len(account) > 0 && nonce < maxNonce(account) {
+		Logf("检测到 nonce 空洞: %s 的 nonce %d 未被使用 (已分配到 %d), 优先填补", address.Hex(), nonce, maxNonce(account))
+	}
+	account[nonce] = nonceRecord{Time: time.Now()}
+	return nonce, nil
+}
+
+// recordLive 不查询节点判断已分配的 nonce 是否仍被占用, known 为 false 表示需要查询交易是否仍在节点中
+func recordLive(nonce uint64, record nonceRecord) (live, known bool) {
+	if record.Hash == (common.Hash{}) {
+		ttl := nonceReserveTTL
+		if record.Built {
+			ttl = nonceBuiltTTL
+		}
+		if time.Since(record.Time) < ttl {
+			return true, true
+		}
+		Logf("nonce %d 分配后超过 %s 未发送, 重新使用", nonce, ttl)
+		return false, true
+	}
+	if time.Since(record.Time) < nonceSentGrace {
+		return true, true
+	}
+	return false, false
+}
+
+// txLive 查询已发送的交易是否仍在节点中
+func txLive(ctx context.Context, client Client, nonce uint64, hash common.Hash) bool {
+	_, _, err := client.TransactionByHash(ctx, hash)
+	if errors.Is(err, ethereum.NotFound) {
+		Logf("nonce %d 的交易 %s 已不在节点中, 重新使用", nonce, hash.Hex())
+		return false
+	}
+	// 其他错误无法确定交易状态, 保守地视为仍被占用
+	return true
+}
+
+// Sent 记录 nonce 对应的已发送交易
+func (l *NonceLease) Sent(ctx context.Context, tx *types.Transaction) {
+	l.set(ctx, func(account nonceAccount) {
+		account[l.Nonce] = nonceRecord{Hash: tx.Hash(), Time: time.Now()}
+	})
+}
+
+// Built 记录 nonce 已用于离线构建的交易, 在签名后广播 (或超过 24 小时) 之前不会被重新分配
+func (l *NonceLease) Built(ctx context.Context) {
+	l.set(ctx, func(account nonceAccount) {
+		account[l.Nonce] = nonceRecord{Built: true, Time: time.Now()}
+	})
+}
+
+// Release 释放未能发送的 nonce, 下次分配时会重新使用
+func (l *NonceLease) Release(ctx context.Context) {
+	l.set(ctx, func(account nonceAccount) {
+		delete(account, l.Nonce)
+	})
+}
+
+func (l *NonceLease) set(ctx context.Context, fn func(nonceAccount)) {
+	// 调用方的 ctx 可能已经取消, 仍然需要写入结果
+	ctx = context.WithoutCancel(ctx)
+	err := l.manager.update(ctx, func() error {
+		fn(l.manager.account(l.key))
+		return nil
+	})
+	if err != nil {
+		Logf("保存 nonce %d 状态失败: %v", l.Nonce, err)
+	}
+}
+
+// Sent 记录不经过 Acquire 发送的交易, 例如替换交易(加速或取消)和离线签名后广播的交易
+// 替换交易记录后, 被替换的原交易从节点消失时 nonce 不会被重新分配
+func (m *NonceManager) Sent(ctx context.Context, chainID *big.Int, address common.Address, tx *types.Transaction) {
+	lease := &NonceLease{Nonce: tx.Nonce(), manager: m, key: nonceKey(chainID, address)}
+	lease.Sent(ctx, tx)
+}
+
+// Reset 清除 address 的全部记录, 下次分配时完全以节点的 pending nonce 为准
+func (m *NonceManager) Reset(ctx context.Context, chainID *big.Int, address common.Address) error {
+	return m.update(ctx, func() error {
+		delete(m.accounts, nonceKey(chainID, address))
+		return nil
+	})
+}
+
+// account 返回 key 对应的记录, 不存在时创建
+func (m *NonceManager) account(key string) nonceAccount {
+	account, ok := m.accounts[key]
+	if !ok {
+		account = make(nonceAccount)
+		m.accounts[key] = account
+	}
+	return account
+}
+
+// update 在锁内执行 fn, 启用持久化时先从文件加载其他进程的修改, 执行后写回文件
+func (m *NonceManager) update(ctx context.Context, fn func() error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.path == "" {
+		return fn()
+	}
+
+	unlock, err := LockFile(ctx, m.path+".lock")
+	if err != nil {
+		return fmt.Errorf("锁定 nonce 存储失败: %w", err)
+	}
+	defer unlock()
+	if err := m.load(); err != nil {
+		return err
+	}
+	if err := fn(); err != nil {
+		return err
+	}
+	return m.save()
+}
+
+// nonceFile 持久化文件格式: "链ID:地址" -> nonce -> 记录
+type nonceFile map[string]map[string]nonceRecord
+
+func (m *NonceManager) load() error {
+	data, err := os.ReadFile(m.path)
+	if errors.Is(err, os.ErrNotExist) {
+		m.accounts = make(map[string]nonceAccount)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("读取 nonce 存储失败: %w", err)
+	}
+	var file nonceFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("解析 nonce 存储 %s 失败: %w", m.path, err)
+	}
+	m.accounts = make(map[string]nonceAccount, len(file))
+	for key, records := range file {
+		account := make(nonceAccount, len(records))
+		for n, record := range records {
+			nonce, err := strconv.ParseUint(n, 10, 64)
+			if err != nil {
+				return fmt.Errorf("解析 nonce 存储 %s 失败: 无效的 nonce %q", m.path, n)
+			}
+			account[nonce] = record
+		}
+		m.accounts[key] = account
+	}
+	return nil
+}
+
+func (m *NonceManager) save() error {
+	file := make(nonceFile, len(m.accounts))
+	for key, account := range m.accounts {
+		if len(account) == 0 {
+			continue
+		}
+		records := make(map[string]nonceRecord, len(account))
+		for nonce, record := range account {
+			records[strconv.FormatUint(nonce, 10)] = record
+		}
+		file[key] = records
+	}
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	// 先写临时文件再重命名, 避免进程中断时留下不完整的文件
+	tmp := m.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("写入 nonce 存储失败: %w", err)
+	}
+	return os.Rename(tmp, m.path)
+}
+
+// maxNonce 返回已分配的最大 nonce
+func maxNonce(account nonceAccount) uint64 {
+	var highest uint64
+	for n := range account {
+		highest = max(highest, n)
+	}
+	return highest
+}
@@ -0,0 +1,194 @@
+package util
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// nonceClient 只实现 nonce 管理器使用的 PendingNonceAt 和 TransactionByHash
+type nonceClient struct {
+	Client
+	pending uint64
+	txs     map[common.Hash]bool // 节点中仍存在的交易
+	lookups int
+	lookup  func() // 查询交易时调用, 用于检查查询时没有持有锁
+}
+
+func (c *nonceClient) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return c.pending, nil
+}
+
+func (c *nonceClient) TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error) {
+	c.lookups++
+	if c.lookup != nil {
+		c.lookup()
+	}
+	if c.txs[hash] {
+		return types.NewTx(&types.LegacyTx{}), true, nil
+	}
+	return nil, false, ethereum.NotFound
+}
+
+var (
+	nonceChainID = big.NewInt(1337)
+	nonceAddress = common.HexToAddress("0x00000000000000000000000000000000000000aa")
+)
+
+func acquire(t *testing.T, m *NonceManager, client Client) *NonceLease {
+	t.Helper()
+	lease, err := m.Acquire(context.Background(), client, nonceChainID, nonceAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return lease
+}
+
+func expectNonce(t *testing.T, lease *NonceLease, want uint64) {
+	t.Helper()
+	if lease.Nonce != want {
+		t.Fatalf("分配的 nonce 为 %d, 预期 %d", lease.Nonce, want)
+	}
+}
+
+// sentTx 构造 nonce 为 n 的交易, 不同的 n 对应不同的哈希
+func sentTx(n uint64) *types.Transaction {
+	return types.NewTx(&types.LegacyTx{Nonce: n, GasPrice: big.NewInt(1), Gas: 21000})
+}
+
+func TestNonceAcquireOrder(t *testing.T) {
+	m, _ := NewNonceManager("")
+	client := &nonceClient{pending: 5}
+	for want := uint64(5); want < 8; want++ {
+		expectNonce(t, acquire(t, m, client), want)
+	}
+
+	// 节点的 pending nonce 超过已分配的 nonce 后, 从新的 pending nonce 开始分配
+	client.pending = 10
+	expectNonce(t, acquire(t, m, client), 10)
+	if n := len(m.accounts[nonceKey(nonceChainID, nonceAddress)]); n != 1 {
+		t.Errorf("低于 pending nonce 的记录没有清除, 剩余 %d 条", n)
+	}
+}
+
+func TestNonceReleaseFillsGap(t *testing.T) {
+	ctx := context.Background()
+	m, _ := NewNonceManager("")
+	client := &nonceClient{pending: 0}
+	first := acquire(t, m, client)
+	second := acquire(t, m, client)
+	acquire(t, m, client)
+
+	first.Release(ctx)
+	expectNonce(t, acquire(t, m, client), 0)
+	second.Release(ctx)
+	expectNonce(t, acquire(t, m, client), 1)
+	expectNonce(t, acquire(t, m, client), 3)
+}
+
+func TestNonceReserveTTL(t *testing.T) {
+	m, _ := NewNonceManager("")
+	client := &nonceClient{pending: 3}
+	acquire(t, m, client)
+	acquire(t, m, client)
+
+	// nonce 3 分配后超过保留时间未发送, 视为发送方已退出
+	m.accounts[nonceKey(nonceChainID, nonceAddress)][3] = nonceRecord{Time: time.Now().Add(-nonceReserveTTL - time.Second)}
+	expectNonce(t, acquire(t, m, client), 3)
+	expectNonce(t, acquire(t, m, client), 5)
+
+	// 离线构建的交易保留更长的时间
+	acquire(t, m, client).Built(context.Background())
+	account := m.accounts[nonceKey(nonceChainID, nonceAddress)]
+	account[6] = nonceRecord{Built: true, Time: time.Now().Add(-nonceReserveTTL - time.Second)}
+	expectNonce(t, acquire(t, m, client), 7)
+	account[6] = nonceRecord{Built: true, Time: time.Now().Add(-nonceBuiltTTL - time.Second)}
+	expectNonce(t, acquire(t, m, client), 6)
+	if client.lookups != 0 {
+		t.Errorf("未发送的 nonce 不需要查询节点, 实际查询了 %d 次", client.lookups)
+	}
+}
+
+func TestNonceSent(t *testing.T) {
+	ctx := context.Background()
+	m, _ := NewNonceManager("")
+	client := &nonceClient{pending: 0, txs: make(map[common.Hash]bool)}
+	for n := uint64(0); n < 3; n++ {
+		acquire(t, m, client).Sent(ctx, sentTx(n))
+	}
+
+	// 刚发送的交易在宽限期内不查询节点
+	expectNonce(t, acquire(t, m, client), 3)
+	if client.lookups != 0 {
+		t.Fatalf("宽限期内查询了 %d 次节点", client.lookups)
+	}
+
+	// 超过宽限期后, 节点中仍存在的交易继续占用 nonce, 被丢弃的交易留下的空洞优先填补
+	account := m.accounts[nonceKey(nonceChainID, nonceAddress)]
+	for n := uint64(0); n < 3; n++ {
+		account[n] = nonceRecord{Hash: sentTx(n).Hash(), Time: time.Now().Add(-nonceSentGrace - time.Second)}
+	}
+	client.txs[sentTx(0).Hash()] = true
+	expectNonce(t, acquire(t, m, client), 1)
+
+	// 通过 NonceManager.Sent 记录的替换交易也会占用 nonce
+	m.Sent(ctx, nonceChainID, nonceAddress, sentTx(1))
+	expectNonce(t, acquire(t, m, client), 2)
+}
+
+func TestNoncePersistence(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "nonces.json")
+	first, err := NewNonceManager(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := NewNonceManager(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &nonceClient{pending: 7, txs: make(map[common.Hash]bool)}
+
+	// 两个管理器 (模拟两个进程) 共享持久化文件, 不会分配相同的 nonce
+	lease := acquire(t, first, client)
+	expectNonce(t, lease, 7)
+	expectNonce(t, acquire(t, second, client), 8)
+	lease.Release(ctx)
+	expectNonce(t, acquire(t, second, client), 7)
+
+	// 查询节点时已经释放了文件锁
+	lock := path + ".lock"
+	client.lookup = func() {
+		// ctx 已取消, 锁被持有时不等待
+		canceled, cancel := context.WithCancel(ctx)
+		cancel()
+		unlock, err := LockFile(canceled, lock)
+		if err != nil {
+			t.Errorf("查询交易时仍持有锁文件 %s: %v", lock, err)
+			return
+		}
+		unlock()
+	}
+	first.Sent(ctx, nonceChainID, nonceAddress, sentTx(9))
+	first.update(ctx, func() error {
+		first.accounts[nonceKey(nonceChainID, nonceAddress)][9] = nonceRecord{Hash: sentTx(9).Hash(), Time: time.Now().Add(-nonceSentGrace - time.Second)}
+		return nil
+	})
+	expectNonce(t, acquire(t, second, client), 9)
+	if client.lookups != 1 {
+		t.Errorf("查询了 %d 次节点, 预期 1 次", client.lookups)
+	}
+
+	// 重新打开时从文件加载已分配的 nonce
+	reopened, err := NewNonceManager(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectNonce(t, acquire(t, reopened, client), 10)
+}
@@ -0,0 +1,458 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"task1/errs"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Client 以太坊客户端接口, *ethclient.Client 和 *Pool 都实现了该接口
+type Client interface {
+	bind.ContractBackend
+	ethereum.ChainReader
+	ethereum.TransactionReader
+	ethereum.ChainStateReader
+	ethereum.BlockNumberReader
+	ethereum.ChainIDReader
+	ethereum.FeeHistoryReader
+	NetworkID(ctx context.Context) (*big.Int, error)
+	EstimateGasAtBlock(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) (uint64, error)
+	Close()
+}
+
+var (
+	_ Client = (*ethclient.Client)(nil)
+	_ Client = (*Pool)(nil)
+)
+
+// ErrNoHealthyEndpoint 连接池中没有可用的端点
+var ErrNoHealthyEndpoint = errors.New("没有可用的 RPC 端点")
+
+// PoolConfig 连接池健康检查配置
+type PoolConfig struct {
+	ChainID       uint64        // 期望的链ID, 0 表示不校验
+	MaxHeadAge    time.Duration // 最新区块时间距今的最大间隔, 0 表示不检查区块新鲜度
+	MaxBlockLag   uint64        // 落后于最高区块的最大块数, 超过则视为不健康
+	CheckInterval time.Duration // 健康检查结果的有效期, 过期后在下次调用前重新检查
+	CheckTimeout  time.Duration // 单个端点健康检查的超时时间
+}
+
+// DefaultPoolConfig 返回网络对应的默认连接池配置
+func DefaultPoolConfig(network *Network) PoolConfig {
+	return PoolConfig{
+		ChainID:       network.ChainID,
+		MaxHeadAge:    network.MaxHeadAge,
+		MaxBlockLag:   3,
+		CheckInterval: 30 * time.Second,
+		CheckTimeout:  5 * time.Second,
+	}
+}
+
+// EndpointStatus 端点健康状态
+type EndpointStatus struct {
+	URL       string        // 端点地址(未替换占位符, 不包含密钥)
+	Healthy   bool          // 是否健康
+	Head      uint64        // 最新区块号
+	HeadTime  time.Time     // 最新区块时间
+	Latency   time.Duration // 获取最新区块的耗时
+	Err       error         // 最近一次错误
+	CheckedAt time.Time     // 最近一次检查时间
+}
+
+// endpoint 连接池中的单个端点
+type endpoint struct {
+	EndpointStatus
+	client      *ethclient.Client
+	chainOK     bool // 链ID已校验通过
+	chainFailed bool // 链ID不匹配, 永久不可用
+}
+
+// Pool 多端点 RPC 连接池
+// 按链ID、区块新鲜度和延迟对端点做健康检查, 调用时优先使用最佳端点,
+// 遇到网络类错误时自动切换到下一个端点
+type Pool struct {
+	name      string
+	config    PoolConfig
+	mu        sync.Mutex
+	endpoints []*endpoint
+}
+
+// NewPool 连接所有端点并执行首次健康检查
+// urls 中的 <API_KEY> 等占位符在连接时替换, 状态中只保留原始模板
+func NewPool(ctx context.Context, name string, urls []string, config PoolConfig, opts ...rpc.ClientOption) (*Pool, error) {
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("网络 %s 没有配置端点", name)
+	}
+	p := &Pool{name: name, config: config}
+	for _, url := range urls {
+		ep := &endpoint{EndpointStatus: EndpointStatus{URL: url}}
+		rpcClient, err := rpc.DialOptions(ctx, LoadEnv(url), opts...)
+		if err != nil {
+			ep.Err = fmt.Errorf("连接失败: %w", err)
+			ep.CheckedAt = time.Now()
+		} else {
+			ep.client = ethclient.NewClient(rpcClient)
+		}
+		p.endpoints = append(p.endpoints, ep)
+	}
+	p.Check(ctx)
+	if _, err := p.healthy(); err != nil {
+		p.Close()
+		return nil, err
+	}
+	return p, nil
+}
+
+// Check 并发检查所有端点的健康状态
+func (p *Pool) Check(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, ep := range p.endpoints {
+		wg.Add(1)
+		go func(ep *endpoint) {
+			defer wg.Done()
+			p.checkEndpoint(ctx, ep)
+		}(ep)
+	}
+	wg.Wait()
+
+	// 根据最高区块判断落后的端点
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var best uint64
+	for _, ep := range p.endpoints {
+		if ep.Healthy && ep.Head > best {
+			best = ep.Head
+		}
+	}
+	for _, ep := range p.endpoints {
+		if ep.Healthy && p.config.MaxBlockLag > 0 && ep.Head+p.config.MaxBlockLag < best {
+			ep.Healthy = false
+			ep.Err = fmt.Errorf("区块落后: %d, 最高区块: %d", ep.Head, best)
+		}
+	}
+}
+
+// checkEndpoint 检查单个端点: 链ID、最新区块新鲜度和延迟
+func (p *Pool) checkEndpoint(ctx context.Context, ep *endpoint) {
+	p.mu.Lock()
+	client, chainOK, chainFailed := ep.client, ep.chainOK, ep.chainFailed
+	p.mu.Unlock()
+	if client == nil || chainFailed {
+		return
+	}
+
+	timeout := p.config.CheckTimeout
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	status := EndpointStatus{URL: ep.URL, CheckedAt: time.Now()}
+	if !chainOK && p.config.ChainID != 0 {
+		chainID, err := client.ChainID(ctx)
+		if err != nil {
+			status.Err = fmt.Errorf("获取链ID失败: %w", err)
+			p.setStatus(ep, status, false)
+			return
+		}
+		if chainID.Uint64() != p.config.ChainID {
+			status.Err = fmt.Errorf("链ID不匹配: 期望 %d, 实际 %s", p.config.ChainID, chainID)
+			p.setStatus(ep, status, true)
+			return
+		}
+	}
+
+	start := time.Now()
+	header, err := client.HeaderByNumber(ctx, nil)
+	status.Latency = time.Since(start)
+	if err != nil {
+		status.Err = fmt.Errorf("获取最新区块失败: %w", err)
+		p.setStatus(ep, status, false)
+		return
+	}
+	status.Head = header.Number.Uint64()
+	status.HeadTime = time.Unix(int64(header.Time), 0)
+	if p.config.MaxHeadAge > 0 && time.Since(status.HeadTime) > p.config.MaxHeadAge {
+		status.Err = fmt.Errorf("最新区块 %d 已过期: %s 前", status.Head, time.Since(status.HeadTime).Truncate(time.Second))
+		p.setStatus(ep, status, false)
+		return
+	}
+	status.Healthy = true
+	p.mu.Lock()
+	ep.chainOK = true
+	p.mu.Unlock()
+	p.setStatus(ep, status, false)
+}
+
+func (p *Pool) setStatus(ep *endpoint, status EndpointStatus, chainFailed bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	ep.EndpointStatus = status
+	if chainFailed {
+		ep.chainFailed = true
+	}
+}
+
+// Status 返回所有端点的健康状态, 按优先级排序
+func (p *Pool) Status() []EndpointStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	res := make([]EndpointStatus, 0, len(p.endpoints))
+	for _, ep := range p.sorted() {
+		res = append(res, ep.EndpointStatus)
+	}
+	return res
+}
+
+// sorted 按健康状态和延迟排序端点, 调用方需持有锁
+func (p *Pool) sorted() []*endpoint {
+	eps := append([]*endpoint(nil), p.endpoints...)
+	sort.SliceStable(eps, func(i, j int) bool {
+		if eps[i].Healthy != eps[j].Healthy {
+			return eps[i].Healthy
+		}
+		return eps[i].Latency < eps[j].Latency
+	})
+	return eps
+}
+
+// ranked 返回按优先级排序的健康端点, 检查结果过期时先重新检查
+// 没有健康端点时立即重新检查一次, 避免单端点网络因一次临时错误在整个检查周期内不可用
+func (p *Pool) ranked(ctx context.Context) ([]*endpoint, error) {
+	p.mu.Lock()
+	stale := false
+	for _, ep := range p.endpoints {
+		if ep.client == nil || ep.chainFailed {
+			continue
+		}
+		if p.config.CheckInterval > 0 && time.Since(ep.CheckedAt) > p.config.CheckInterval {
+			stale = true
+		}
+	}
+	p.mu.Unlock()
+	if stale {
+		p.Check(ctx)
+	}
+
+	healthy, err := p.healthy()
+	if err != nil && !stale && ctx.Err() == nil {
+		p.Check(ctx)
+		healthy, err = p.healthy()
+	}
+	return healthy, err
+}
+
+// healthy 返回按优先级排序的健康端点, 没有时返回所有端点的错误
+func (p *Pool) healthy() ([]*endpoint, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var healthy []*endpoint
+	var errs []error
+	for _, ep := range p.sorted() {
+		if ep.Healthy {
+			healthy = append(healthy, ep)
+		} else if ep.Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", ep.URL, ep.Err))
+		}
+	}
+	if len(healthy) == 0 {
+		return nil, fmt.Errorf("%w (网络 %s): %w", ErrNoHealthyEndpoint, p.name, errors.Join(errs...))
+	}
+	return healthy, nil
+}
+
+// markFailed 将调用失败的端点标记为不健康, 在下次健康检查前优先使用其他端点
+func (p *Pool) markFailed(ep *endpoint, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	ep.Healthy = false
+	ep.Err = err
+	ep.CheckedAt = time.Now()
+}
+
+// poolCall 在最佳端点上执行调用, 遇到网络、限流、鉴权等端点相关错误时切换到下一个端点
+func poolCall[T any](ctx context.Context, p *Pool, fn func(*ethclient.Client) (T, error)) (T, error) {
+	var zero T
+	endpoints, err := p.ranked(ctx)
+	if err != nil {
+		return zero, err
+	}
+	var failures []error
+	for _, ep := range endpoints {
+		res, err := fn(ep.client)
+		if err == nil {
+			return res, nil
+		}
+		if !errs.PolicyOf(err).Failover || ctx.Err() != nil {
+			return zero, err
+		}
+		Logf("端点 %s 调用失败, 切换到下一个端点: %v", ep.URL, err)
+		p.markFailed(ep, err)
+		failures = append(failures, fmt.Errorf("%s: %w", ep.URL, err))
+	}
+	return zero, fmt.Errorf("%w (网络 %s): %w", ErrNoHealthyEndpoint, p.name, errors.Join(failures...))
+}
+
+// poolExec 与 poolCall 相同, 用于没有返回值的调用
+func poolExec(ctx context.Context, p *Pool, fn func(*ethclient.Client) error) error {
+	_, err := poolCall(ctx, p, func(c *ethclient.Client) (struct{}, error) {
+		return struct{}{}, fn(c)
+	})
+	return err
+}
+
+// Close 关闭所有端点连接
+func (p *Pool) Close() {
+	for _, ep := range p.endpoints {
+		if ep.client != nil {
+			ep.client.Close()
+		}
+	}
+}
+
+func (p *Pool) ChainID(ctx context.Context) (*big.Int, error) {
+	return poolCall(ctx, p, func(c *ethclient.Client) (*big.Int, error) { return c.ChainID(ctx) })
+}
+
+func (p *Pool) NetworkID(ctx context.Context) (*big.Int, error) {
+	return poolCall(ctx, p, func(c *ethclient.Client) (*big.Int, error) { return c.NetworkID(ctx) })
+}
+
+func (p *Pool) BlockNumber(ctx context.Context) (uint64, error) {
+	return poolCall(ctx, p, func(c *ethclient.Client) (uint64, error) { return c.BlockNumber(ctx) })
+}
+
+func (p *Pool) BlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error) {
+	return poolCall(ctx, p, func(c *ethclient.Client) (*types.Block, error) { return c.BlockByHash(ctx, hash) })
+}
+
+func (p *Pool) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	return poolCall(ctx, p, func(c *ethclient.Client) (*types.Block, error) { return c.BlockByNumber(ctx, number) })
+}
+
+func (p *Pool) HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error) {
+	return poolCall(ctx, p, func(c *ethclient.Client) (*types.Header, error) { return c.HeaderByHash(ctx, hash) })
+}
+
+func (p *Pool) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return poolCall(ctx, p, func(c *ethclient.Client) (*types.Header, error) { return c.HeaderByNumber(ctx, number) })
+}
+
+func (p *Pool) TransactionCount(ctx context.Context, blockHash common.Hash) (uint, error) {
+	return poolCall(ctx, p, func(c *ethclient.Client) (uint, error) { return c.TransactionCount(ctx, blockHash) })
+}
+
+func (p *Pool) TransactionInBlock(ctx context.Context, blockHash common.Hash, index uint) (*types.Transaction, error) {
+	return poolCall(ctx, p, func(c *ethclient.Client) (*types.Transaction, error) {
+		return c.TransactionInBlock(ctx, blockHash, index)
+	})
+}
+
+func (p *Pool) TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error) {
+	type result struct {
+		tx        *types.Transaction
+		isPending bool
+	}
+	res, err := poolCall(ctx, p, func(c *ethclient.Client) (result, error) {
+		tx, isPending, err := c.TransactionByHash(ctx, hash)
+		return result{tx, isPending}, err
+	})
+	return res.tx, res.isPending, err
+}
+
+func (p *Pool) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	return poolCall(ctx, p, func(c *ethclient.Client) (*types.Receipt, error) { return c.TransactionReceipt(ctx, txHash) })
+}
+
+func (p *Pool) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	return poolCall(ctx, p, func(c *ethclient.Client) (*big.Int, error) { return c.BalanceAt(ctx, account, blockNumber) })
+}
+
+func (p *Pool) StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error) {
+	return poolCall(ctx, p, func(c *ethclient.Client) ([]byte, error) { return c.StorageAt(ctx, account, key, blockNumber) })
+}
+
+func (p *Pool) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error) {
+	return poolCall(ctx, p, func(c *ethclient.Client) ([]byte, error) { return c.CodeAt(ctx, account, blockNumber) })
+}
+
+func (p *Pool) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	return poolCall(ctx, p, func(c *ethclient.Client) (uint64, error) { return c.NonceAt(ctx, account, blockNumber) })
+}
+
+func (p *Pool) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	return poolCall(ctx, p, func(c *ethclient.Client) ([]byte, error) { return c.PendingCodeAt(ctx, account) })
+}
+
+func (p *Pool) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return poolCall(ctx, p, func(c *ethclient.Client) (uint64, error) { return c.PendingNonceAt(ctx, account) })
+}
+
+func (p *Pool) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return poolCall(ctx, p, func(c *ethclient.Client) ([]byte, error) { return c.CallContract(ctx, msg, blockNumber) })
+}
+
+func (p *Pool) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error) {
+	return poolCall(ctx, p, func(c *ethclient.Client) (uint64, error) { return c.EstimateGas(ctx, msg) })
+}
+
+func (p *Pool) EstimateGasAtBlock(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) (uint64, error) {
+	return poolCall(ctx, p, func(c *ethclient.Client) (uint64, error) { return c.EstimateGasAtBlock(ctx, msg, blockNumber) })
+}
+
+func (p *Pool) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return poolCall(ctx, p, func(c *ethclient.Client) (*big.Int, error) { return c.SuggestGasPrice(ctx) })
+}
+
+func (p *Pool) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return poolCall(ctx, p, func(c *ethclient.Client) (*big.Int, error) { return c.SuggestGasTipCap(ctx) })
+}
+
+func (p *Pool) FeeHistory(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (*ethereum.FeeHistory, error) {
+	return poolCall(ctx, p, func(c *ethclient.Client) (*ethereum.FeeHistory, error) {
+		return c.FeeHistory(ctx, blockCount, lastBlock, rewardPercentiles)
+	})
+}
+
+// SendTransaction 广播交易, 失败时换端点重发同一笔已签名交易是安全的
+func (p *Pool) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	return poolExec(ctx, p, func(c *ethclient.Client) error { return c.SendTransaction(ctx, tx) })
+}
+
+func (p *Pool) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	return poolCall(ctx, p, func(c *ethclient.Client) ([]types.Log, error) { return c.FilterLogs(ctx, q) })
+}
+
+// SubscribeFilterLogs 在最佳端点上订阅日志, 订阅建立后不会自动切换端点
+func (p *Pool) SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	return poolCall(ctx, p, func(c *ethclient.Client) (ethereum.Subscription, error) {
+		return c.SubscribeFilterLogs(ctx, q, ch)
+	})
+}
+
+// SubscribeNewHead 在最佳端点上订阅新区块, 订阅建立后不会自动切换端点
+func (p *Pool) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	return poolCall(ctx, p, func(c *ethclient.Client) (ethereum.Subscription, error) {
+		return c.SubscribeNewHead(ctx, ch)
+	})
+}
+
+// SubscribeTransactionReceipts 在最佳端点上订阅交易收据, 订阅建立后不会自动切换端点
+func (p *Pool) SubscribeTransactionReceipts(ctx context.Context, q *ethereum.TransactionReceiptsQuery, ch chan<- []*types.Receipt) (ethereum.Subscription, error) {
+	return poolCall(ctx, p, func(c *ethclient.Client) (ethereum.Subscription, error) {
+		return c.SubscribeTransactionReceipts(ctx, q, ch)
+	})
+}
@@ -0,0 +1,224 @@
+package util
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"task1/errs"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// fakeEth 模拟节点的 eth 命名空间, 只实现连接池健康检查和测试调用的方法
+type fakeEth struct {
+	chainID uint64
+	head    uint64
+	age     time.Duration // 最新区块时间距今的间隔
+	delay   time.Duration // 返回最新区块前的延迟
+	revert  bool          // eth_call 返回执行回滚
+	calls   atomic.Int32  // eth_call 的调用次数
+}
+
+// nodeRevertError 节点返回的执行回滚错误
+type nodeRevertError struct{}
+
+func (nodeRevertError) Error() string          { return "execution reverted" }
+func (nodeRevertError) ErrorCode() int         { return 3 }
+func (nodeRevertError) ErrorData() interface{} { return "0x08c379a0" }
+
+func (f *fakeEth) ChainId() hexutil.Uint64 { return hexutil.Uint64(f.chainID) }
+
+func (f *fakeEth) BlockNumber() hexutil.Uint64 { return hexutil.Uint64(f.head) }
+
+func (f *fakeEth) GetBlockByNumber(number string, full bool) *types.Header {
+	time.Sleep(f.delay)
+	return &types.Header{
+		Number:     new(big.Int).SetUint64(f.head),
+		Time:       uint64(time.Now().Add(-f.age).Unix()),
+		Difficulty: new(big.Int),
+	}
+}
+
+func (f *fakeEth) Call(args json.RawMessage, block string) (hexutil.Bytes, error) {
+	f.calls.Add(1)
+	if f.revert {
+		return nil, nodeRevertError{}
+	}
+	return hexutil.Bytes{1}, nil
+}
+
+// fakeNode JSON-RPC 节点, status 不为 0 时所有请求返回该 HTTP 状态码
+type fakeNode struct {
+	eth    *fakeEth
+	url    string
+	status atomic.Int32
+}
+
+func startFakeNode(t *testing.T, eth *fakeEth) *fakeNode {
+	t.Helper()
+	if eth.chainID == 0 {
+		eth.chainID = 1337
+	}
+	if eth.head == 0 {
+		eth.head = 100
+	}
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", eth); err != nil {
+		t.Fatal(err)
+	}
+	node := &fakeNode{eth: eth}
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if status := int(node.status.Load()); status != 0 {
+			http.Error(w, http.StatusText(status), status)
+			return
+		}
+		server.ServeHTTP(w, r)
+	}))
+	t.Cleanup(func() {
+		httpServer.Close()
+		server.Stop()
+	})
+	node.url = httpServer.URL
+	return node
+}
+
+// testPool 使用 eths 模拟的节点创建连接池, 健康检查结果在测试期间不过期
+func testPool(t *testing.T, config PoolConfig, eths ...*fakeEth) (*Pool, []*fakeNode) {
+	t.Helper()
+	var nodes []*fakeNode
+	var urls []string
+	for _, eth := range eths {
+		node := startFakeNode(t, eth)
+		nodes = append(nodes, node)
+		urls = append(urls, node.url)
+	}
+	config.ChainID = 1337
+	config.CheckInterval = time.Hour
+	config.CheckTimeout = 2 * time.Second
+	pool, err := NewPool(context.Background(), "test", urls, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(pool.Close)
+	return pool, nodes
+}
+
+// healthyNodes 返回按优先级排序的健康端点在 nodes 中的下标
+func healthyNodes(t *testing.T, pool *Pool, nodes []*fakeNode) []int {
+	t.Helper()
+	healthy, err := pool.healthy()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var res []int
+	for _, ep := range healthy {
+		for i, node := range nodes {
+			if ep.URL == node.url {
+				res = append(res, i)
+			}
+		}
+	}
+	return res
+}
+
+func TestPoolHealth(t *testing.T) {
+	tests := []struct {
+		name   string
+		config PoolConfig
+		eths   []*fakeEth
+		want   []int // 按优先级排序的健康端点
+	}{
+		{"链ID不匹配", PoolConfig{}, []*fakeEth{{chainID: 1}, {}}, []int{1}},
+		{"最新区块过期", PoolConfig{MaxHeadAge: time.Minute}, []*fakeEth{{age: time.Hour}, {}}, []int{1}},
+		{"不检查区块新鲜度", PoolConfig{}, []*fakeEth{{age: time.Hour}}, []int{0}},
+		{"区块落后", PoolConfig{MaxBlockLag: 3}, []*fakeEth{{head: 100}, {head: 96}, {head: 97, delay: 50 * time.Millisecond}}, []int{0, 2}},
+		{"按延迟排序", PoolConfig{}, []*fakeEth{{delay: 100 * time.Millisecond}, {delay: 50 * time.Millisecond}, {}}, []int{2, 1, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool, nodes := testPool(t, tt.config, tt.eths...)
+			got := healthyNodes(t, pool, nodes)
+			if len(got) != len(tt.want) {
+				t.Fatalf("健康端点为 %v, 预期 %v: %+v", got, tt.want, pool.Status())
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("健康端点为 %v, 预期 %v: %+v", got, tt.want, pool.Status())
+				}
+			}
+		})
+	}
+
+	// 所有端点都连接到其他链时无法创建连接池
+	node := startFakeNode(t, &fakeEth{chainID: 1})
+	if _, err := NewPool(context.Background(), "test", []string{node.url}, PoolConfig{ChainID: 1337}); !errors.Is(err, ErrNoHealthyEndpoint) {
+		t.Fatalf("错误为 %v, 预期 %v", err, ErrNoHealthyEndpoint)
+	}
+}
+
+func TestPoolFailover(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   []int32 // 创建连接池后每个端点返回的 HTTP 状态码
+		revert   bool    // 首选端点执行回滚
+		calls    []int32 // 每个端点收到的 eth_call 次数
+		failover bool    // 首选端点是否被标记为不健康
+		err      error
+	}{
+		{"HTTP 429 切换端点", []int32{http.StatusTooManyRequests, 0}, false, []int32{0, 1}, true, nil},
+		{"HTTP 503 切换端点", []int32{http.StatusServiceUnavailable, 0}, false, []int32{0, 1}, true, nil},
+		{"执行回滚不切换端点", []int32{0, 0}, true, []int32{1, 0}, false, errs.ErrExecutionReverted},
+		{"全部端点不可用", []int32{http.StatusBadGateway, http.StatusBadGateway}, false, []int32{0, 0}, true, ErrNoHealthyEndpoint},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 首选端点延迟较低
+			pool, nodes := testPool(t, PoolConfig{}, &fakeEth{revert: tt.revert}, &fakeEth{delay: 50 * time.Millisecond})
+			for i, status := range tt.status {
+				nodes[i].status.Store(status)
+			}
+			_, err := pool.CallContract(context.Background(), ethereum.CallMsg{To: &common.Address{}}, nil)
+			if !errors.Is(errs.Classify(err), tt.err) {
+				t.Fatalf("错误为 %v, 预期 %v", err, tt.err)
+			}
+			for i, node := range nodes {
+				if calls := node.eth.calls.Load(); calls != tt.calls[i] {
+					t.Errorf("端点 %d 收到 %d 次调用, 预期 %d 次", i, calls, tt.calls[i])
+				}
+			}
+			for _, status := range pool.Status() {
+				if status.URL == nodes[0].url && status.Healthy == tt.failover {
+					t.Errorf("首选端点的健康状态为 %v, 预期 %v", status.Healthy, !tt.failover)
+				}
+			}
+		})
+	}
+}
+
+// TestPoolRecheck 没有健康端点时立即重新检查, 不等待检查周期结束
+func TestPoolRecheck(t *testing.T) {
+	pool, nodes := testPool(t, PoolConfig{}, &fakeEth{})
+	nodes[0].status.Store(http.StatusServiceUnavailable)
+	if _, err := pool.BlockNumber(context.Background()); !errors.Is(err, ErrNoHealthyEndpoint) {
+		t.Fatalf("错误为 %v, 预期 %v", err, ErrNoHealthyEndpoint)
+	}
+	if pool.Status()[0].Healthy {
+		t.Fatal("调用失败的端点应当被标记为不健康")
+	}
+
+	nodes[0].status.Store(0)
+	head, err := pool.BlockNumber(context.Background())
+	if err != nil || head != 100 {
+		t.Fatalf("恢复后的最新区块为 %d, %v", head, err)
+	}
+}
@@ -0,0 +1,217 @@
+package util
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/gorilla/websocket"
+	"github.com/spf13/viper"
+	"golang.org/x/net/http/httpproxy"
+	"golang.org/x/net/proxy"
+)
+
+// ProxyConfig 代理配置
+type ProxyConfig struct {
+	URL     *url.URL // 代理地址, nil 表示直连
+	NoProxy string   // 不走代理的地址列表, 格式同 NO_PROXY 环境变量
+}
+
+// proxyFlag 通过 --proxy 参数指定的代理, 优先级最高
+var proxyFlag string
+
+// SetProxy 设置命令行指定的代理地址, 为空表示使用环境配置
+func SetProxy(raw string) {
+	proxyFlag = raw
+}
+
+// LoadProxyConfig 按优先级加载代理配置:
+//
+//  1. --proxy 参数
+//  2. 环境文件中的 PROXY (可配合 PROXY_USERNAME / PROXY_PASSWORD / NO_PROXY)
+//  3. 系统环境变量 HTTPS_PROXY / ALL_PROXY / NO_PROXY
+//
+// 代理地址支持 http://, https://, socks5://, socks5h:// 协议,
+// 值为 none / direct / off 时表示不使用代理
+func LoadProxyConfig() (*ProxyConfig, error) {
+	raw := proxyFlag
+	noProxy := viper.GetString("NO_PROXY")
+	if raw == "" {
+		raw = viper.GetString("PROXY")
+	}
+	if raw == "" {
+		raw = firstEnv("HTTPS_PROXY", "https_proxy", "ALL_PROXY", "all_proxy")
+	}
+	if noProxy == "" {
+		noProxy = firstEnv("NO_PROXY", "no_proxy")
+	}
+
+	config := &ProxyConfig{NoProxy: noProxy}
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "", "none", "direct", "off":
+		return config, nil
+	}
+
+	proxyURL, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return nil, fmt.Errorf("代理地址解析失败 %q: %w", raw, err)
+	}
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("不支持的代理协议 %q, 可选: http, https, socks5, socks5h", proxyURL.Scheme)
+	}
+	if proxyURL.Host == "" {
+		return nil, fmt.Errorf("代理地址缺少主机: %q", raw)
+	}
+	// 代理认证信息可以单独配置, 避免写在 URL 里
+	if proxyURL.User == nil {
+		if username := viper.GetString("PROXY_USERNAME"); username != "" {
+			proxyURL.User = url.UserPassword(username, viper.GetString("PROXY_PASSWORD"))
+		}
+	}
+	config.URL = proxyURL
+	return config, nil
+}
+
+// String 返回隐藏了密码的代理描述
+func (p *ProxyConfig) String() string {
+	if p == nil || p.URL == nil {
+		return "direct"
+	}
+	return p.URL.Redacted()
+}
+
+// ProxyFunc 返回按目标地址选择代理的函数, 命中 NO_PROXY 或本地回环地址时直连
+func (p *ProxyConfig) ProxyFunc() func(*url.URL) (*url.URL, error) {
+	if p == nil || p.URL == nil {
+		return func(*url.URL) (*url.URL, error) { return nil, nil }
+	}
+	cfg := httpproxy.Config{
+		HTTPProxy:  p.URL.String(),
+		HTTPSProxy: p.URL.String(),
+		NoProxy:    p.NoProxy,
+	}
+	return cfg.ProxyFunc()
+}
+
+// HTTPClient 创建使用该代理配置的 HTTP 客户端
+func (p *ProxyConfig) HTTPClient() *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	proxyFunc := p.ProxyFunc()
+	transport.Proxy = func(req *http.Request) (*url.URL, error) {
+		return proxyFunc(req.URL)
+	}
+	return &http.Client{Transport: transport}
+}
+
+// WebsocketDialer 创建使用该代理配置的 WebSocket 拨号器
+// 每次返回新的拨号器, 不修改全局的 websocket.DefaultDialer
+func (p *ProxyConfig) WebsocketDialer() websocket.Dialer {
+	dialer := websocket.Dialer{
+		HandshakeTimeout: websocket.DefaultDialer.HandshakeTimeout,
+	}
+	if p == nil || p.URL == nil {
+		return dialer
+	}
+	proxyFunc := p.ProxyFunc()
+	dialer.NetDialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		// websocket 的目标地址只有 host:port, 按 https 地址判断是否需要代理
+		proxyURL, err := proxyFunc(&url.URL{Scheme: "https", Host: addr})
+		if err != nil {
+			return nil, err
+		}
+		if proxyURL == nil {
+			var d net.Dialer
+			return d.DialContext(ctx, network, addr)
+		}
+		return dialProxy(ctx, proxyURL, network, addr)
+	}
+	return dialer
+}
+
+// dialProxy 通过代理建立到 addr 的 TCP 连接
+func dialProxy(ctx context.Context, proxyURL *url.URL, network, addr string) (net.Conn, error) {
+	switch proxyURL.Scheme {
+	case "socks5", "socks5h":
+		dialer, err := proxy.FromURL(proxyURL, &net.Dialer{})
+		if err != nil {
+			return nil, fmt.Errorf("创建 socks5 代理失败: %w", err)
+		}
+		return dialer.(proxy.ContextDialer).DialContext(ctx, network, addr)
+	case "http", "https":
+		return dialConnect(ctx, proxyURL, addr)
+	}
+	return nil, fmt.Errorf("不支持的代理协议: %s", proxyURL.Scheme)
+}
+
+// dialConnect 通过 HTTP(S) 代理的 CONNECT 方法建立隧道
+func dialConnect(ctx context.Context, proxyURL *url.URL, addr string) (net.Conn, error) {
+	proxyAddr := proxyURL.Host
+	if proxyURL.Port() == "" {
+		if proxyURL.Scheme == "https" {
+			proxyAddr = net.JoinHostPort(proxyURL.Hostname(), "443")
+		} else {
+			proxyAddr = net.JoinHostPort(proxyURL.Hostname(), "80")
+		}
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", proxyAddr)
+	if err != nil {
+		return nil, fmt.Errorf("连接代理 %s 失败: %w", proxyAddr, err)
+	}
+	if proxyURL.Scheme == "https" {
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: proxyURL.Hostname()})
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("代理 TLS 握手失败: %w", err)
+		}
+		conn = tlsConn
+	}
+
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: addr},
+		Host:   addr,
+		Header: make(http.Header),
+	}
+	if user := proxyURL.User; user != nil {
+		password, _ := user.Password()
+		credential := base64.StdEncoding.EncodeToString([]byte(user.Username() + ":" + password))
+		req.Header.Set("Proxy-Authorization", "Basic "+credential)
+	}
+	if err := req.Write(conn); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("发送 CONNECT 请求失败: %w", err)
+	}
+	// 对端在收到 CONNECT 响应前不会发送数据, 可以直接丢弃缓冲读取器
+	resp, err := http.ReadResponse(bufio.NewReader(conn), req)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("读取 CONNECT 响应失败: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		conn.Close()
+		return nil, fmt.Errorf("代理拒绝 CONNECT 请求: %s", resp.Status)
+	}
+	return conn, nil
+}
+
+// firstEnv 返回第一个非空的系统环境变量
+func firstEnv(keys ...string) string {
+	for _, key := range keys {
+		if value := os.Getenv(key); value != "" {
+			return value
+		}
+	}
+	return ""
+}
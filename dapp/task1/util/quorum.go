@@ -0,0 +1,242 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// ErrQuorumUnavailable 健康端点数量不足以完成一致性校验
+var ErrQuorumUnavailable = errors.New("健康端点数量不足")
+
+// verifyQuorum 通过 --verify-quorum 指定的校验端点数量, 小于 2 表示不校验
+var verifyQuorum int
+
+// SetVerifyQuorum 设置区块和收据查询需要交叉校验的端点数量
+func SetVerifyQuorum(n int) {
+	verifyQuorum = n
+}
+
+// VerifyQuorum 返回当前的交叉校验端点数量
+func VerifyQuorum() int {
+	return verifyQuorum
+}
+
+// QuorumMismatch 单个端点与基准端点不一致的字段
+type QuorumMismatch struct {
+	Endpoint string // 不一致的端点
+	Field    string // 字段名称, missing 表示该端点查不到数据
+	Expected string // 基准端点的值
+	Actual   string // 该端点的值
+}
+
+// QuorumError 多个端点返回的数据不一致
+type QuorumError struct {
+	Kind       string           // block 或 receipt
+	Target     string           // 区块号或交易哈希
+	Reference  string           // 基准端点
+	Endpoints  []string         // 参与校验的全部端点
+	Mismatches []QuorumMismatch // 不一致的字段
+}
+
+func (e *QuorumError) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s %s 在 %d 个端点间不一致 (基准: %s)", e.Kind, e.Target, len(e.Endpoints), e.Reference)
+	for _, m := range e.Mismatches {
+		fmt.Fprintf(&sb, "\n  %s %s: 期望 %s, 实际 %s", m.Endpoint, m.Field, m.Expected, m.Actual)
+	}
+	return sb.String()
+}
+
+// OnlyMissing 是否所有不一致都是端点查不到数据, 通常是端点同步落后导致, 稍后重试即可
+func (e *QuorumError) OnlyMissing() bool {
+	for _, m := range e.Mismatches {
+		if m.Field != "missing" {
+			return false
+		}
+	}
+	return len(e.Mismatches) > 0
+}
+
+// quorumMember 参与一致性校验的端点
+type quorumMember struct {
+	url    string
+	client *ethclient.Client
+}
+
+// quorum 返回前 n 个健康端点
+func (p *Pool) quorum(ctx context.Context, n int) ([]quorumMember, error) {
+	endpoints, err := p.ranked(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(endpoints) < n {
+		return nil, fmt.Errorf("%w: 需要 %d 个, 可用 %d 个 (网络 %s)", ErrQuorumUnavailable, n, len(endpoints), p.name)
+	}
+	members := make([]quorumMember, n)
+	for i, ep := range endpoints[:n] {
+		members[i] = quorumMember{url: ep.URL, client: ep.client}
+	}
+	return members, nil
+}
+
+// quorumFetch 并发地从每个端点获取数据
+func quorumFetch[T any](members []quorumMember, fn func(*ethclient.Client) (T, error)) ([]T, []error) {
+	results := make([]T, len(members))
+	errs := make([]error, len(members))
+	var wg sync.WaitGroup
+	for i, m := range members {
+		wg.Add(1)
+		go func(i int, m quorumMember) {
+			defer wg.Done()
+			results[i], errs[i] = fn(m.client)
+		}(i, m)
+	}
+	wg.Wait()
+	return results, errs
+}
+
+// quorumCompare 以第一个成功返回的端点为基准比较各端点的字段摘要
+func quorumCompare(kind, target string, members []quorumMember, fields []map[string]string, errs []error) error {
+	qe := &QuorumError{Kind: kind, Target: target}
+	for _, m := range members {
+		qe.Endpoints = append(qe.Endpoints, m.url)
+	}
+
+	ref := -1
+	for i, err := range errs {
+		if err == nil {
+			ref = i
+			break
+		}
+	}
+	if ref < 0 {
+		return fmt.Errorf("所有端点查询 %s %s 都失败: %w", kind, target, errors.Join(errs...))
+	}
+	qe.Reference = members[ref].url
+
+	for i, m := range members {
+		if i == ref {
+			continue
+		}
+		if errs[i] != nil {
+			// 查不到数据属于不一致, 其他错误(网络等)同样无法证明一致
+			field := "error"
+			if errors.Is(errs[i], ethereum.NotFound) {
+				field = "missing"
+			}
+			qe.Mismatches = append(qe.Mismatches, QuorumMismatch{Endpoint: m.url, Field: field, Expected: "found", Actual: errs[i].Error()})
+			continue
+		}
+		for _, name := range sortedKeys(fields[ref]) {
+			if fields[ref][name] != fields[i][name] {
+				qe.Mismatches = append(qe.Mismatches, QuorumMismatch{Endpoint: m.url, Field: name, Expected: fields[ref][name], Actual: fields[i][name]})
+			}
+		}
+	}
+	if len(qe.Mismatches) > 0 {
+		return qe
+	}
+	return nil
+}
+
+// VerifyBlock 从 n 个端点获取同一区块, 比较区块哈希、状态根和交易数量
+func (p *Pool) VerifyBlock(ctx context.Context, number *big.Int, n int) (*types.Block, error) {
+	members, err := p.quorum(ctx, n)
+	if err != nil {
+		return nil, err
+	}
+	blocks, errs := quorumFetch(members, func(c *ethclient.Client) (*types.Block, error) {
+		return c.BlockByNumber(ctx, number)
+	})
+	fields := make([]map[string]string, len(blocks))
+	for i, block := range blocks {
+		if errs[i] != nil {
+			continue
+		}
+		fields[i] = map[string]string{
+			"hash":        block.Hash().Hex(),
+			"parentHash":  block.ParentHash().Hex(),
+			"stateRoot":   block.Root().Hex(),
+			"txCount":     fmt.Sprint(len(block.Transactions())),
+			"receiptHash": block.ReceiptHash().Hex(),
+		}
+	}
+	if err := quorumCompare("block", number.String(), members, fields, errs); err != nil {
+		return nil, err
+	}
+	for i, err := range errs {
+		if err == nil {
+			return blocks[i], nil
+		}
+	}
+	return nil, ethereum.NotFound
+}
+
+// VerifyReceipt 从 n 个端点获取同一交易收据, 比较所在区块哈希、状态根和执行结果
+func (p *Pool) VerifyReceipt(ctx context.Context, txHash common.Hash, n int) (*types.Receipt, error) {
+	members, err := p.quorum(ctx, n)
+	if err != nil {
+		return nil, err
+	}
+	type result struct {
+		receipt *types.Receipt
+		header  *types.Header
+	}
+	results, errs := quorumFetch(members, func(c *ethclient.Client) (result, error) {
+		receipt, err := c.TransactionReceipt(ctx, txHash)
+		if err != nil {
+			return result{}, err
+		}
+		// 收据中没有状态根(拜占庭分叉之后), 从所在区块头获取
+		header, err := c.HeaderByHash(ctx, receipt.BlockHash)
+		if err != nil {
+			return result{}, err
+		}
+		return result{receipt, header}, nil
+	})
+	fields := make([]map[string]string, len(results))
+	for i, res := range results {
+		if errs[i] != nil {
+			continue
+		}
+		fields[i] = map[string]string{
+			"blockHash":   res.receipt.BlockHash.Hex(),
+			"blockNumber": res.receipt.BlockNumber.String(),
+			"stateRoot":   res.header.Root.Hex(),
+			"txIndex":     fmt.Sprint(res.receipt.TransactionIndex),
+			"txRoot":      res.header.TxHash.Hex(),
+			"status":      fmt.Sprint(res.receipt.Status),
+			"gasUsed":     fmt.Sprint(res.receipt.GasUsed),
+			"logs":        fmt.Sprint(len(res.receipt.Logs)),
+		}
+	}
+	if err := quorumCompare("receipt", txHash.Hex(), members, fields, errs); err != nil {
+		return nil, err
+	}
+	for i, err := range errs {
+		if err == nil {
+			return results[i].receipt, nil
+		}
+	}
+	return nil, ethereum.NotFound
+}
+
+// sortedKeys 返回排序后的键, 保证错误信息输出顺序稳定
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
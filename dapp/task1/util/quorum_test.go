@@ -0,0 +1,78 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+)
+
+func TestQuorumCompare(t *testing.T) {
+	members := []quorumMember{{url: "a"}, {url: "b"}, {url: "c"}}
+	block := func(hash string) map[string]string {
+		return map[string]string{"hash": hash, "stateRoot": "0x01", "txCount": "3"}
+	}
+	missing := fmt.Errorf("查询失败: %w", ethereum.NotFound)
+	network := fmt.Errorf("post: %w", syscall.ECONNRESET)
+
+	tests := []struct {
+		name        string
+		fields      []map[string]string
+		errs        []error
+		reference   string
+		mismatches  []string // 不一致的 端点.字段
+		onlyMissing bool
+	}{
+		{"全部一致", []map[string]string{block("0xaa"), block("0xaa"), block("0xaa")}, []error{nil, nil, nil}, "", nil, false},
+		{"区块哈希不一致", []map[string]string{block("0xaa"), block("0xbb"), block("0xaa")}, []error{nil, nil, nil},
+			"a", []string{"b.hash"}, false},
+		{"多个字段不一致", []map[string]string{block("0xaa"), {"hash": "0xbb", "stateRoot": "0x02", "txCount": "3"}, block("0xaa")}, []error{nil, nil, nil},
+			"a", []string{"b.hash", "b.stateRoot"}, false},
+		{"只有端点查不到数据", []map[string]string{block("0xaa"), nil, nil}, []error{nil, missing, missing},
+			"a", []string{"b.missing", "c.missing"}, true},
+		{"查不到数据和哈希不一致", []map[string]string{block("0xaa"), nil, block("0xbb")}, []error{nil, missing, nil},
+			"a", []string{"b.missing", "c.hash"}, false},
+		{"网络错误无法证明一致", []map[string]string{block("0xaa"), nil, block("0xaa")}, []error{nil, network, nil},
+			"a", []string{"b.error"}, false},
+		{"基准端点失败时以下一个端点为准", []map[string]string{nil, block("0xbb"), block("0xcc")}, []error{missing, nil, nil},
+			"b", []string{"a.missing", "c.hash"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := quorumCompare("block", "100", members, tt.fields, tt.errs)
+			if tt.mismatches == nil {
+				if err != nil {
+					t.Fatalf("预期一致, 错误为 %v", err)
+				}
+				return
+			}
+			var qe *QuorumError
+			if !errors.As(err, &qe) {
+				t.Fatalf("错误为 %v, 预期 QuorumError", err)
+			}
+			var got []string
+			for _, m := range qe.Mismatches {
+				got = append(got, m.Endpoint+"."+m.Field)
+			}
+			if qe.Reference != tt.reference || strings.Join(got, ",") != strings.Join(tt.mismatches, ",") {
+				t.Fatalf("基准端点 %s, 不一致 %v, 预期 %s, %v", qe.Reference, got, tt.reference, tt.mismatches)
+			}
+			if qe.OnlyMissing() != tt.onlyMissing {
+				t.Fatalf("OnlyMissing() 为 %v, 预期 %v", qe.OnlyMissing(), tt.onlyMissing)
+			}
+			if len(qe.Endpoints) != len(members) || !strings.Contains(err.Error(), "block 100 在 3 个端点间不一致") {
+				t.Fatalf("错误信息: %v", err)
+			}
+		})
+	}
+
+	// 所有端点都失败时不是不一致, 返回每个端点的错误
+	err := quorumCompare("receipt", "0x01", members, make([]map[string]string, 3), []error{missing, network, missing})
+	var qe *QuorumError
+	if errors.As(err, &qe) || !errors.Is(err, ethereum.NotFound) || !errors.Is(err, syscall.ECONNRESET) {
+		t.Fatalf("错误为 %v", err)
+	}
+}
@@ -0,0 +1,74 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"task1/decoder"
+	"task1/errs"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ReplayRevert 通过 eth_call 重放失败的交易, 获取并解码回滚原因
+//
+// 重放使用交易所在区块的父区块状态 (区块执行前), 没有包含同一区块中排在前面的交易;
+// 节点不能在父区块上执行 (例如状态已被裁剪) 时改用所在区块执行后的状态.
+// 重放时不设置 gas 价格, 避免因余额已扣除手续费而失败
+func ReplayRevert(ctx context.Context, client Client, receipt *types.Receipt) (*decoder.Revert, error) {
+	tx, _, err := client.TransactionByHash(ctx, receipt.TxHash)
+	if err != nil {
+		return nil, fmt.Errorf("查询交易 %s 失败: %w", receipt.TxHash.Hex(), errs.Classify(err))
+	}
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("获取链ID失败: %w", errs.Classify(err))
+	}
+	from, err := types.Sender(types.LatestSignerForChainID(chainID), tx)
+	if err != nil {
+		return nil, fmt.Errorf("无法恢复交易 %s 的发送方: %w", receipt.TxHash.Hex(), err)
+	}
+
+	msg := txCallMsg(from, tx)
+	block := receipt.BlockNumber
+	if block.Sign() > 0 {
+		block = new(big.Int).Sub(block, big.NewInt(1))
+	}
+	_, err = client.CallContract(ctx, msg, block)
+	if _, ok := decoder.FromError(err); err != nil && !ok && block.Cmp(receipt.BlockNumber) != 0 {
+		Logf("在区块 %d 重放交易失败: %v, 改为在交易所在的区块 %d 重放", block, errs.Classify(err), receipt.BlockNumber)
+		block = receipt.BlockNumber
+		_, err = client.CallContract(ctx, msg, block)
+	}
+	if err == nil {
+		// 重放成功通常说明交易耗尽了 gas, 或者执行结果依赖区块内的其他交易
+		if receipt.GasUsed >= tx.Gas() {
+			return nil, fmt.Errorf("交易耗尽了 gas (gas 限制 %d)", tx.Gas())
+		}
+		return nil, fmt.Errorf("在区块 %d 重放交易没有回滚, 失败可能依赖区块内的其他交易", block)
+	}
+	revert, ok := decoder.FromError(err)
+	if !ok {
+		return nil, fmt.Errorf("重放交易失败: %w", errs.Classify(err))
+	}
+	return revert, nil
+}
+
+// DiagnoseReceipt 检查交易收据状态, 执行失败时重放交易获取回滚原因, 输出并附加到返回的 ErrTxFailed 中
+func DiagnoseReceipt(ctx context.Context, client Client, receipt *types.Receipt) error {
+	if err := CheckReceipt(receipt); err == nil {
+		return nil
+	}
+	revert, err := ReplayRevert(ctx, client, receipt)
+	if err != nil {
+		Logf("交易 %s 执行失败: %v", receipt.TxHash.Hex(), err)
+		return fmt.Errorf("%w: 交易 %s (区块 %d): %w", ErrTxFailed, receipt.TxHash.Hex(), receipt.BlockNumber, err)
+	}
+	Logf("交易 %s 执行失败, 回滚原因: %s", receipt.TxHash.Hex(), revert)
+	// 部分合约直接以原始字节回滚文本
+	if text := HexByteToASCII(revert.Data); !revert.Known() && text != "" && !strings.Contains(text, `\x`) {
+		Logf("回滚数据 (ASCII): %s", text)
+	}
+	return fmt.Errorf("%w: 交易 %s (区块 %d), 回滚原因: %s", ErrTxFailed, receipt.TxHash.Hex(), receipt.BlockNumber, revert)
+}
@@ -0,0 +1,95 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"slices"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// revertError 与节点返回的 execution reverted 错误相同, 携带回滚数据
+type revertError struct{ data string }
+
+func (e *revertError) Error() string          { return "execution reverted" }
+func (e *revertError) ErrorData() interface{} { return e.data }
+
+// replayClient 只实现 ReplayRevert 使用的方法, 按区块号返回 eth_call 的结果
+type replayClient struct {
+	Client
+	tx      *types.Transaction
+	results map[uint64]error
+	blocks  []uint64 // eth_call 使用的区块号
+}
+
+func (c *replayClient) TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error) {
+	return c.tx, false, nil
+}
+
+func (c *replayClient) ChainID(ctx context.Context) (*big.Int, error) {
+	return c.tx.ChainId(), nil
+}
+
+func (c *replayClient) CallContract(ctx context.Context, msg ethereum.CallMsg, block *big.Int) ([]byte, error) {
+	c.blocks = append(c.blocks, block.Uint64())
+	return nil, c.results[block.Uint64()]
+}
+
+// errorString Error(string) 的回滚数据
+func errorString(reason string) string {
+	data := crypto.Keccak256([]byte("Error(string)"))[:4]
+	data = append(data, common.LeftPadBytes([]byte{0x20}, 32)...)
+	data = append(data, common.LeftPadBytes(big.NewInt(int64(len(reason))).Bytes(), 32)...)
+	data = append(data, common.RightPadBytes([]byte(reason), 32)...)
+	return hexutil.Encode(data)
+}
+
+func TestReplayRevert(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	to := common.HexToAddress("0x00000000000000000000000000000000000000cc")
+	tx, err := types.SignNewTx(key, types.LatestSignerForChainID(big.NewInt(1337)),
+		&types.DynamicFeeTx{ChainID: big.NewInt(1337), To: &to, Gas: 100000, GasFeeCap: big.NewInt(1), GasTipCap: big.NewInt(1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	receipt := &types.Receipt{TxHash: tx.Hash(), BlockNumber: big.NewInt(10), GasUsed: 30000}
+	missing := errors.New("missing trie node")
+
+	tests := []struct {
+		name    string
+		results map[uint64]error
+		blocks  []uint64
+		reason  string // 为空表示预期 ReplayRevert 返回错误
+	}{
+		{"在父区块上回滚", map[uint64]error{9: &revertError{errorString("父区块")}}, []uint64{9}, "父区块"},
+		// 区块执行后的状态不再回滚 (例如后面的交易改变了状态), 父区块上仍然回滚
+		{"只在父区块上回滚", map[uint64]error{9: &revertError{errorString("执行前")}, 10: nil}, []uint64{9}, "执行前"},
+		{"父区块状态不可用", map[uint64]error{9: missing, 10: &revertError{errorString("所在区块")}}, []uint64{9, 10}, "所在区块"},
+		{"父区块上没有回滚", map[uint64]error{}, []uint64{9}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &replayClient{tx: tx, results: tt.results}
+			revert, err := ReplayRevert(context.Background(), client, receipt)
+			if tt.reason == "" {
+				if err == nil {
+					t.Fatalf("预期出错, 实际回滚原因为 %s", revert)
+				}
+			} else if err != nil || revert.Reason != tt.reason {
+				t.Fatalf("回滚原因为 %v, %v, 预期 %q", revert, err, tt.reason)
+			}
+			if !slices.Equal(client.blocks, tt.blocks) {
+				t.Errorf("重放使用的区块为 %v, 预期 %v", client.blocks, tt.blocks)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"task1/decoder"
+	"task1/errs"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// ErrDryRun 模拟执行成功, 按 --dry-run 的要求没有广播交易
+var ErrDryRun = errors.New("模拟执行成功, 交易未广播")
+
+// dryRun 通过 --dry-run 开启, 开启后所有交易只模拟执行不广播
+var dryRun bool
+
+// SetDryRun 设置是否只模拟执行交易
+func SetDryRun(enabled bool) {
+	dryRun = enabled
+}
+
+// DryRun 返回是否只模拟执行交易
+func DryRun() bool {
+	return dryRun
+}
+
+// pendingBlock 模拟执行使用 pending 状态, 包含交易池中已发送但未打包的交易
+var pendingBlock = big.NewInt(int64(rpc.PendingBlockNumber))
+
+// Simulation 交易的模拟执行结果
+type Simulation struct {
+	From         common.Address
+	Contract     *common.Address // 部署合约时的合约地址
+	Return       []byte          // eth_call 的返回数据
+	GasEstimate  uint64          // eth_estimateGas 估算的 gas 用量
+	Fee          *big.Int        // 按估算 gas 用量和当前基础费用计算的手续费
+	MaxFee       *big.Int        // gas 限制 * 最高费用, 发送时余额至少需要 value + MaxFee
+	Balance      *big.Int        // 发送前的余额
+	BalanceAfter *big.Int        // 按 Fee 计算的发送后余额
+}
+
+// Simulate 在 pending 状态上通过 eth_call 和 eth_estimateGas 模拟执行交易, 不广播
+// 交易会回滚时返回包含回滚原因的错误
+func Simulate(ctx context.Context, client Client, from common.Address, tx *types.Transaction) (*Simulation, error) {
+	msg := txCallMsg(from, tx)
+	if tx.Type() == types.LegacyTxType || tx.Type() == types.AccessListTxType {
+		msg.GasPrice = tx.GasPrice()
+	} else {
+		msg.GasFeeCap, msg.GasTipCap = tx.GasFeeCap(), tx.GasTipCap()
+	}
+
+	ret, err := client.CallContract(ctx, msg, pendingBlock)
+	if err != nil {
+		return nil, RevertError("模拟执行失败", err)
+	}
+	// 不限制 gas, 估算实际用量
+	estimateMsg := msg
+	estimateMsg.Gas = 0
+	gas, err := client.EstimateGasAtBlock(ctx, estimateMsg, pendingBlock)
+	if err != nil {
+		return nil, RevertError("估算 gas 失败", err)
+	}
+	balance, err := client.BalanceAt(ctx, from, pendingBlock)
+	if err != nil {
+		return nil, fmt.Errorf("查询余额失败: %w", errs.Classify(err))
+	}
+	header, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("获取最新区块失败: %w", errs.Classify(err))
+	}
+
+	// 动态费用交易实际支付 min(最高费用, 基础费用 + 优先费)
+	price := tx.GasPrice()
+	if tx.Type() == types.DynamicFeeTxType && header.BaseFee != nil {
+		price = new(big.Int).Add(header.BaseFee, tx.GasTipCap())
+		if price.Cmp(tx.GasFeeCap()) > 0 {
+			price = tx.GasFeeCap()
+		}
+	}
+	sim := &Simulation{
+		From:        from,
+		Return:      ret,
+		GasEstimate: gas,
+		Fee:         new(big.Int).Mul(price, new(big.Int).SetUint64(gas)),
+		MaxFee:      new(big.Int).Mul(tx.GasFeeCap(), new(big.Int).SetUint64(tx.Gas())),
+		Balance:     balance,
+	}
+	sim.BalanceAfter = new(big.Int).Sub(balance, tx.Value())
+	sim.BalanceAfter.Sub(sim.BalanceAfter, sim.Fee)
+	if tx.To() == nil {
+		contract := crypto.CreateAddress(from, tx.Nonce())
+		sim.Contract = &contract
+	}
+	return sim, nil
+}
+
+// txCallMsg 构建与交易内容相同的 eth_call 参数, 不包含手续费
+func txCallMsg(from common.Address, tx *types.Transaction) ethereum.CallMsg {
+	return ethereum.CallMsg{
+		From:       from,
+		To:         tx.To(),
+		Gas:        tx.Gas(),
+		Value:      tx.Value(),
+		Data:       tx.Data(),
+		AccessList: tx.AccessList(),
+	}
+}
+
+// Log 输出模拟执行结果
+func (s *Simulation) Log() {
+	decimals, symbol := 18, "ETH"
+	if network, err := CurrentNetwork(); err == nil {
+		decimals, symbol = int(network.Decimals), network.Symbol
+	}
+	Logf("模拟执行成功 (pending 状态), 预计 gas 用量 %d", s.GasEstimate)
+	if s.Contract != nil {
+		Logf("合约地址将为 %s", s.Contract.Hex())
+	}
+	// 部署合约时 eth_call 返回的是合约运行时代码, 不输出
+	if len(s.Return) > 0 && s.Contract == nil {
+		Logf("返回数据: %s", hexutil.Encode(s.Return))
+	}
+	Logf("预计手续费 %s %s (最多 %s %s)", FormatUnits(s.Fee, decimals), symbol, FormatUnits(s.MaxFee, decimals), symbol)
+	Logf("账户 %s 余额 %s %s, 发送后预计余额 %s %s", s.From.Hex(),
+		FormatUnits(s.Balance, decimals), symbol, FormatUnits(s.BalanceAfter, decimals), symbol)
+}
+
+// DryRunTx 在 --dry-run 模式下模拟执行交易并输出结果, 成功时返回 ErrDryRun; 未开启时返回 nil, 由调用方继续广播
+func DryRunTx(ctx context.Context, client Client, from common.Address, tx *types.Transaction) error {
+	if !dryRun {
+		return nil
+	}
+	Logf("--dry-run: 模拟执行交易 (nonce %d), 不广播", tx.Nonce())
+	sim, err := Simulate(ctx, client, from, tx)
+	if err != nil {
+		return err
+	}
+	sim.Log()
+	return ErrDryRun
+}
+
+// RevertError 归类 eth_call / eth_estimateGas 返回的错误, 能解码回滚原因时附加到错误信息中
+func RevertError(action string, err error) error {
+	if revert, ok := decoder.FromError(err); ok {
+		return fmt.Errorf("%s, 回滚原因 %s: %w", action, revert, errs.Classify(err))
+	}
+	return fmt.Errorf("%s: %w", action, errs.Classify(err))
+}
@@ -0,0 +1,172 @@
+package util
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"strings"
+	"task1/errs"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// simClient 在 simulated.Backend 上实现 Client
+type simClient struct {
+	simulated.Client
+}
+
+func (c simClient) NetworkID(ctx context.Context) (*big.Int, error) {
+	return c.ChainID(ctx)
+}
+
+func (c simClient) EstimateGasAtBlock(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) (uint64, error) {
+	return c.EstimateGas(ctx, msg)
+}
+
+func (c simClient) Close() {}
+
+// revertCode 运行时代码: 以 Error("nope") 回滚
+var revertCode = append(common.FromHex("6064600c60003960646000fd"), common.FromHex(
+	"08c379a0"+
+		"0000000000000000000000000000000000000000000000000000000000000020"+
+		"0000000000000000000000000000000000000000000000000000000000000004"+
+		"6e6f706500000000000000000000000000000000000000000000000000000000")...)
+
+var revertContract = common.HexToAddress("0x00000000000000000000000000000000000000ee")
+
+// newSimChain 创建有 1000 ETH 余额的账户和总是回滚的合约
+func newSimChain(t *testing.T) (*simulated.Backend, simClient, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend := simulated.NewBackend(types.GenesisAlloc{
+		crypto.PubkeyToAddress(key.PublicKey): {Balance: new(big.Int).Mul(big.NewInt(1000), big.NewInt(params.Ether))},
+		revertContract:                        {Code: revertCode, Balance: new(big.Int)},
+	})
+	t.Cleanup(func() { backend.Close() })
+	return backend, simClient{backend.Client()}, key
+}
+
+func TestSimulate(t *testing.T) {
+	ctx := context.Background()
+	_, client, key := newSimChain(t)
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	header, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chainID := params.AllDevChainProtocolChanges.ChainID
+	to := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	value := big.NewInt(params.Ether)
+	gwei := func(n int64) *big.Int { return new(big.Int).Mul(big.NewInt(n), big.NewInt(params.GWei)) }
+	// 基础费用 + 优先费不超过最高费用
+	tip, feeCap := gwei(2), new(big.Int).Add(new(big.Int).Mul(header.BaseFee, big.NewInt(2)), gwei(2))
+
+	tests := []struct {
+		name  string
+		tx    *types.Transaction
+		price *big.Int // 实际支付的 gas 价格
+	}{
+		{"EIP-1559 转账", types.NewTx(&types.DynamicFeeTx{ChainID: chainID, GasTipCap: tip, GasFeeCap: feeCap, Gas: 30000, To: &to, Value: value}),
+			new(big.Int).Add(header.BaseFee, tip)},
+		{"最高费用低于基础费用 + 优先费", types.NewTx(&types.DynamicFeeTx{ChainID: chainID, GasTipCap: feeCap, GasFeeCap: feeCap, Gas: 30000, To: &to, Value: value}),
+			feeCap},
+		{"传统交易", types.NewTx(&types.LegacyTx{GasPrice: feeCap, Gas: 30000, To: &to, Value: value}), feeCap},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sim, err := Simulate(ctx, client, from, tt.tx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sim.GasEstimate != params.TxGas {
+				t.Fatalf("估算的 gas 用量为 %d, 预期 %d", sim.GasEstimate, params.TxGas)
+			}
+			if fee := new(big.Int).Mul(tt.price, big.NewInt(int64(params.TxGas))); sim.Fee.Cmp(fee) != 0 {
+				t.Errorf("手续费为 %s, 预期 %s", sim.Fee, fee)
+			}
+			if maxFee := new(big.Int).Mul(tt.tx.GasFeeCap(), big.NewInt(30000)); sim.MaxFee.Cmp(maxFee) != 0 {
+				t.Errorf("最高手续费为 %s, 预期 %s", sim.MaxFee, maxFee)
+			}
+			after := new(big.Int).Sub(sim.Balance, value)
+			if after.Sub(after, sim.Fee); sim.BalanceAfter.Cmp(after) != 0 || sim.Contract != nil {
+				t.Errorf("发送后余额为 %s, 预期 %s", sim.BalanceAfter, after)
+			}
+		})
+	}
+
+	// 部署合约时给出合约地址
+	deploy := types.NewTx(&types.DynamicFeeTx{ChainID: chainID, Nonce: 0, GasTipCap: tip, GasFeeCap: feeCap, Gas: 100000, Data: common.FromHex("60006000f3")})
+	sim, err := Simulate(ctx, client, from, deploy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := crypto.CreateAddress(from, 0); sim.Contract == nil || *sim.Contract != want {
+		t.Fatalf("合约地址为 %v, 预期 %s", sim.Contract, want.Hex())
+	}
+
+	// 回滚时返回解码后的回滚原因
+	call := types.NewTx(&types.DynamicFeeTx{ChainID: chainID, GasTipCap: tip, GasFeeCap: feeCap, Gas: 30000, To: &revertContract})
+	_, err = Simulate(ctx, client, from, call)
+	if !errors.Is(err, errs.ErrExecutionReverted) || !strings.Contains(err.Error(), `Error("nope")`) {
+		t.Fatalf("错误为 %v, 预期包含回滚原因", err)
+	}
+}
+
+// TestDryRunBuildAndSend --dry-run 模式下只模拟执行, 不签名、不广播, 也不占用 nonce
+func TestDryRunBuildAndSend(t *testing.T) {
+	ctx := context.Background()
+	backend, client, key := newSimChain(t)
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	SetDryRun(true)
+	if err := SetNonceStore(""); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		SetDryRun(false)
+		SetNonceStore("")
+	})
+
+	header, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chainID := params.AllDevChainProtocolChanges.ChainID
+	send := func(to common.Address) error {
+		_, err := BuildAndSend(ctx, client, from, func(ctx context.Context, nonce uint64) (*types.Transaction, error) {
+			return types.NewTx(&types.DynamicFeeTx{ChainID: chainID, Nonce: nonce, GasTipCap: big.NewInt(params.GWei),
+				GasFeeCap: new(big.Int).Mul(header.BaseFee, big.NewInt(2)), Gas: 30000, To: &to, Value: big.NewInt(1)}), nil
+		}, func(ctx context.Context, tx *types.Transaction) (*types.Transaction, error) {
+			t.Error("--dry-run 模式下不应签名")
+			return types.SignTx(tx, types.LatestSignerForChainID(chainID), key)
+		})
+		return err
+	}
+	if err := send(common.HexToAddress("0x00000000000000000000000000000000000000aa")); !errors.Is(err, ErrDryRun) {
+		t.Fatalf("错误为 %v, 预期 %v", err, ErrDryRun)
+	}
+	if err := send(revertContract); !errors.Is(err, errs.ErrExecutionReverted) {
+		t.Fatalf("模拟执行回滚的错误为 %v, 预期 %v", err, errs.ErrExecutionReverted)
+	}
+
+	backend.Commit()
+	if nonce, err := client.PendingNonceAt(ctx, from); err != nil || nonce != 0 {
+		t.Fatalf("节点的 pending nonce 为 %d, %v, 预期 0", nonce, err)
+	}
+	lease, err := Nonces().Acquire(ctx, client, chainID, from)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lease.Nonce != 0 {
+		t.Fatalf("模拟执行后分配的 nonce 为 %d, 预期 0", lease.Nonce)
+	}
+}
@@ -0,0 +1,401 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"task1/errs"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// ErrReorged 交易所在区块被重组移除, 且交易已不在节点中
+var ErrReorged = errors.New("交易所在区块已被重组")
+
+// HeadSubscriber 可以订阅新区块的客户端, 通常是 WebSocket 连接
+type HeadSubscriber interface {
+	SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error)
+}
+
+// WaitConfig 交易收据等待配置
+type WaitConfig struct {
+	Confirmations uint64         // 需要的确认数, 交易所在区块算 1 个确认, 0 按 1 处理
+	Timeout       time.Duration  // 最长等待时间, 0 表示只受 ctx 控制
+	PollInterval  time.Duration  // 无法订阅新区块时的轮询间隔
+	Heads         HeadSubscriber // 新区块订阅来源, nil 表示使用等待收据的客户端
+	DialWS        bool           // 客户端不支持订阅时是否连接当前网络的 WebSocket 端点
+}
+
+// waitConfig 通过 --confirmations / --wait-timeout 指定的默认等待配置
+var waitConfig = WaitConfig{
+	Confirmations: 1,
+	Timeout:       2 * time.Minute,
+	PollInterval:  5 * time.Second,
+	DialWS:        true,
+}
+
+// SetWaitConfig 设置 WaitReceipt 使用的默认等待配置
+func SetWaitConfig(config WaitConfig) {
+	waitConfig = config
+}
+
+// DefaultWaitConfig 返回当前的默认等待配置
+func DefaultWaitConfig() WaitConfig {
+	return waitConfig
+}
+
+// Waiter 等待交易被打包并达到指定确认数
+// 优先订阅新区块, 订阅不可用或中断时改为轮询; 收据所在区块被重组时重新等待
+type Waiter struct {
+	client Client
+	config WaitConfig
+}
+
+// NewWaiter 创建交易收据等待器
+func NewWaiter(client Client, config WaitConfig) *Waiter {
+	if config.Confirmations == 0 {
+		config.Confirmations = 1
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = 5 * time.Second
+	}
+	return &Waiter{client: client, config: config}
+}
+
+// WaitReceipt 使用默认等待配置等待交易确认
+func WaitReceipt(ctx context.Context, client Client, txHash common.Hash) (*types.Receipt, error) {
+	return NewWaiter(client, DefaultWaitConfig()).Wait(ctx, txHash)
+}
+
+// Wait 等待单笔交易达到确认数
+func (w *Waiter) Wait(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	receipts, err := w.WaitAll(ctx, []common.Hash{txHash})
+	if err != nil {
+		return nil, err
+	}
+	return receipts[0], nil
+}
+
+// waitState 单笔交易的等待状态
+type waitState struct {
+	hash     common.Hash
+	receipt  *types.Receipt // 当前已知的收据, 被重组移除后清空
+	confirms uint64         // 上次输出的确认数
+	reorgs   int            // 已发生的重组次数
+	done     bool
+	err      error
+}
+
+// WaitAll 同时等待多笔交易达到确认数, 返回的收据与 hashes 一一对应
+// 部分交易失败或超时时, 已确认交易的收据仍会返回, 错误中包含每笔失败交易的原因
+func (w *Waiter) WaitAll(ctx context.Context, hashes []common.Hash) ([]*types.Receipt, error) {
+	states, err := w.wait(ctx, hashes, false)
+	if err != nil {
+		return nil, err
+	}
+	return w.result(states)
+}
+
+// WaitAny 等待多笔相互竞争的交易(例如使用相同 nonce 的原交易和替换交易)中任意一笔达到确认数
+// 返回先确认的交易收据, 全部失败或超时时返回错误
+func (w *Waiter) WaitAny(ctx context.Context, hashes []common.Hash) (*types.Receipt, error) {
+	states, err := w.wait(ctx, hashes, true)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range states {
+		if s.done && s.err == nil {
+			return s.receipt, nil
+		}
+	}
+	_, err = w.result(states)
+	return nil, err
+}
+
+// wait 等待交易达到确认数, first 为 true 时任意一笔确认即返回
+func (w *Waiter) wait(ctx context.Context, hashes []common.Hash, first bool) ([]*waitState, error) {
+	if w.client == nil {
+		return nil, fmt.Errorf("%w: ethclient cannot be nil", ErrInvalidArgument)
+	}
+
+	start := time.Now()
+	parent := ctx
+	if w.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, w.config.Timeout)
+		defer cancel()
+	}
+
+	states := make([]*waitState, len(hashes))
+	for i, hash := range hashes {
+		states[i] = &waitState{hash: hash}
+	}
+	if len(states) == 0 {
+		return states, nil
+	}
+	Logf("开始等待 %d 笔交易, 需要 %d 个确认", len(hashes), w.config.Confirmations)
+
+	watchCtx, stopWatch := context.WithCancel(ctx)
+	defer stopWatch()
+	heads := w.watchHeads(watchCtx)
+
+	// 启动时先检查一次, 交易可能已经被打包
+	head, err := w.client.BlockNumber(ctx)
+	for {
+		if err == nil && w.check(ctx, head, states, first) {
+			return states, nil
+		}
+		select {
+		case <-ctx.Done():
+			w.expire(parent, start, states)
+			return states, nil
+		case head = <-heads:
+			err = nil
+		}
+	}
+}
+
+// check 在新区块到达时更新每笔交易的状态, 全部完成(first 为 true 时任意一笔确认)时返回 true
+func (w *Waiter) check(ctx context.Context, head uint64, states []*waitState, first bool) bool {
+	finished := true
+	for _, s := range states {
+		if s.done {
+			continue
+		}
+		w.checkOne(ctx, head, s)
+		if first && s.done && s.err == nil {
+			return true
+		}
+		finished = finished && s.done
+	}
+	return finished
+}
+
+func (w *Waiter) checkOne(ctx context.Context, head uint64, s *waitState) {
+	if s.receipt == nil {
+		receipt, err := w.client.TransactionReceipt(ctx, s.hash)
+		if err != nil {
+			// 交易尚未被打包或节点暂时不可用时继续等待
+			if errs.IsRetryable(err) {
+				if s.reorgs > 0 && errors.Is(err, ethereum.NotFound) {
+					w.checkDropped(ctx, s)
+				}
+				return
+			}
+			w.fail(ctx, s, fmt.Errorf("获取交易 %s 的收据失败: %w", s.hash.Hex(), errs.Classify(err)))
+			return
+		}
+		s.receipt = receipt
+		Logf("交易 %s 已打包到区块 %d", s.hash.Hex(), receipt.BlockNumber)
+	}
+
+	// 确认所在区块仍在主链上
+	number := s.receipt.BlockNumber
+	header, err := w.client.HeaderByNumber(ctx, number)
+	if err != nil {
+		if !errs.IsRetryable(err) {
+			w.fail(ctx, s, fmt.Errorf("获取区块 %d 失败: %w", number, errs.Classify(err)))
+		}
+		return
+	}
+	if header.Hash() != s.receipt.BlockHash {
+		s.reorgs++
+		Logf("区块 %d 发生重组 (%s -> %s), 重新等待交易 %s", number, s.receipt.BlockHash.Hex(), header.Hash().Hex(), s.hash.Hex())
+		s.receipt, s.confirms = nil, 0
+		return
+	}
+
+	// 新区块可能来自落后的端点, 以较大者为准
+	head = max(head, number.Uint64())
+	confirms := head - number.Uint64() + 1
+	if confirms < w.config.Confirmations {
+		if confirms != s.confirms {
+			Logf("交易 %s 已有 %d/%d 个确认", s.hash.Hex(), confirms, w.config.Confirmations)
+			s.confirms = confirms
+		}
+		return
+	}
+
+	// 开启 --verify-quorum 时交叉校验多个端点返回的收据
+	if quorum := VerifyQuorum(); quorum > 1 {
+		pool, ok := w.client.(*Pool)
+		if !ok {
+			w.fail(ctx, s, fmt.Errorf("收据一致性校验需要使用连接池客户端"))
+			return
+		}
+		receipt, err := pool.VerifyReceipt(ctx, s.hash, quorum)
+		var qe *QuorumError
+		if errors.As(err, &qe) && qe.OnlyMissing() {
+			// 部分端点尚未同步到该区块, 下一个区块再校验
+			Logf("交易 %s 的收据尚未同步到全部 %d 个端点", s.hash.Hex(), quorum)
+			return
+		}
+		if err != nil {
+			w.fail(ctx, s, fmt.Errorf("收据一致性校验失败: %w", err))
+			return
+		}
+		Logf("交易 %s 的收据已在 %d 个端点间校验一致", s.hash.Hex(), quorum)
+		s.receipt = receipt
+	}
+
+	s.done = true
+	Logf("交易 %s 已确认! 区块 %d, 确认数 %d", s.hash.Hex(), number, confirms)
+}
+
+// checkDropped 重组后交易既不在链上也不在交易池中时结束等待
+func (w *Waiter) checkDropped(ctx context.Context, s *waitState) {
+	_, _, err := w.client.TransactionByHash(ctx, s.hash)
+	if errors.Is(err, ethereum.NotFound) {
+		w.fail(ctx, s, fmt.Errorf("%w: 交易 %s 已不在节点中, 需要重新发送", ErrReorged, s.hash.Hex()))
+	}
+}
+
+// fail 结束交易的等待并记录错误, ctx 已结束时由 expire 统一处理
+func (w *Waiter) fail(ctx context.Context, s *waitState, err error) {
+	if ctx.Err() != nil {
+		return
+	}
+	s.done, s.err = true, err
+}
+
+// expire 在等待结束时为未完成的交易生成超时或取消错误
+func (w *Waiter) expire(parent context.Context, start time.Time, states []*waitState) {
+	elapsed := time.Since(start).Round(time.Second)
+	for _, s := range states {
+		if s.done {
+			continue
+		}
+		s.done = true
+		if parent.Err() != nil {
+			// 调用方取消, 不属于超时
+			s.err = parent.Err()
+			continue
+		}
+		if s.receipt != nil {
+			s.err = fmt.Errorf("%w: 交易 %s 在 %s 内未达到 %d 个确认 (当前 %d 个)", ErrTimeout, s.hash.Hex(), elapsed, w.config.Confirmations, s.confirms)
+		} else {
+			s.err = fmt.Errorf("%w: 交易 %s 在 %s 内未确认", ErrTimeout, s.hash.Hex(), elapsed)
+		}
+	}
+}
+
+// result 汇总每笔交易的收据和错误
+func (w *Waiter) result(states []*waitState) ([]*types.Receipt, error) {
+	receipts := make([]*types.Receipt, len(states))
+	var failures []error
+	for i, s := range states {
+		if s.err != nil {
+			failures = append(failures, s.err)
+			continue
+		}
+		receipts[i] = s.receipt
+	}
+	return receipts, errors.Join(failures...)
+}
+
+// watchHeads 返回新区块号的通道, ctx 结束后不再发送
+// 优先订阅新区块, 订阅失败或中断时改为按 PollInterval 轮询
+func (w *Waiter) watchHeads(ctx context.Context) <-chan uint64 {
+	out := make(chan uint64)
+	go func() {
+		if !w.subscribeHeads(ctx, out) {
+			w.pollHeads(ctx, out)
+		}
+	}()
+	return out
+}
+
+// subscribeHeads 订阅新区块并转发区块号, ctx 结束时返回 true, 订阅不可用或中断时返回 false
+func (w *Waiter) subscribeHeads(ctx context.Context, out chan<- uint64) bool {
+	heads := w.config.Heads
+	if heads == nil {
+		heads = w.client
+	}
+	ch := make(chan *types.Header, 16)
+	sub, err := heads.SubscribeNewHead(ctx, ch)
+	if errors.Is(err, rpc.ErrNotificationsUnsupported) && w.config.Heads == nil && w.config.DialWS {
+		// HTTP 客户端不支持订阅, 尝试连接当前网络的 WebSocket 端点
+		var ws *Pool
+		if ws, err = w.dialWS(ctx); err == nil {
+			defer ws.Close()
+			sub, err = ws.SubscribeNewHead(ctx, ch)
+		}
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			return true
+		}
+		if !errors.Is(err, rpc.ErrNotificationsUnsupported) {
+			Logf("订阅新区块失败, 改为每 %s 轮询: %v", w.config.PollInterval, err)
+		}
+		return false
+	}
+	defer sub.Unsubscribe()
+	Logf("已订阅新区块")
+
+	// 订阅建立前产生的区块不会推送, 补发一次当前区块号
+	if head, err := w.client.BlockNumber(ctx); err == nil {
+		select {
+		case out <- head:
+		case <-ctx.Done():
+			return true
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return true
+		case err := <-sub.Err():
+			Logf("新区块订阅中断, 改为每 %s 轮询: %v", w.config.PollInterval, err)
+			return false
+		case header := <-ch:
+			select {
+			case out <- header.Number.Uint64():
+			case <-ctx.Done():
+				return true
+			}
+		}
+	}
+}
+
+// dialWS 连接当前网络的 WebSocket 端点
+func (w *Waiter) dialWS(ctx context.Context) (*Pool, error) {
+	network, err := CurrentNetwork()
+	if err != nil {
+		return nil, err
+	}
+	if len(network.WSURLs) == 0 {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+	return NewNetworkPool(ctx, network, true)
+}
+
+// pollHeads 按 PollInterval 轮询最新区块号
+func (w *Waiter) pollHeads(ctx context.Context, out chan<- uint64) {
+	ticker := time.NewTicker(w.config.PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		head, err := w.client.BlockNumber(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				Logf("获取最新区块失败: %v", errs.Classify(err))
+			}
+			continue
+		}
+		select {
+		case out <- head:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
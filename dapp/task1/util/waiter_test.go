@@ -0,0 +1,201 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// waitClient 模拟链, 只实现 Waiter 使用的区块和交易查询, fork 不同的区块哈希不同
+type waitClient struct {
+	Client
+	mu       sync.Mutex
+	head     uint64
+	blocks   map[uint64]*types.Header // 主链上的区块
+	receipts map[common.Hash]*types.Receipt
+	pool     map[common.Hash]bool // 交易池中的交易
+}
+
+func newWaitClient() *waitClient {
+	return &waitClient{
+		blocks:   make(map[uint64]*types.Header),
+		receipts: make(map[common.Hash]*types.Receipt),
+		pool:     make(map[common.Hash]bool),
+	}
+}
+
+// mine 在 number 处生成 fork 分支的区块并打包 txs, 替换原有区块时移除其中的收据
+func (c *waitClient) mine(number uint64, fork byte, txs ...common.Hash) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	header := &types.Header{Number: new(big.Int).SetUint64(number), Extra: []byte{fork}}
+	for hash, receipt := range c.receipts {
+		if receipt.BlockNumber.Uint64() == number {
+			delete(c.receipts, hash)
+		}
+	}
+	for _, tx := range txs {
+		c.receipts[tx] = &types.Receipt{TxHash: tx, BlockNumber: header.Number, BlockHash: header.Hash(), Status: types.ReceiptStatusSuccessful}
+		delete(c.pool, tx)
+	}
+	c.blocks[number] = header
+	c.head = max(c.head, number)
+}
+
+func (c *waitClient) BlockNumber(ctx context.Context) (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.head, nil
+}
+
+func (c *waitClient) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if header, ok := c.blocks[number.Uint64()]; ok {
+		return header, nil
+	}
+	return nil, ethereum.NotFound
+}
+
+func (c *waitClient) TransactionReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if receipt, ok := c.receipts[hash]; ok {
+		return receipt, nil
+	}
+	return nil, ethereum.NotFound
+}
+
+func (c *waitClient) TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.pool[hash] {
+		return types.NewTx(&types.LegacyTx{}), true, nil
+	}
+	if _, ok := c.receipts[hash]; ok {
+		return types.NewTx(&types.LegacyTx{}), false, nil
+	}
+	return nil, false, ethereum.NotFound
+}
+
+func (c *waitClient) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	return nil, errors.New("websocket: bad handshake")
+}
+
+var waitTx = common.HexToHash("0x01")
+
+// waitStep 修改模拟链后以最新区块调用一次 check
+type waitStep struct {
+	change func(c *waitClient)
+	done   bool
+	err    error // done 为 true 时预期的错误
+}
+
+func TestWaiterCheck(t *testing.T) {
+	tests := []struct {
+		name          string
+		confirmations uint64
+		steps         []waitStep
+	}{
+		{"达到确认数", 3, []waitStep{
+			{change: func(c *waitClient) { c.pool[waitTx] = true }},
+			{change: func(c *waitClient) { c.mine(10, 0, waitTx) }},
+			{change: func(c *waitClient) { c.mine(11, 0) }},
+			{change: func(c *waitClient) { c.mine(12, 0) }, done: true},
+		}},
+		{"收据所在区块被重组后重新打包", 2, []waitStep{
+			{change: func(c *waitClient) { c.mine(10, 0, waitTx) }},
+			// 交易回到交易池, 之前的收据作废
+			{change: func(c *waitClient) { c.mine(10, 1); c.pool[waitTx] = true }},
+			{change: func(c *waitClient) { c.mine(11, 1, waitTx) }},
+			{change: func(c *waitClient) { c.mine(12, 1) }, done: true},
+		}},
+		{"重组后交易被丢弃", 2, []waitStep{
+			{change: func(c *waitClient) { c.mine(10, 0, waitTx) }},
+			{change: func(c *waitClient) { c.mine(10, 1) }},
+			{done: true, err: ErrReorged},
+		}},
+		{"没有发生重组时节点查不到交易继续等待", 1, []waitStep{
+			{},
+			{change: func(c *waitClient) { c.mine(10, 0) }},
+			{change: func(c *waitClient) { c.mine(11, 0, waitTx) }, done: true},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newWaitClient()
+			w := NewWaiter(client, WaitConfig{Confirmations: tt.confirmations})
+			states := []*waitState{{hash: waitTx}}
+			for i, step := range tt.steps {
+				if step.change != nil {
+					step.change(client)
+				}
+				if done := w.check(context.Background(), client.head, states, false); done != step.done {
+					t.Fatalf("第 %d 步: 结束等待为 %v, 预期 %v (%+v)", i+1, done, step.done, states[0])
+				}
+			}
+			s := states[0]
+			if !errors.Is(s.err, tt.steps[len(tt.steps)-1].err) || (s.err == nil) != (tt.steps[len(tt.steps)-1].err == nil) {
+				t.Fatalf("等待结果的错误为 %v, 预期 %v", s.err, tt.steps[len(tt.steps)-1].err)
+			}
+			if s.err != nil {
+				return
+			}
+			// 返回的是主链上的收据
+			if header := client.blocks[s.receipt.BlockNumber.Uint64()]; s.receipt.BlockHash != header.Hash() {
+				t.Fatalf("收据所在区块 %s 不在主链上", s.receipt.BlockHash.Hex())
+			}
+		})
+	}
+}
+
+// TestWaitAny 订阅失败时改为轮询, 竞争的交易中任意一笔确认后立即返回
+func TestWaitAny(t *testing.T) {
+	original, replacement := common.HexToHash("0x01"), common.HexToHash("0x02")
+	client := newWaitClient()
+	client.pool[original] = true
+	client.mine(1, 0)
+	w := NewWaiter(client, WaitConfig{Confirmations: 2, Timeout: 10 * time.Second, PollInterval: 10 * time.Millisecond})
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		client.mine(2, 0, replacement)
+		time.Sleep(50 * time.Millisecond)
+		client.mine(3, 0)
+	}()
+	start := time.Now()
+	receipt, err := w.WaitAny(context.Background(), []common.Hash{original, replacement})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if receipt.TxHash != replacement || receipt.BlockNumber.Uint64() != 2 {
+		t.Fatalf("返回的收据为交易 %s (区块 %d), 预期替换交易", receipt.TxHash.Hex(), receipt.BlockNumber)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("替换交易确认后没有立即返回, 等待了 %s", elapsed)
+	}
+}
+
+func TestWaitTimeout(t *testing.T) {
+	client := newWaitClient()
+	client.pool[waitTx] = true
+	client.mine(1, 0)
+	w := NewWaiter(client, WaitConfig{Timeout: 50 * time.Millisecond, PollInterval: 10 * time.Millisecond})
+	if _, err := w.Wait(context.Background(), waitTx); !errors.Is(err, ErrTimeout) {
+		t.Fatalf("错误为 %v, 预期 %v", err, ErrTimeout)
+	}
+
+	// 调用方取消不属于超时
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := w.Wait(ctx, waitTx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("错误为 %v, 预期 %v", err, context.Canceled)
+	}
+}
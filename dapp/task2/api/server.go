@@ -0,0 +1,339 @@
+// Package api 通过 HTTP/JSON 提供索引数据库中拍卖数据的只读接口
+//
+// 接口只读取 indexer 写入的 SQLite 数据库, 不访问 RPC 节点, 可以和 index 命令分别运行.
+// 所有 JSON 响应都带有按内容计算的 ETag, 客户端通过 If-None-Match 重新验证时内容未变化返回 304;
+// 新出价通过 Server-Sent Events 推送
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"task1/auction"
+	"task1/util"
+	"task2/store"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// 分页参数
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+// Config 接口参数
+type Config struct {
+	CORSOrigin     string        // Access-Control-Allow-Origin, 为空时不允许跨域
+	StreamInterval time.Duration // SSE 检查新出价的间隔
+	Heartbeat      time.Duration // SSE 没有新出价时发送注释行的间隔, 避免代理断开空闲连接
+}
+
+// DefaultConfig 返回默认的接口参数
+func DefaultConfig() Config {
+	return Config{
+		StreamInterval: time.Second,
+		Heartbeat:      15 * time.Second,
+	}
+}
+
+// Server 拍卖数据的 HTTP 接口
+type Server struct {
+	store  *store.Store
+	config Config
+	mux    *http.ServeMux
+}
+
+// New 创建 HTTP 接口
+func New(st *store.Store, config Config) *Server {
+	s := &Server{store: st, config: config, mux: http.NewServeMux()}
+	s.mux.HandleFunc("GET /auctions", s.handleAuctions)
+	s.mux.HandleFunc("GET /auctions/{id}", s.handleAuction)
+	s.mux.HandleFunc("GET /auctions/{id}/bids", s.handleBids)
+	s.mux.HandleFunc("GET /sellers/{addr}", s.handleSeller)
+	s.mux.HandleFunc("GET /fees", s.handleFees)
+	s.mux.HandleFunc("GET /bids/stream", s.handleBidStream)
+	s.mux.HandleFunc("GET /status", s.handleStatus)
+	return s
+}
+
+// ServeHTTP 实现 http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.config.CORSOrigin != "" {
+		w.Header().Set("Access-Control-Allow-Origin", s.config.CORSOrigin)
+		w.Header().Set("Access-Control-Expose-Headers", "ETag")
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+// PageResponse 分页响应
+type PageResponse[T any] struct {
+	Items  []T   `json:"items"`
+	Total  int64 `json:"total"`
+	Limit  int   `json:"limit"`
+	Offset int   `json:"offset"`
+}
+
+// SellerResponse 卖家的拍卖统计和拍卖列表
+type SellerResponse struct {
+	Seller   string                           `json:"seller"`
+	Counts   map[string]int64                 `json:"counts"` // 各状态的拍卖数量
+	Auctions PageResponse[*store.AuctionView] `json:"auctions"`
+}
+
+// FeesResponse 手续费汇总和收取记录
+type FeesResponse struct {
+	*store.Fees
+	Collections PageResponse[*store.FeeCollection] `json:"collections"`
+}
+
+// handleAuctions GET /auctions?status=&seller=&limit=&offset=
+func (s *Server) handleAuctions(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseFilter(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if seller := r.URL.Query().Get("seller"); seller != "" {
+		address, err := parseAddress(seller)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		filter.Seller = &address
+	}
+	page, err := parsePage(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	now, err := s.store.HeadTime(r.Context())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	auctions, total, err := s.store.Auctions(r.Context(), filter, page, now)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, r, newPage(auctions, total, page))
+}
+
+// handleAuction GET /auctions/{id}
+func (s *Server) handleAuction(w http.ResponseWriter, r *http.Request) {
+	view, ok := s.loadAuction(w, r)
+	if ok {
+		writeJSON(w, r, view)
+	}
+}
+
+// handleBids GET /auctions/{id}/bids?limit=&offset=
+func (s *Server) handleBids(w http.ResponseWriter, r *http.Request) {
+	page, err := parsePage(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	view, ok := s.loadAuction(w, r)
+	if !ok {
+		return
+	}
+	bids, total, err := s.store.Bids(r.Context(), view.ID, page)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, r, newPage(bids, total, page))
+}
+
+// handleSeller GET /sellers/{addr}?status=&limit=&offset=
+func (s *Server) handleSeller(w http.ResponseWriter, r *http.Request) {
+	seller, err := parseAddress(r.PathValue("addr"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	filter, err := parseFilter(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	filter.Seller = &seller
+	page, err := parsePage(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	now, err := s.store.HeadTime(r.Context())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	counts, err := s.store.StatusCounts(r.Context(), seller, now)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	auctions, total, err := s.store.Auctions(r.Context(), filter, page, now)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, r, &SellerResponse{Seller: seller.Hex(), Counts: counts, Auctions: newPage(auctions, total, page)})
+}
+
+// handleFees GET /fees?limit=&offset=
+func (s *Server) handleFees(w http.ResponseWriter, r *http.Request) {
+	page, err := parsePage(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	fees, err := s.store.Fees(r.Context())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	collections, total, err := s.store.FeeCollections(r.Context(), page)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, r, &FeesResponse{Fees: fees, Collections: newPage(collections, total, page)})
+}
+
+// handleStatus GET /status 返回索引进度, 可用于健康检查
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	status, err := s.store.Status(r.Context())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if status == nil {
+		writeErrorStatus(w, http.StatusServiceUnavailable, "数据库还没有索引任何合约")
+		return
+	}
+	writeJSON(w, r, status)
+}
+
+// loadAuction 读取路径中的拍卖, 失败时写入错误响应并返回 false
+func (s *Server) loadAuction(w http.ResponseWriter, r *http.Request) (*store.AuctionView, bool) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil || id < 0 {
+		writeError(w, fmt.Errorf("%w: 拍卖 ID %q 格式错误", util.ErrInvalidArgument, r.PathValue("id")))
+		return nil, false
+	}
+	now, err := s.store.HeadTime(r.Context())
+	if err != nil {
+		writeError(w, err)
+		return nil, false
+	}
+	view, err := s.store.Auction(r.Context(), id, now)
+	if err != nil {
+		writeError(w, err)
+		return nil, false
+	}
+	if view == nil {
+		writeErrorStatus(w, http.StatusNotFound, fmt.Sprintf("拍卖 %d 不存在", id))
+		return nil, false
+	}
+	return view, true
+}
+
+func newPage[T any](items []T, total int64, page store.Page) PageResponse[T] {
+	return PageResponse[T]{Items: items, Total: total, Limit: page.Limit, Offset: page.Offset}
+}
+
+// parsePage 解析 limit 和 offset 参数
+func parsePage(r *http.Request) (store.Page, error) {
+	page := store.Page{Limit: DefaultLimit}
+	query := r.URL.Query()
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > MaxLimit {
+			return page, fmt.Errorf("%w: limit 需要是 1 - %d 的整数: %q", util.ErrInvalidArgument, MaxLimit, value)
+		}
+		page.Limit = limit
+	}
+	if value := query.Get("offset"); value != "" {
+		offset, err := strconv.Atoi(value)
+		if err != nil || offset < 0 {
+			return page, fmt.Errorf("%w: offset 需要是非负整数: %q", util.ErrInvalidArgument, value)
+		}
+		page.Offset = offset
+	}
+	return page, nil
+}
+
+// parseFilter 解析 status 参数
+func parseFilter(r *http.Request) (store.AuctionFilter, error) {
+	var filter store.AuctionFilter
+	filter.Status = r.URL.Query().Get("status")
+	if filter.Status != "" && !slices.Contains(auction.Statuses(), filter.Status) {
+		return filter, fmt.Errorf("%w: 未知的拍卖状态 %q, 可选: %s", util.ErrInvalidArgument, filter.Status, strings.Join(auction.Statuses(), ", "))
+	}
+	return filter, nil
+}
+
+func parseAddress(value string) (common.Address, error) {
+	if !common.IsHexAddress(value) {
+		return common.Address{}, fmt.Errorf("%w: 地址 %q 格式错误", util.ErrInvalidArgument, value)
+	}
+	return common.HexToAddress(value), nil
+}
+
+// writeJSON 输出 JSON 响应, 请求的 If-None-Match 与内容的 ETag 相同时返回 304
+// ETag 按响应内容计算, 索引新事件、重组回滚和拍卖到期都会改变内容, 不需要单独维护版本号
+func writeJSON(w http.ResponseWriter, r *http.Request, v any) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "no-cache")
+	if matchETag(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Write(append(body, '\n'))
+}
+
+// matchETag 判断 If-None-Match 是否包含 etag, 按 RFC 9110 使用弱比较
+func matchETag(header, etag string) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// writeError 输出错误响应, 参数错误返回 400, 其他错误返回 500
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	if errors.Is(err, util.ErrInvalidArgument) {
+		status = http.StatusBadRequest
+	} else {
+		util.Logf("%s", err)
+	}
+	writeErrorStatus(w, status, err.Error())
+}
+
+func writeErrorStatus(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": msg})
+}
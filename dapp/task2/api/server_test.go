@@ -0,0 +1,406 @@
+package api_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"task1/auction"
+	"task1/auction/auctiontest"
+	"task1/contracts"
+	"task2/api"
+	"task2/indexer"
+	"task2/store"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// 账户: 0 合约所有者, 1 卖家, 2 出价者
+const (
+	seller = 1
+	bidder = 2
+)
+
+// env 模拟链、拍卖服务、索引器和 HTTP 接口
+type env struct {
+	chain   *auctiontest.Chain
+	service *auction.Service
+	indexer *indexer.Indexer
+	server  *httptest.Server
+}
+
+func newEnv(t *testing.T) *env {
+	t.Helper()
+	ctx := context.Background()
+	chain := auctiontest.New(t, 3)
+	deployments := contracts.OpenDeployments(filepath.Join(t.TempDir(), "deployments.json"))
+	service, err := auction.NewService(ctx, chain.Client, deployments, chain.Auction.Hex())
+	if err != nil {
+		t.Fatal(err)
+	}
+	st, err := store.Open(filepath.Join(t.TempDir(), "auction.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { st.Close() })
+	config := indexer.DefaultConfig()
+	config.Contract = chain.Auction
+	ix, err := indexer.New(ctx, chain.Client, st, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	apiConfig := api.DefaultConfig()
+	apiConfig.StreamInterval = 10 * time.Millisecond
+	server := httptest.NewServer(api.New(st, apiConfig))
+	t.Cleanup(server.Close)
+	return &env{chain: chain, service: service, indexer: ix, server: server}
+}
+
+// sync 索引到模拟链的最新区块
+func (e *env) sync(t *testing.T) {
+	t.Helper()
+	if err := e.indexer.Sync(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// create 卖家铸造 NFT 并创建 ETH 计价的拍卖, 返回拍卖 ID
+func (e *env) create(t *testing.T, tokenID int64, duration time.Duration) int64 {
+	t.Helper()
+	e.chain.MintNFT(t, e.chain.Accounts[seller], tokenID)
+	e.chain.Use(seller)
+	_, id, err := e.service.Create(context.Background(), e.chain.NFT, big.NewInt(tokenID), ether(1), false, duration)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return id.Int64()
+}
+
+func (e *env) bid(t *testing.T, id int64, amount *big.Int) {
+	t.Helper()
+	e.chain.Use(bidder)
+	if _, err := e.service.Bid(context.Background(), big.NewInt(id), amount, false); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// get 请求接口, 状态码为 200 时将响应解码到 v, 返回响应
+func (e *env) get(t *testing.T, path, etag string, v any) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, e.server.URL+path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK && v != nil {
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return resp
+}
+
+func ether(n int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(n), big.NewInt(params.Ether))
+}
+
+func ids(auctions []*store.AuctionView) []int64 {
+	res := []int64{}
+	for _, a := range auctions {
+		res = append(res, a.ID)
+	}
+	return res
+}
+
+func TestAuctionsPage(t *testing.T) {
+	e := newEnv(t)
+	for i := range int64(3) {
+		e.create(t, i+1, 2*time.Hour)
+	}
+	e.bid(t, 1, ether(2))
+	e.sync(t)
+
+	var page api.PageResponse[*store.AuctionView]
+	if resp := e.get(t, "/auctions?limit=2", "", &page); resp.StatusCode != http.StatusOK {
+		t.Fatalf("状态码 %d", resp.StatusCode)
+	}
+	if got := fmt.Sprint(ids(page.Items)); page.Total != 3 || page.Limit != 2 || page.Offset != 0 || got != "[2 1]" {
+		t.Fatalf("第一页: total=%d limit=%d offset=%d ids=%s", page.Total, page.Limit, page.Offset, got)
+	}
+	if a := page.Items[1]; a.Bids != 1 || a.HighestBid != ether(2).String() || a.HighestBidder != e.chain.Accounts[bidder].Hex() {
+		t.Fatalf("拍卖 1: bids=%d highestBid=%s highestBidder=%s", a.Bids, a.HighestBid, a.HighestBidder)
+	}
+	e.get(t, "/auctions?limit=2&offset=2", "", &page)
+	if got := fmt.Sprint(ids(page.Items)); page.Total != 3 || page.Offset != 2 || got != "[0]" {
+		t.Fatalf("第二页: total=%d offset=%d ids=%s", page.Total, page.Offset, got)
+	}
+
+	var bids api.PageResponse[*store.Bid]
+	e.get(t, "/auctions/1/bids", "", &bids)
+	if bids.Total != 1 || len(bids.Items) != 1 || bids.Items[0].Bidder != e.chain.Accounts[bidder].Hex() || bids.Items[0].Amount != ether(2).String() {
+		t.Fatalf("拍卖 1 的出价: %+v", bids)
+	}
+	e.get(t, "/auctions/0/bids", "", &bids)
+	if bids.Total != 0 || len(bids.Items) != 0 {
+		t.Fatalf("拍卖 0 的出价: %+v", bids)
+	}
+
+	for path, status := range map[string]int{
+		"/auctions?limit=0":         http.StatusBadRequest,
+		"/auctions?limit=101":       http.StatusBadRequest,
+		"/auctions?offset=-1":       http.StatusBadRequest,
+		"/auctions/3":               http.StatusNotFound,
+		"/auctions/x":               http.StatusBadRequest,
+		"/sellers/0x1234":           http.StatusBadRequest,
+		"/auctions?status=finished": http.StatusBadRequest,
+	} {
+		if resp := e.get(t, path, "", nil); resp.StatusCode != status {
+			t.Errorf("%s: 状态码 %d, 预期 %d", path, resp.StatusCode, status)
+		}
+	}
+}
+
+func TestAuctionsStatus(t *testing.T) {
+	ctx := context.Background()
+	e := newEnv(t)
+	e.create(t, 1, 2*time.Hour)
+	e.create(t, 2, 4*time.Hour)
+	e.create(t, 3, 4*time.Hour)
+	e.chain.Use(seller)
+	if _, err := e.service.Cancel(ctx, big.NewInt(2)); err != nil {
+		t.Fatal(err)
+	}
+	e.sync(t)
+
+	list := func(status string) string {
+		t.Helper()
+		var page api.PageResponse[*store.AuctionView]
+		if resp := e.get(t, "/auctions?status="+status, "", &page); resp.StatusCode != http.StatusOK {
+			t.Fatalf("status=%s: 状态码 %d", status, resp.StatusCode)
+		}
+		return fmt.Sprint(ids(page.Items))
+	}
+	if got := list(auction.StatusActive); got != "[1 0]" {
+		t.Fatalf("进行中的拍卖: %s", got)
+	}
+	if got := list(auction.StatusEnded); got != "[2]" {
+		t.Fatalf("已结束的拍卖: %s", got)
+	}
+
+	// 状态按已索引的最新区块时间判断, 链上时间前进但还没有索引时拍卖 0 仍在进行中
+	e.chain.AdjustTime(t, 3*time.Hour)
+	if got := list(auction.StatusExpired); got != "[]" {
+		t.Fatalf("索引新区块前到期的拍卖: %s", got)
+	}
+	e.sync(t)
+	if got := list(auction.StatusExpired); got != "[0]" {
+		t.Fatalf("到期的拍卖: %s", got)
+	}
+	if got := list(auction.StatusActive); got != "[1]" {
+		t.Fatalf("进行中的拍卖: %s", got)
+	}
+
+	var view store.AuctionView
+	e.get(t, "/auctions/2", "", &view)
+	if view.Status != auction.StatusEnded || !view.Cancelled {
+		t.Fatalf("取消的拍卖: status=%s cancelled=%v", view.Status, view.Cancelled)
+	}
+	var sellerView api.SellerResponse
+	e.get(t, "/sellers/"+e.chain.Accounts[seller].Hex()+"?status="+auction.StatusExpired, "", &sellerView)
+	counts := sellerView.Counts
+	if counts[auction.StatusActive] != 1 || counts[auction.StatusExpired] != 1 || counts[auction.StatusEnded] != 1 {
+		t.Fatalf("卖家的拍卖数量: %v", counts)
+	}
+	if got := fmt.Sprint(ids(sellerView.Auctions.Items)); got != "[0]" {
+		t.Fatalf("卖家到期的拍卖: %s", got)
+	}
+}
+
+// TestAuctionEndTimeBoundary 已索引的最新区块时间等于结束时间时仍可出价, 状态为进行中, 之后才到期
+func TestAuctionEndTimeBoundary(t *testing.T) {
+	ctx := context.Background()
+	e := newEnv(t)
+	e.create(t, 1, 2*time.Hour)
+	e.sync(t)
+	var view store.AuctionView
+	e.get(t, "/auctions/0", "", &view)
+	head, err := e.chain.Client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Backend.AdjustTime 出一个时间为 父区块时间 + d 的块
+	if err := e.chain.Backend.AdjustTime(time.Duration(view.EndTime-head.Time) * time.Second); err != nil {
+		t.Fatal(err)
+	}
+	e.sync(t)
+	if head, err = e.chain.Client.HeaderByNumber(ctx, nil); err != nil || head.Time != view.EndTime {
+		t.Fatalf("最新区块时间为 %d, %v, 预期等于结束时间 %d", head.Time, err, view.EndTime)
+	}
+	if e.get(t, "/auctions/0", "", &view); view.Status != auction.StatusActive {
+		t.Fatalf("区块时间等于结束时间时的状态为 %s, 预期 %s", view.Status, auction.StatusActive)
+	}
+
+	if err := e.chain.Backend.AdjustTime(time.Second); err != nil {
+		t.Fatal(err)
+	}
+	e.sync(t)
+	if e.get(t, "/auctions/0", "", &view); view.Status != auction.StatusExpired {
+		t.Fatalf("超过结束时间后的状态为 %s, 预期 %s", view.Status, auction.StatusExpired)
+	}
+}
+
+func TestETag(t *testing.T) {
+	e := newEnv(t)
+	e.create(t, 1, 2*time.Hour)
+	e.sync(t)
+
+	resp := e.get(t, "/auctions/0", "", nil)
+	etag := resp.Header.Get("ETag")
+	if resp.StatusCode != http.StatusOK || etag == "" || resp.Header.Get("Cache-Control") != "no-cache" {
+		t.Fatalf("状态码 %d, ETag %q, Cache-Control %q", resp.StatusCode, etag, resp.Header.Get("Cache-Control"))
+	}
+	for _, header := range []string{etag, "W/" + etag, `"other", ` + etag, "*"} {
+		if resp := e.get(t, "/auctions/0", header, nil); resp.StatusCode != http.StatusNotModified {
+			t.Fatalf("If-None-Match %s: 状态码 %d", header, resp.StatusCode)
+		}
+	}
+
+	// 索引新出价后内容变化, 旧的 ETag 不再匹配
+	e.bid(t, 0, ether(2))
+	e.sync(t)
+	var view store.AuctionView
+	resp = e.get(t, "/auctions/0", etag, &view)
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("ETag") == etag {
+		t.Fatalf("出价后: 状态码 %d, ETag %q", resp.StatusCode, resp.Header.Get("ETag"))
+	}
+	if view.Bids != 1 {
+		t.Fatalf("出价后: bids=%d", view.Bids)
+	}
+	if resp := e.get(t, "/auctions/0", resp.Header.Get("ETag"), nil); resp.StatusCode != http.StatusNotModified {
+		t.Fatalf("新的 ETag: 状态码 %d", resp.StatusCode)
+	}
+}
+
+// event 一个 Server-Sent Event
+type event struct {
+	name, id, data string
+}
+
+// stream 连接出价推送, 返回接收事件的 channel, 测试结束时断开连接
+func (e *env) stream(t *testing.T, query string) <-chan event {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, e.server.URL+"/bids/stream"+query, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("状态码 %d, Content-Type %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	events := make(chan event, 16)
+	go func() {
+		defer resp.Body.Close()
+		defer close(events)
+		var ev event
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			key, value, _ := strings.Cut(scanner.Text(), ": ")
+			switch key {
+			case "event":
+				ev.name = value
+			case "id":
+				ev.id = value
+			case "data":
+				ev.data = value
+			case "":
+				// 空行结束一个事件, 注释行 (": keep-alive") 没有事件名
+				if ev.name != "" {
+					events <- ev
+				}
+				ev = event{}
+			}
+		}
+	}()
+	return events
+}
+
+func next(t *testing.T, events <-chan event) event {
+	t.Helper()
+	select {
+	case ev, ok := <-events:
+		if !ok {
+			t.Fatal("推送连接已断开")
+		}
+		return ev
+	case <-time.After(10 * time.Second):
+		t.Fatal("等待推送超时")
+	}
+	return event{}
+}
+
+func TestBidStream(t *testing.T) {
+	ctx := context.Background()
+	e := newEnv(t)
+	e.create(t, 1, 2*time.Hour)
+	e.create(t, 2, 2*time.Hour)
+	e.sync(t)
+
+	events := e.stream(t, "?auction=1")
+	e.bid(t, 0, ether(2))
+	e.bid(t, 1, ether(3))
+	e.sync(t)
+
+	// 只推送拍卖 1 的出价
+	ev := next(t, events)
+	var bid store.Bid
+	if ev.name != "bid" {
+		t.Fatalf("事件 %+v, 预期 bid", ev)
+	}
+	if err := json.Unmarshal([]byte(ev.data), &bid); err != nil {
+		t.Fatal(err)
+	}
+	if bid.AuctionID != 1 || bid.Bidder != e.chain.Accounts[bidder].Hex() || bid.Amount != ether(3).String() || bid.BidToken != (common.Address{}).Hex() {
+		t.Fatalf("出价 %+v", bid)
+	}
+	if ev.id != fmt.Sprintf("%d-%d", bid.Block, bid.LogIndex) {
+		t.Fatalf("事件 ID %q, 出价位置 %d-%d", ev.id, bid.Block, bid.LogIndex)
+	}
+
+	// 从出价区块的父区块分叉出更长的链, 索引器回滚后推送 reorg 事件, data 为共同区块号
+	parent, err := e.chain.Client.HeaderByNumber(ctx, new(big.Int).SetUint64(bid.Block-1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := e.chain.Backend.Fork(parent.Hash()); err != nil {
+		t.Fatal(err)
+	}
+	e.chain.Backend.Commit()
+	e.chain.Backend.Commit()
+	e.sync(t)
+	ev = next(t, events)
+	if ev.name != "reorg" || ev.data != strconv.FormatUint(parent.Number.Uint64(), 10) {
+		t.Fatalf("事件 %+v, 预期 reorg %d", ev, parent.Number)
+	}
+}
@@ -0,0 +1,142 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"task1/util"
+	"task2/store"
+	"time"
+)
+
+// streamBatch 每次检查最多推送的出价数, 其余的在下一次检查时推送
+const streamBatch = 100
+
+// handleBidStream GET /bids/stream?auction= 以 Server-Sent Events 推送新出价
+//
+// 每个出价是一个 bid 事件, id 为 "区块号-日志索引", 断线重连时浏览器通过 Last-Event-ID 从断点继续;
+// 索引器回滚重组的区块后先推送 reorg 事件 (data 为共同区块号), 之后重新推送新链上的出价
+func (s *Server) handleBidStream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeErrorStatus(w, http.StatusInternalServerError, "连接不支持流式响应")
+		return
+	}
+	var auctionID *int64
+	if value := r.URL.Query().Get("auction"); value != "" {
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || id < 0 {
+			writeError(w, fmt.Errorf("%w: 拍卖 ID %q 格式错误", util.ErrInvalidArgument, value))
+			return
+		}
+		auctionID = &id
+	}
+	stream := &bidStream{auctionID: auctionID}
+	var err error
+	// 从连接时的回滚记录开始检查回滚, 没有 Last-Event-ID 时只推送连接之后的新出价
+	if stream.reorg, err = s.store.LastReorg(r.Context()); err != nil {
+		writeError(w, err)
+		return
+	}
+	cursor, err := parseEventID(r.Header.Get("Last-Event-ID"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if cursor != nil {
+		stream.cursor = *cursor
+	} else if stream.cursor, err = s.store.LastBid(r.Context()); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ticker := time.NewTicker(s.config.StreamInterval)
+	defer ticker.Stop()
+	lastWrite := time.Now()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+		}
+		wrote, err := s.pushBids(w, r, stream)
+		if err != nil {
+			if r.Context().Err() == nil {
+				util.Logf("推送新出价失败: %v", err)
+			}
+			return
+		}
+		if !wrote && time.Since(lastWrite) < s.config.Heartbeat {
+			continue
+		}
+		if !wrote {
+			fmt.Fprint(w, ": keep-alive\n\n")
+		}
+		lastWrite = time.Now()
+		flusher.Flush()
+	}
+}
+
+// bidStream 一个 SSE 连接的推送进度
+type bidStream struct {
+	auctionID *int64         // 只推送该拍卖的出价, nil 表示所有拍卖
+	cursor    store.Position // 最后推送的出价位置
+	reorg     int64          // 已处理的最新回滚编号
+}
+
+// pushBids 推送 cursor 之后的出价并推进 cursor, 返回是否写入了数据
+func (s *Server) pushBids(w http.ResponseWriter, r *http.Request, stream *bidStream) (bool, error) {
+	wrote := false
+	// 索引器可能在两次检查之间回滚并重新索引, 通过回滚记录发现已推送的出价被回滚, 从共同区块之后重新推送
+	id, number, ok, err := s.store.ReorgsAfter(r.Context(), stream.reorg)
+	if err != nil {
+		return false, err
+	}
+	if ok {
+		stream.reorg = id
+		if number < stream.cursor.Block {
+			stream.cursor = store.Position{Block: number, LogIndex: math.MaxInt32}
+			fmt.Fprintf(w, "event: reorg\ndata: %d\n\n", number)
+			wrote = true
+		}
+	}
+	bids, err := s.store.BidsAfter(r.Context(), stream.auctionID, stream.cursor, streamBatch)
+	if err != nil {
+		return wrote, err
+	}
+	for _, bid := range bids {
+		data, err := json.Marshal(bid)
+		if err != nil {
+			return wrote, err
+		}
+		if _, err := fmt.Fprintf(w, "id: %d-%d\nevent: bid\ndata: %s\n\n", bid.Block, bid.LogIndex, data); err != nil {
+			return wrote, err
+		}
+		stream.cursor = store.Position{Block: bid.Block, LogIndex: bid.LogIndex}
+		wrote = true
+	}
+	return wrote, nil
+}
+
+// parseEventID 解析 "区块号-日志索引" 格式的 Last-Event-ID, 为空时返回 nil
+func parseEventID(value string) (*store.Position, error) {
+	if value == "" {
+		return nil, nil
+	}
+	block, index, ok := strings.Cut(value, "-")
+	number, err1 := strconv.ParseUint(block, 10, 63)
+	logIndex, err2 := strconv.ParseUint(index, 10, 31)
+	if !ok || err1 != nil || err2 != nil {
+		return nil, fmt.Errorf("%w: Last-Event-ID %q 格式错误, 需要是 区块号-日志索引", util.ErrInvalidArgument, value)
+	}
+	return &store.Position{Block: number, LogIndex: uint(logIndex)}, nil
+}
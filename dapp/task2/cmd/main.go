@@ -0,0 +1,327 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"task1/auction"
+	"task1/contracts"
+	"task1/errs"
+	"task1/util"
+	"task2/api"
+	"task2/indexer"
+	"task2/store"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// init 初始化命令行标志和命令
+func init() {
+	// 设置根命令的持久标志, 网络和代理配置与 task1 共用同一个环境文件
+	rootCmd.PersistentFlags().StringP("env-file", "e", "", "指定环境变量文件路径 (默认: .env)")
+	rootCmd.PersistentFlags().String("proxy", "", "代理地址, 支持 http/https/socks5, none 表示直连 (默认: 环境文件 PROXY 或系统 HTTPS_PROXY)")
+	rootCmd.PersistentFlags().StringP("network", "n", "", fmt.Sprintf("指定网络配置 (可选: %s, 默认: 环境变量 NETWORK 或 %s)", strings.Join(util.NetworkNames(), ", "), util.DEFAULT_NETWORK))
+	rootCmd.PersistentFlags().String("db", "", "事件数据库文件 (默认: 环境文件 INDEX_DB 或 "+store.DefaultPath+")")
+
+	// 设置索引命令的标志
+	defaults := indexer.DefaultConfig()
+	indexCmd.Flags().String("deployments", "", "部署记录文件 (默认: 环境文件 DEPLOYMENTS_FILE 或 "+contracts.DefaultDeploymentsFile+")")
+	indexCmd.Flags().String("auction", auction.AuctionName, "拍卖合约地址或部署记录中的合约名")
+	indexCmd.Flags().Uint64("start-block", 0, "首次索引的起始区块, 数据库已有进度时忽略 (默认: 部署记录中的部署区块, 直接指定地址时为 0)")
+	indexCmd.Flags().Uint64("chunk-size", defaults.ChunkSize, "每次 eth_getLogs 查询的区块数, 节点拒绝时自动减半")
+	indexCmd.Flags().Uint64("confirmations", defaults.Confirmations, "只索引到 最新区块 - N, 减少需要回滚的重组")
+	indexCmd.Flags().Duration("poll-interval", defaults.PollInterval, "追上最新区块后轮询新事件的间隔")
+	indexCmd.Flags().Uint64("reorg-window", defaults.ReorgWindow, "保留最近多少个区块的哈希用于检测重组")
+	indexCmd.Flags().Bool("once", false, "索引到最新区块后退出, 不继续轮询")
+
+	// 设置 HTTP 接口命令的标志
+	serveCmd.Flags().String("addr", ":8080", "HTTP 监听地址")
+	serveCmd.Flags().String("cors-origin", "", "允许跨域访问的来源, 如 http://localhost:3000 或 * (默认: 不允许跨域)")
+
+	// 将子命令添加到根命令
+	rootCmd.AddCommand(indexCmd)
+	rootCmd.AddCommand(statusCmd)
+	rootCmd.AddCommand(serveCmd)
+}
+
+// 退出码, 与 task1 一致
+const (
+	exitError       = 1 // 其他错误
+	exitUsage       = 2 // 参数错误
+	exitConfig      = 3 // 配置错误: 环境文件、网络、代理、数据库
+	exitNetwork     = 4 // 没有可用的 RPC 端点或节点持续返回网络、限流、鉴权错误
+	exitNotDeployed = 8 // 合约未部署
+)
+
+// exitCode 根据错误类型返回退出码
+func exitCode(err error) int {
+	switch {
+	case errors.Is(err, util.ErrInvalidArgument):
+		return exitUsage
+	case errors.Is(err, util.ErrConfig), errors.Is(err, store.ErrMismatch):
+		return exitConfig
+	case errors.Is(err, util.ErrNoHealthyEndpoint):
+		return exitNetwork
+	case errors.Is(err, util.ErrNotDeployed):
+		return exitNotDeployed
+	}
+	switch errs.KindOf(err) {
+	case errs.ErrNetwork, errs.ErrTimeout, errs.ErrRateLimited, errs.ErrUnavailable, errs.ErrUnauthorized:
+		return exitNetwork
+	}
+	return exitError
+}
+
+func main() {
+	// 库内部的进度日志输出到标准日志
+	util.SetLogger(log.Default())
+
+	// 参数解析错误归类为参数错误
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return fmt.Errorf("%w: %w", util.ErrInvalidArgument, err)
+	})
+
+	// 在执行命令前处理环境文件配置
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		envFile, err := cmd.Flags().GetString("env-file")
+		if err != nil {
+			return fmt.Errorf("获取环境文件参数错误: %w", err)
+		}
+		if envFile != "" {
+			if err := util.ValidateEnvFile(envFile); err != nil {
+				return fmt.Errorf("%w: 环境文件验证失败: %w", util.ErrConfig, err)
+			}
+			log.Printf("使用自定义环境文件: %s", envFile)
+		}
+		if err := util.InitConfig(envFile); err != nil {
+			return fmt.Errorf("初始化配置失败: %w", err)
+		}
+
+		// 命令行指定的代理优先于环境配置
+		proxy, err := cmd.Flags().GetString("proxy")
+		if err != nil {
+			return fmt.Errorf("获取代理参数错误: %w", err)
+		}
+		util.SetProxy(proxy)
+		if _, err := util.LoadProxyConfig(); err != nil {
+			return fmt.Errorf("%w: 代理配置错误: %w", util.ErrConfig, err)
+		}
+
+		networkName, err := cmd.Flags().GetString("network")
+		if err != nil {
+			return fmt.Errorf("获取网络参数错误: %w", err)
+		}
+		if err := util.SetNetwork(networkName); err != nil {
+			return fmt.Errorf("选择网络失败: %w", err)
+		}
+		return nil
+	}
+
+	// Ctrl+C 时停止索引, 已写入的进度不会丢失
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	err := rootCmd.ExecuteContext(ctx)
+	stop()
+	if err != nil {
+		log.Print("命令执行错误: ", err)
+		os.Exit(exitCode(err))
+	}
+}
+
+// openStore 打开 --db 指定的事件数据库, 未指定时使用环境文件的 INDEX_DB
+func openStore(cmd *cobra.Command) (*store.Store, error) {
+	path, err := cmd.Flags().GetString("db")
+	if err != nil {
+		return nil, fmt.Errorf("获取数据库参数错误: %w", err)
+	}
+	if path == "" {
+		path = util.LoadEnv("<INDEX_DB>")
+	}
+	return store.Open(path)
+}
+
+// indexConfig 读取索引命令的参数, 并解析拍卖合约地址和起始区块
+func indexConfig(cmd *cobra.Command, client util.Client) (indexer.Config, error) {
+	config := indexer.DefaultConfig()
+	var err error
+	if config.ChunkSize, err = cmd.Flags().GetUint64("chunk-size"); err != nil {
+		return config, fmt.Errorf("获取查询区块数参数错误: %w", err)
+	}
+	if config.Confirmations, err = cmd.Flags().GetUint64("confirmations"); err != nil {
+		return config, fmt.Errorf("获取确认数参数错误: %w", err)
+	}
+	if config.PollInterval, err = cmd.Flags().GetDuration("poll-interval"); err != nil {
+		return config, fmt.Errorf("获取轮询间隔参数错误: %w", err)
+	}
+	if config.ReorgWindow, err = cmd.Flags().GetUint64("reorg-window"); err != nil {
+		return config, fmt.Errorf("获取重组检查参数错误: %w", err)
+	}
+
+	target, err := cmd.Flags().GetString("auction")
+	if err != nil {
+		return config, fmt.Errorf("获取拍卖合约参数错误: %w", err)
+	}
+	path, err := cmd.Flags().GetString("deployments")
+	if err != nil {
+		return config, fmt.Errorf("获取部署记录文件参数错误: %w", err)
+	}
+	if path == "" {
+		path = util.LoadEnv("<DEPLOYMENTS_FILE>")
+	}
+	address, deployment, err := contracts.OpenDeployments(path).Resolve(cmd.Context(), client, target)
+	if err != nil {
+		return config, err
+	}
+	config.Contract = address
+
+	// 默认从部署区块开始回填, 避免扫描部署之前的区块
+	if cmd.Flags().Changed("start-block") {
+		if config.StartBlock, err = cmd.Flags().GetUint64("start-block"); err != nil {
+			return config, fmt.Errorf("获取起始区块参数错误: %w", err)
+		}
+	} else if deployment != nil {
+		config.StartBlock = deployment.Block
+	}
+	return config, nil
+}
+
+var rootCmd = &cobra.Command{
+	Use:   "task2",
+	Short: "NFTAuction 事件索引服务",
+	Long:  "将 NFTAuction 合约事件索引到本地 SQLite 数据库, 支持分段回填、持续跟踪、重组回滚和断点续传, 并通过 HTTP/JSON 接口提供查询",
+	// 错误由 main 统一输出
+	SilenceErrors: true,
+	SilenceUsage:  true,
+}
+
+var indexCmd = &cobra.Command{
+	Use:   "index",
+	Short: "索引拍卖合约事件",
+	Long: `从起始区块开始按 --chunk-size 分段回填拍卖合约的事件, 追上最新区块后每隔 --poll-interval 轮询新事件.
+重启后从数据库中的进度继续; 已索引区块的哈希变化时回滚到分叉点重新索引.`,
+	Example: `  task2 index -n sepolia
+  task2 index --auction 0x... --start-block 5000000 --db data/auction.db
+  task2 index --once`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		once, err := cmd.Flags().GetBool("once")
+		if err != nil {
+			return fmt.Errorf("获取 once 参数错误: %w", err)
+		}
+		client, err := util.LoadClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+		config, err := indexConfig(cmd, client)
+		if err != nil {
+			return err
+		}
+		st, err := openStore(cmd)
+		if err != nil {
+			return err
+		}
+		defer st.Close()
+
+		ix, err := indexer.New(cmd.Context(), client, st, config)
+		if err != nil {
+			return err
+		}
+		next, err := st.Cursor(cmd.Context())
+		if err != nil {
+			return err
+		}
+		log.Printf("开始索引拍卖合约 %s, 从区块 %d 开始", config.Contract.Hex(), next)
+		if once {
+			return ix.Sync(cmd.Context())
+		}
+		return ix.Run(cmd.Context())
+	},
+}
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "查看索引进度",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		st, err := openStore(cmd)
+		if err != nil {
+			return err
+		}
+		defer st.Close()
+		status, err := st.Status(cmd.Context())
+		if err != nil {
+			return err
+		}
+		if status == nil {
+			return fmt.Errorf("%w: 数据库还没有索引任何合约, 请先运行 index 命令", util.ErrConfig)
+		}
+		data, err := json.MarshalIndent(status, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	},
+}
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "启动拍卖数据的 HTTP/JSON 只读接口",
+	Long: `读取 index 命令写入的数据库, 提供以下接口, 不访问 RPC 节点:
+  GET /auctions?status=&seller=&limit=&offset=  拍卖列表, status 可选 active / expired / ended
+  GET /auctions/{id}                            拍卖详情
+  GET /auctions/{id}/bids?limit=&offset=        拍卖的出价记录
+  GET /sellers/{addr}?status=&limit=&offset=    卖家的拍卖统计和拍卖列表
+  GET /fees?limit=&offset=                      手续费汇总和收取记录
+  GET /bids/stream?auction=                     新出价的 Server-Sent Events 推送
+  GET /status                                   索引进度`,
+	Example: `  task2 serve --addr :8080
+  task2 serve --db data/auction.db --cors-origin http://localhost:3000`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		addr, err := cmd.Flags().GetString("addr")
+		if err != nil {
+			return fmt.Errorf("获取监听地址参数错误: %w", err)
+		}
+		config := api.DefaultConfig()
+		if config.CORSOrigin, err = cmd.Flags().GetString("cors-origin"); err != nil {
+			return fmt.Errorf("获取跨域参数错误: %w", err)
+		}
+		st, err := openStore(cmd)
+		if err != nil {
+			return err
+		}
+		defer st.Close()
+
+		listener, err := net.Listen("tcp", addr)
+		if err != nil {
+			return fmt.Errorf("%w: 监听 %s 失败: %w", util.ErrConfig, addr, err)
+		}
+		// 请求的 context 继承命令的 context, Ctrl+C 时 SSE 连接随之结束
+		server := &http.Server{
+			Handler:           api.New(st, config),
+			ReadHeaderTimeout: 10 * time.Second,
+			BaseContext:       func(net.Listener) context.Context { return cmd.Context() },
+		}
+		// Serve 在 Shutdown 开始时就返回, 等待正在处理的请求结束后再关闭数据库
+		stopped := make(chan struct{})
+		go func() {
+			defer close(stopped)
+			<-cmd.Context().Done()
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			server.Shutdown(ctx)
+		}()
+		log.Printf("HTTP 接口已启动: http://%s", listener.Addr())
+		if err := server.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("HTTP 服务错误: %w", err)
+		}
+		<-stopped
+		return nil
+	},
+}
@@ -0,0 +1,153 @@
+package indexer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"task1/auction"
+	"task1/errs"
+	"task1/util"
+	"task2/store"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Events 返回索引的所有事件名
+func Events() []string {
+	return []string{store.EventAuctionCreated, store.EventBidPlaced, store.EventAuctionEnded, store.EventAuctionCancelled,
+		store.EventFeeCollected, store.EventFeesWithdrawn, store.EventFeeRateUpdated}
+}
+
+// decode 将日志解码为事件, AuctionCreated 只包含拍卖 ID, 拍卖信息需要另外查询合约
+func (ix *Indexer) decode(log types.Log, time uint64) (*store.Event, error) {
+	name, ok := ix.events[log.Topics[0]]
+	if !ok {
+		return nil, fmt.Errorf("未知的事件 %s", log.Topics[0].Hex())
+	}
+	event := &store.Event{Block: log.BlockNumber, Time: time, TxHash: log.TxHash, LogIndex: log.Index, Name: name}
+	filterer := &ix.auction.NFTAuctionFilterer
+	var err error
+	switch name {
+	case store.EventAuctionCreated:
+		var e *auction.NFTAuctionAuctionCreated
+		if e, err = filterer.ParseAuctionCreated(log); err == nil {
+			event.AuctionID = e.AuctionId
+			event.Args = map[string]string{"auctionId": e.AuctionId.String()}
+		}
+	case store.EventBidPlaced:
+		var e *auction.NFTAuctionBidPlaced
+		if e, err = filterer.ParseBidPlaced(log); err == nil {
+			event.AuctionID = e.AuctionId
+			event.Args = map[string]string{"auctionId": e.AuctionId.String(), "bidder": e.Bidder.Hex(),
+				"amount": e.Amount.String(), "bidToken": e.BidToken.Hex()}
+		}
+	case store.EventAuctionEnded:
+		var e *auction.NFTAuctionAuctionEnded
+		if e, err = filterer.ParseAuctionEnded(log); err == nil {
+			event.AuctionID = e.AuctionId
+			event.Args = map[string]string{"auctionId": e.AuctionId.String(), "winner": e.Winner.Hex(),
+				"finalPrice": e.FinalPrice.String(), "bidToken": e.BidToken.Hex(), "seller": e.Seller.Hex()}
+		}
+	case store.EventAuctionCancelled:
+		var e *auction.NFTAuctionAuctionCancelled
+		if e, err = filterer.ParseAuctionCancelled(log); err == nil {
+			event.AuctionID = e.AuctionId
+			event.Args = map[string]string{"auctionId": e.AuctionId.String(), "seller": e.Seller.Hex()}
+		}
+	case store.EventFeeCollected:
+		var e *auction.NFTAuctionFeeCollected
+		if e, err = filterer.ParseFeeCollected(log); err == nil {
+			event.AuctionID = e.AuctionId
+			event.Args = map[string]string{"auctionId": e.AuctionId.String(), "feeAmount": e.FeeAmount.String(),
+				"feeToken": e.FeeToken.Hex(), "seller": e.Seller.Hex()}
+		}
+	case store.EventFeesWithdrawn:
+		var e *auction.NFTAuctionFeesWithdrawn
+		if e, err = filterer.ParseFeesWithdrawn(log); err == nil {
+			event.Args = map[string]string{"recipient": e.Recipient.Hex(), "ethAmount": e.EthAmount.String(),
+				"usdcAmount": e.UsdcAmount.String()}
+		}
+	case store.EventFeeRateUpdated:
+		var e *auction.NFTAuctionFeeRateUpdated
+		if e, err = filterer.ParseFeeRateUpdated(log); err == nil {
+			event.Args = map[string]string{"oldFeeRate": e.OldFeeRate.String(), "newFeeRate": e.NewFeeRate.String()}
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("解码区块 %d 交易 %s 的 %s 事件失败: %w", log.BlockNumber, log.TxHash.Hex(), name, err)
+	}
+	if event.AuctionID != nil && !event.AuctionID.IsInt64() {
+		return nil, fmt.Errorf("区块 %d 交易 %s 的 %s 事件中拍卖 ID %s 超出范围", log.BlockNumber, log.TxHash.Hex(), name, event.AuctionID)
+	}
+	return event, nil
+}
+
+// loadAuction 查询新创建拍卖的信息
+// 合约在每次出价时都会把 bidToken 改为出价使用的代币, 因此在创建所在的区块上查询, 得到创建时的出价代币;
+// 同一区块中创建后已有出价时, 区块末的 bidToken 已被覆盖, 改为按创建交易的 isUSDCPrice 参数确定
+func (ix *Indexer) loadAuction(ctx context.Context, event *store.Event) (*store.Auction, error) {
+	opts := &bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).SetUint64(event.Block)}
+	info, err := ix.auction.Auctions(opts, event.AuctionID)
+	if err != nil {
+		return nil, fmt.Errorf("查询拍卖 %s 在区块 %d 的信息失败: %w", event.AuctionID, event.Block, errs.Classify(err))
+	}
+	if info.Seller == (common.Address{}) {
+		// 拍卖合约中不存在该拍卖: 创建交易所在的区块可能已被重组, 下一轮检查时回滚
+		return nil, fmt.Errorf("%w: 拍卖合约中查询不到区块 %d 创建的拍卖 %s", errReorg, event.Block, event.AuctionID)
+	}
+	bidToken := info.BidToken
+	if info.HighestBidder != (common.Address{}) {
+		if bidToken, err = ix.createdToken(ctx, event, opts); errors.Is(err, errNotDirectCall) {
+			// 无法确定时保留区块末的代币, 与出价代币不同时起始价格的计价单位可能有误
+			util.Logf("警告: %v, 使用区块末的出价代币 %s", err, info.BidToken.Hex())
+			bidToken = info.BidToken
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	return &store.Auction{
+		ID:            event.AuctionID,
+		Seller:        info.Seller,
+		NFTContract:   info.NftContract,
+		TokenID:       info.TokenId,
+		BidToken:      bidToken,
+		StartingPrice: info.StartingPrice,
+		StartTime:     info.StartTime,
+		EndTime:       info.EndTime,
+		Block:         event.Block,
+		TxHash:        event.TxHash,
+	}, nil
+}
+
+// errNotDirectCall 创建交易不是直接调用拍卖合约的 createAuction, 无法从交易参数得到创建时的出价代币
+var errNotDirectCall = errors.New("无法确定拍卖创建时的出价代币")
+
+// createdToken 从创建交易的 createAuction 参数得到拍卖创建时的出价代币
+func (ix *Indexer) createdToken(ctx context.Context, event *store.Event, opts *bind.CallOpts) (common.Address, error) {
+	tx, _, err := ix.client.TransactionByHash(ctx, event.TxHash)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("查询拍卖 %s 的创建交易 %s 失败: %w", event.AuctionID, event.TxHash.Hex(), errs.Classify(err))
+	}
+	data := tx.Data()
+	method := ix.abi.Methods["createAuction"]
+	if tx.To() == nil || *tx.To() != ix.config.Contract || len(data) < 4 || string(data[:4]) != string(method.ID) {
+		// 通过其他合约创建时无法从交易参数得到
+		return common.Address{}, fmt.Errorf("%w: 拍卖 %s 在创建的区块 %d 中已有出价, 创建交易 %s 不是直接调用 createAuction",
+			errNotDirectCall, event.AuctionID, event.Block, event.TxHash.Hex())
+	}
+	args, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		return common.Address{}, fmt.Errorf("解码拍卖 %s 的创建交易 %s 失败: %w", event.AuctionID, event.TxHash.Hex(), err)
+	}
+	if isUSDC, _ := args[3].(bool); !isUSDC {
+		return common.Address{}, nil
+	}
+	usdc, err := ix.auction.UsdcTokenAddress(opts)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("查询区块 %d 的 USDC 地址失败: %w", event.Block, errs.Classify(err))
+	}
+	return usdc, nil
+}
@@ -0,0 +1,298 @@
+// Package indexer 将 NFTAuction 合约事件索引到本地数据库
+//
+// 索引器先用 FilterLogs 按区块范围分段回填历史事件, 追上最新区块后定期轮询新事件;
+// 每一段事件和段末区块的哈希在一个事务中写入, 重启后从数据库中的进度继续.
+// 每轮开始前检查已保存的最近区块哈希, 与链上不一致时找到分叉点并回滚之后的记录
+package indexer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"task1/auction"
+	"task1/errs"
+	"task1/util"
+	"task2/store"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// errReorg 查询期间区块发生了变化, 本段结果不能写入
+var errReorg = errors.New("区块在查询期间发生重组")
+
+// Config 索引参数
+type Config struct {
+	Contract      common.Address // 拍卖合约地址
+	StartBlock    uint64         // 首次索引时的起始区块, 一般为合约部署区块
+	ChunkSize     uint64         // 每次 FilterLogs 查询的区块数, 节点拒绝时自动减半, 之后连续成功时逐步恢复
+	Confirmations uint64         // 只索引到 最新区块 - Confirmations, 减少需要回滚的重组
+	PollInterval  time.Duration  // 追上最新区块后轮询新事件的间隔
+	ReorgWindow   uint64         // 保留最近多少个区块的哈希用于检测重组, 更深的重组不会被发现
+}
+
+// DefaultConfig 返回默认的索引参数
+func DefaultConfig() Config {
+	return Config{
+		ChunkSize:    2000,
+		PollInterval: 5 * time.Second,
+		ReorgWindow:  128,
+	}
+}
+
+// Indexer 拍卖合约事件索引器
+type Indexer struct {
+	client  util.Client
+	store   *store.Store
+	config  Config
+	auction *auction.NFTAuction
+	abi     *abi.ABI
+	events  map[common.Hash]string // 事件 topic -> 事件名
+	topics  []common.Hash
+	chunk   uint64 // 当前的查询区块数
+	ok      int    // 当前查询区块数下连续成功的查询次数
+}
+
+// chunkGrowAfter 查询区块数减半后连续成功多少次再加倍, 直到恢复为 ChunkSize
+// 节点的限制通常来自结果数量, 事件密集的区块范围过去后可以恢复较大的查询范围
+const chunkGrowAfter = 8
+
+// New 创建索引器, 并将数据库绑定到当前链上的拍卖合约
+func New(ctx context.Context, client util.Client, st *store.Store, config Config) (*Indexer, error) {
+	if config.ChunkSize == 0 {
+		return nil, fmt.Errorf("%w: 查询区块数不能为 0", util.ErrInvalidArgument)
+	}
+	if config.PollInterval <= 0 {
+		return nil, fmt.Errorf("%w: 轮询间隔需要大于 0", util.ErrInvalidArgument)
+	}
+	if config.ReorgWindow == 0 {
+		return nil, fmt.Errorf("%w: 重组检查区块数不能为 0", util.ErrInvalidArgument)
+	}
+	contractABI, err := auction.NFTAuctionMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	binding, err := auction.NewNFTAuction(config.Contract, client)
+	if err != nil {
+		return nil, err
+	}
+	ix := &Indexer{client: client, store: st, config: config, auction: binding, abi: contractABI,
+		events: make(map[common.Hash]string), chunk: config.ChunkSize}
+	for _, name := range Events() {
+		id := contractABI.Events[name].ID
+		ix.events[id] = name
+		ix.topics = append(ix.topics, id)
+	}
+
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("查询链ID失败: %w", errs.Classify(err))
+	}
+	if err := st.Bind(ctx, chainID, config.Contract, config.StartBlock); err != nil {
+		return nil, err
+	}
+	return ix, nil
+}
+
+// Run 回填历史事件后持续轮询新事件, 直到 ctx 取消
+// 节点错误和重组不会中止索引, 记录日志后在下一轮重试
+func (ix *Indexer) Run(ctx context.Context) error {
+	for {
+		if err := ix.Sync(ctx); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			util.Logf("索引失败, %s 后重试: %v", ix.config.PollInterval, err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(ix.config.PollInterval):
+		}
+	}
+}
+
+// Sync 检查重组后索引到当前最新区块 (减去确认数)
+func (ix *Indexer) Sync(ctx context.Context) error {
+	if err := ix.checkReorg(ctx); err != nil {
+		return err
+	}
+	next, err := ix.store.Cursor(ctx)
+	if err != nil {
+		return err
+	}
+	latest, err := ix.client.BlockNumber(ctx)
+	if err != nil {
+		return fmt.Errorf("查询最新区块失败: %w", errs.Classify(err))
+	}
+	if latest < ix.config.Confirmations || latest-ix.config.Confirmations < next {
+		return nil
+	}
+	head := latest - ix.config.Confirmations
+
+	for next <= head {
+		to := min(next+ix.chunk-1, head)
+		count, err := ix.index(ctx, next, to)
+		if err != nil {
+			if isRangeError(err) && ix.chunk > 1 {
+				ix.chunk, ix.ok = ix.chunk/2, 0
+				util.Logf("节点拒绝查询区块 %d - %d 的日志, 每次查询的区块数减少到 %d: %v", next, to, ix.chunk, err)
+				continue
+			}
+			return err
+		}
+		if ix.ok++; ix.chunk < ix.config.ChunkSize && ix.ok >= chunkGrowAfter {
+			ix.chunk, ix.ok = min(ix.chunk*2, ix.config.ChunkSize), 0
+		}
+		if count > 0 || to < head {
+			util.Logf("已索引区块 %d - %d (最新 %d): %d 个事件", next, to, head, count)
+		}
+		next = to + 1
+	}
+	return nil
+}
+
+// index 索引 [from, to] 区块范围内的事件, 返回写入的事件数
+func (ix *Indexer) index(ctx context.Context, from, to uint64) (int, error) {
+	logs, err := ix.client.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(from),
+		ToBlock:   new(big.Int).SetUint64(to),
+		Addresses: []common.Address{ix.config.Contract},
+		Topics:    [][]common.Hash{ix.topics},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("查询区块 %d - %d 的日志失败: %w", from, to, errs.Classify(err))
+	}
+	// 段末区块在日志查询之后获取, 日志所在区块的哈希与之冲突说明查询期间发生了重组
+	header, err := ix.client.HeaderByNumber(ctx, new(big.Int).SetUint64(to))
+	if err != nil {
+		return 0, fmt.Errorf("查询区块 %d 失败: %w", to, errs.Classify(err))
+	}
+	batch := &store.Batch{To: store.Block{Number: to, Hash: header.Hash(), Time: header.Time}}
+	hashes := map[uint64]common.Hash{to: header.Hash()}
+	times := map[common.Hash]uint64{header.Hash(): header.Time}
+
+	for _, log := range logs {
+		if log.Removed || len(log.Topics) == 0 {
+			continue
+		}
+		if hash, ok := hashes[log.BlockNumber]; ok && hash != log.BlockHash {
+			return 0, fmt.Errorf("%w: 区块 %d 的哈希 %s 与 %s 不一致", errReorg, log.BlockNumber, log.BlockHash.Hex(), hash.Hex())
+		}
+		blockTime, err := ix.blockTime(ctx, log, times)
+		if err != nil {
+			return 0, err
+		}
+		if _, ok := hashes[log.BlockNumber]; !ok {
+			hashes[log.BlockNumber] = log.BlockHash
+			batch.Blocks = append(batch.Blocks, store.Block{Number: log.BlockNumber, Hash: log.BlockHash, Time: blockTime})
+		}
+		event, err := ix.decode(log, blockTime)
+		if err != nil {
+			return 0, err
+		}
+		batch.Events = append(batch.Events, event)
+		if event.Name == store.EventAuctionCreated {
+			created, err := ix.loadAuction(ctx, event)
+			if err != nil {
+				return 0, err
+			}
+			batch.Auctions = append(batch.Auctions, created)
+		}
+	}
+
+	if err := ix.store.Apply(ctx, batch); err != nil {
+		return 0, err
+	}
+	if to > ix.config.ReorgWindow {
+		if err := ix.store.Prune(ctx, to-ix.config.ReorgWindow); err != nil {
+			return 0, err
+		}
+	}
+	return len(batch.Events), nil
+}
+
+// blockTime 返回日志所在区块的时间戳, 节点没有在日志中返回时按区块哈希查询区块头
+func (ix *Indexer) blockTime(ctx context.Context, log types.Log, times map[common.Hash]uint64) (uint64, error) {
+	if log.BlockTimestamp != 0 {
+		return log.BlockTimestamp, nil
+	}
+	if t, ok := times[log.BlockHash]; ok {
+		return t, nil
+	}
+	header, err := ix.client.HeaderByHash(ctx, log.BlockHash)
+	if errors.Is(err, ethereum.NotFound) {
+		return 0, fmt.Errorf("%w: 区块 %d (%s) 已不在链上", errReorg, log.BlockNumber, log.BlockHash.Hex())
+	}
+	if err != nil {
+		return 0, fmt.Errorf("查询区块 %d 失败: %w", log.BlockNumber, errs.Classify(err))
+	}
+	times[log.BlockHash] = header.Time
+	return header.Time, nil
+}
+
+// checkReorg 比较最近保存的区块哈希和链上的区块, 不一致时回滚到最近的共同区块
+func (ix *Indexer) checkReorg(ctx context.Context) error {
+	blocks, err := ix.store.RecentBlocks(ctx, int(ix.config.ReorgWindow))
+	if err != nil || len(blocks) == 0 {
+		return err
+	}
+	for i, block := range blocks {
+		header, err := ix.client.HeaderByNumber(ctx, new(big.Int).SetUint64(block.Number))
+		if err != nil && !errors.Is(err, ethereum.NotFound) {
+			return fmt.Errorf("查询区块 %d 失败: %w", block.Number, errs.Classify(err))
+		}
+		// 新链比保存的区块短时区块不存在, 同样需要回滚
+		if err == nil && header.Hash() == block.Hash {
+			if i == 0 {
+				return nil
+			}
+			util.Logf("检测到链重组: 区块 %d 之后的区块已变化, 回滚之后的记录", block.Number)
+			return ix.store.Rollback(ctx, block.Number)
+		}
+	}
+	oldest := blocks[len(blocks)-1].Number
+	util.Logf("警告: 保存的最近 %d 个区块哈希都已变化, 重组深度超过检查范围, 回滚到区块 %d 之前", len(blocks), oldest)
+	if oldest == 0 {
+		return fmt.Errorf("%w: 创世区块的哈希已变化, 数据库可能属于另一条链", store.ErrMismatch)
+	}
+	return ix.store.Rollback(ctx, oldest-1)
+}
+
+// isRangeError 判断节点是否因为查询的区块范围或结果数量过大而拒绝 eth_getLogs
+// 只匹配各节点和服务商的范围、结果数量限制的错误信息, 其他包含 limit 等字样的错误 (如请求频率限制) 不减小查询范围
+func isRangeError(err error) bool {
+	if errs.KindOf(err) == errs.ErrRateLimited {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	for _, s := range rangeErrors {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// rangeErrors eth_getLogs 范围或结果数量超限的错误信息片段, 例如:
+//   - geth: "exceed maximum block range: 5000"
+//   - Infura: "query returned more than 10000 results"
+//   - Alchemy: "Log response size exceeded. You can make eth_getLogs requests with up to a 2K block range ..."
+//   - QuickNode: "eth_getLogs is limited to a 10,000 range"
+//   - Ankr: "block range is too wide"
+//   - Erigon / 其他: "query exceeds max block range 1000"、"requested too many blocks from 0 to 20000, maximum is set to 2048"
+var rangeErrors = []string{
+	"block range",
+	"returned more than",
+	"response size exceeded",
+	"is limited to a",
+	"too many blocks",
+	"max results",
+	"range is too",
+	"range too large",
+}
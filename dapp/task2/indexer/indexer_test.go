@@ -0,0 +1,342 @@
+package indexer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"task1/auction"
+	"task1/auction/auctiontest"
+	"task1/contracts"
+	"task1/util"
+	"task2/store"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// 账户: 0 合约所有者, 1 卖家, 2 出价者
+const (
+	seller = 1
+	bidder = 2
+)
+
+// logsClient 记录 FilterLogs 的查询范围, 查询的区块数超过 limit 时按节点的格式拒绝
+type logsClient struct {
+	util.Client
+	limit    uint64
+	err      error // 不为 nil 时所有查询都返回该错误
+	queries  [][2]uint64
+	rejected int
+}
+
+func (c *logsClient) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	from, to := q.FromBlock.Uint64(), q.ToBlock.Uint64()
+	if c.err != nil {
+		return nil, c.err
+	}
+	if c.limit > 0 && to-from+1 > c.limit {
+		c.rejected++
+		return nil, fmt.Errorf("query returned more than 10000 results. Try with this block range [0x%x, 0x%x]", from, from+c.limit-1)
+	}
+	c.queries = append(c.queries, [2]uint64{from, to})
+	return c.Client.FilterLogs(ctx, q)
+}
+
+// env 模拟链、拍卖服务和索引数据库
+type env struct {
+	chain   *auctiontest.Chain
+	service *auction.Service
+	store   *store.Store
+	client  *logsClient
+}
+
+func newEnv(t *testing.T) *env {
+	t.Helper()
+	chain := auctiontest.New(t, 3)
+	deployments := contracts.OpenDeployments(filepath.Join(t.TempDir(), "deployments.json"))
+	service, err := auction.NewService(context.Background(), chain.Client, deployments, chain.Auction.Hex())
+	if err != nil {
+		t.Fatal(err)
+	}
+	st, err := store.Open(filepath.Join(t.TempDir(), "auction.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { st.Close() })
+	return &env{chain: chain, service: service, store: st, client: &logsClient{Client: chain.Client}}
+}
+
+// indexer 创建索引器, 通过 logsClient 访问模拟链; 多次调用相当于重启索引进程
+func (e *env) indexer(t *testing.T, chunkSize uint64) *Indexer {
+	t.Helper()
+	config := DefaultConfig()
+	config.Contract = e.chain.Auction
+	config.ChunkSize = chunkSize
+	ix, err := New(context.Background(), e.client, e.store, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ix
+}
+
+func (e *env) create(t *testing.T, tokenID int64) int64 {
+	t.Helper()
+	e.chain.MintNFT(t, e.chain.Accounts[seller], tokenID)
+	e.chain.Use(seller)
+	_, id, err := e.service.Create(context.Background(), e.chain.NFT, big.NewInt(tokenID), ether(1), false, 2*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return id.Int64()
+}
+
+func (e *env) bid(t *testing.T, id int64, amount *big.Int) *types.Receipt {
+	t.Helper()
+	e.chain.Use(bidder)
+	receipt, err := e.service.Bid(context.Background(), big.NewInt(id), amount, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return receipt
+}
+
+func (e *env) status(t *testing.T) *store.Status {
+	t.Helper()
+	status, err := e.store.Status(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return status
+}
+
+func sync(t *testing.T, ix *Indexer) {
+	t.Helper()
+	if err := ix.Sync(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func ether(n int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(n), big.NewInt(params.Ether))
+}
+
+// TestReorgRollback 链重组后回滚被移除区块中的记录, 并按新链重新索引
+func TestReorgRollback(t *testing.T) {
+	ctx := context.Background()
+	e := newEnv(t)
+	ix := e.indexer(t, DefaultConfig().ChunkSize)
+	id := e.create(t, 1)
+	receipt := e.bid(t, id, ether(2))
+	sync(t, ix)
+	if view, err := e.store.Auction(ctx, id, 0); err != nil || view.Bids != 1 {
+		t.Fatalf("拍卖 %+v, %v, 预期有 1 次出价", view, err)
+	}
+
+	// 回到出价区块的父区块, 新链比已索引的区块短, 出价被移除
+	parent, err := e.chain.Client.HeaderByNumber(ctx, new(big.Int).Sub(receipt.BlockNumber, big.NewInt(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := e.chain.Backend.Fork(parent.Hash()); err != nil {
+		t.Fatal(err)
+	}
+	sync(t, ix)
+	if next := e.status(t).NextBlock; next != parent.Number.Uint64()+1 {
+		t.Fatalf("回滚后的下一个待索引区块为 %d, 预期 %d", next, parent.Number.Uint64()+1)
+	}
+	if view, err := e.store.Auction(ctx, id, 0); err != nil || view.Bids != 0 {
+		t.Fatalf("回滚后的拍卖 %+v, %v, 预期没有出价", view, err)
+	}
+	if reorg, err := e.store.LastReorg(ctx); err != nil || reorg != 1 {
+		t.Fatalf("回滚记录编号为 %d, %v, 预期 1", reorg, err)
+	}
+
+	// 出价交易重新打包到新链的区块中, 按新链的区块哈希重新索引
+	e.chain.Backend.Commit()
+	e.chain.Backend.Commit()
+	sync(t, ix)
+	if view, err := e.store.Auction(ctx, id, 0); err != nil || view.Bids != 1 {
+		t.Fatalf("重新索引后的拍卖 %+v, %v, 预期有 1 次出价", view, err)
+	}
+	blocks, err := e.store.RecentBlocks(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, block := range blocks {
+		header, err := e.chain.Client.HeaderByNumber(ctx, new(big.Int).SetUint64(block.Number))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if header.Hash() != block.Hash {
+			t.Errorf("区块 %d 保存的哈希 %s 与新链 %s 不一致", block.Number, block.Hash.Hex(), header.Hash().Hex())
+		}
+	}
+	if reorg, _ := e.store.LastReorg(ctx); reorg != 1 {
+		t.Errorf("重新索引时不应再回滚, 回滚记录编号为 %d", reorg)
+	}
+}
+
+// TestResume 重启后从数据库中的进度继续索引, 不重复查询已索引的区块
+func TestResume(t *testing.T) {
+	ctx := context.Background()
+	e := newEnv(t)
+	e.create(t, 1)
+	sync(t, e.indexer(t, DefaultConfig().ChunkSize))
+	before := e.status(t)
+	cursor, err := e.store.Cursor(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e.create(t, 2)
+	e.client.queries = nil
+	sync(t, e.indexer(t, DefaultConfig().ChunkSize))
+	if len(e.client.queries) == 0 || e.client.queries[0][0] != cursor {
+		t.Fatalf("重启后的查询范围为 %v, 预期从区块 %d 开始", e.client.queries, cursor)
+	}
+	after := e.status(t)
+	if after.Auctions != 2 || after.Events <= before.Events {
+		t.Fatalf("重启前 %+v, 重启后 %+v", before, after)
+	}
+
+	// 同一数据库不能用于其他合约
+	config := DefaultConfig()
+	config.Contract = e.chain.NFT
+	if _, err := New(ctx, e.client, e.store, config); !errors.Is(err, store.ErrMismatch) {
+		t.Fatalf("绑定其他合约的错误为 %v, 预期 %v", err, store.ErrMismatch)
+	}
+}
+
+// TestChunkHalving 节点拒绝过大的查询范围时减半重试, 之后连续成功时逐步恢复
+func TestChunkHalving(t *testing.T) {
+	e := newEnv(t)
+	e.create(t, 1)
+	e.create(t, 2)
+	const chunkSize = 16
+	ix := e.indexer(t, chunkSize)
+	e.client.limit = 3
+	sync(t, ix)
+	// 16 -> 8 -> 4 -> 2 被拒绝 3 次, 之后每连续成功 chunkGrowAfter 次尝试加倍到 4, 被拒绝后再减半
+	if most := 3 + len(e.client.queries)/chunkGrowAfter; e.client.rejected == 0 || e.client.rejected > most {
+		t.Fatalf("被拒绝 %d 次, 预期 1 - %d 次", e.client.rejected, most)
+	}
+	if status := e.status(t); status.Auctions != 2 {
+		t.Fatalf("索引状态 %+v, 预期 2 个拍卖", status)
+	}
+	// 查询范围连续且不重叠
+	for i := 1; i < len(e.client.queries); i++ {
+		if e.client.queries[i][0] != e.client.queries[i-1][1]+1 {
+			t.Fatalf("查询范围不连续: %v", e.client.queries)
+		}
+	}
+
+	// 节点不再限制后恢复为 ChunkSize
+	e.client.limit = 0
+	for range 8 * chunkSize {
+		e.chain.Backend.Commit()
+	}
+	sync(t, ix)
+	if ix.chunk != chunkSize {
+		t.Fatalf("查询区块数为 %d, 预期恢复为 %d", ix.chunk, chunkSize)
+	}
+
+	// 其他错误不减小查询范围
+	e.chain.Backend.Commit()
+	e.client.err = errors.New("429 Too Many Requests: rate limit exceeded")
+	if err := ix.Sync(context.Background()); err == nil {
+		t.Fatal("预期查询失败")
+	}
+	if ix.chunk != chunkSize {
+		t.Fatalf("请求频率超限后查询区块数为 %d, 预期 %d", ix.chunk, chunkSize)
+	}
+}
+
+func TestIsRangeError(t *testing.T) {
+	tests := []struct {
+		msg  string
+		want bool
+	}{
+		{"exceed maximum block range: 5000", true},
+		{"query returned more than 10000 results", true},
+		{"Log response size exceeded. You can make eth_getLogs requests with up to a 2K block range", true},
+		{"eth_getLogs is limited to a 10,000 range", true},
+		{"block range is too wide", true},
+		{"requested too many blocks from 0 to 20000, maximum is set to 2048", true},
+		{"429 Too Many Requests", false},
+		{"daily request limit reached", false},
+		{"gas limit exceeded", false},
+		{"execution reverted", false},
+	}
+	for _, tt := range tests {
+		if got := isRangeError(errors.New(tt.msg)); got != tt.want {
+			t.Errorf("isRangeError(%q) = %v, 预期 %v", tt.msg, got, tt.want)
+		}
+	}
+}
+
+// TestSameBlockBid 创建拍卖和第一次出价在同一区块时, 按创建交易的参数确定创建时的出价代币
+func TestSameBlockBid(t *testing.T) {
+	ctx := context.Background()
+	e := newEnv(t)
+	ix := e.indexer(t, DefaultConfig().ChunkSize)
+	e.chain.MintNFT(t, e.chain.Accounts[seller], 1)
+	nft, err := auction.NewMyNFT(e.chain.NFT, e.chain.Client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := nft.Approve(e.chain.Transactor(t, seller), e.chain.Auction, big.NewInt(1)); err != nil {
+		t.Fatal(err)
+	}
+
+	// 以 USDC 计价创建, 同一区块中用 ETH 出价, 区块末的 bidToken 已变为 ETH
+	contract, err := auction.NewNFTAuction(e.chain.Auction, e.chain.Client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := func(i int, value *big.Int) *bind.TransactOpts {
+		opts := e.chain.Transactor(t, i)
+		opts.NoSend, opts.GasLimit, opts.Value = true, 1_000_000, value
+		return opts
+	}
+	create, err := contract.CreateAuction(opts(seller, nil), e.chain.NFT, big.NewInt(1), big.NewInt(100e6), true, big.NewInt(7200))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bid, err := contract.PlaceBid(opts(bidder, ether(1)), big.NewInt(0), new(big.Int), common.Address{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tx := range []*types.Transaction{create, bid} {
+		if err := e.chain.Backend.Client().SendTransaction(ctx, tx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	e.chain.Backend.Commit()
+	var blocks []uint64
+	for _, tx := range []*types.Transaction{create, bid} {
+		receipt, err := e.chain.Client.TransactionReceipt(ctx, tx.Hash())
+		if err != nil || receipt.Status != types.ReceiptStatusSuccessful {
+			t.Fatalf("交易 %s 的收据 %+v, %v", tx.Hash().Hex(), receipt, err)
+		}
+		blocks = append(blocks, receipt.BlockNumber.Uint64())
+	}
+	if blocks[0] != blocks[1] {
+		t.Fatalf("创建和出价交易所在的区块为 %v, 预期同一区块", blocks)
+	}
+
+	sync(t, ix)
+	view, err := e.store.Auction(ctx, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if view.StartingToken != e.chain.USDC.Hex() || view.BidToken != (common.Address{}).Hex() || view.Bids != 1 {
+		t.Fatalf("拍卖 %+v, 预期起始价格以 USDC %s 计价, 最高出价为 ETH", view, e.chain.USDC.Hex())
+	}
+}
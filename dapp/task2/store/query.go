@@ -0,0 +1,372 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"task1/auction"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Page 分页参数
+type Page struct {
+	Limit  int
+	Offset int
+}
+
+// AuctionFilter 拍卖列表的过滤条件, 零值表示不过滤
+type AuctionFilter struct {
+	Status string          // auction.StatusActive / StatusExpired / StatusEnded
+	Seller *common.Address // 卖家
+}
+
+// AuctionView 拍卖的当前状态, 由创建信息和之后的出价、结束、取消事件计算得到
+// 金额为代币最小单位 (ETH 为 wei) 的十进制字符串, startingPrice 的单位为 startingToken, highestBid 和 finalPrice 的单位为 bidToken
+type AuctionView struct {
+	ID            int64  `json:"id"`
+	Seller        string `json:"seller"`
+	NFTContract   string `json:"nftContract"`
+	TokenID       string `json:"tokenId"`
+	BidToken      string `json:"bidToken"`      // 当前最高出价使用的代币, 没有出价时与 startingToken 相同
+	StartingToken string `json:"startingToken"` // 创建时的出价代币, 即起始价格的计价代币
+	StartingPrice string `json:"startingPrice"`
+	StartTime     uint64 `json:"startTime"`
+	EndTime       uint64 `json:"endTime"`
+	Status        string `json:"status"`
+	Cancelled     bool   `json:"cancelled"`               // 卖家在无人出价时取消, 状态为 ended
+	Bids          int64  `json:"bids"`                    // 出价次数
+	HighestBidder string `json:"highestBidder,omitempty"` // 没有出价时为空
+	HighestBid    string `json:"highestBid"`              // 没有出价时为 0
+	Winner        string `json:"winner,omitempty"`        // 结束后的赢家, 无人出价时为零地址
+	FinalPrice    string `json:"finalPrice,omitempty"`
+	CreatedBlock  uint64 `json:"createdBlock"`
+	CreatedTx     string `json:"createdTx"`
+}
+
+// Bid 一次出价
+type Bid struct {
+	AuctionID int64  `json:"auctionId"`
+	Bidder    string `json:"bidder"`
+	Amount    string `json:"amount"`
+	BidToken  string `json:"bidToken"`
+	Block     uint64 `json:"block"`
+	LogIndex  uint   `json:"logIndex"`
+	Time      uint64 `json:"time"`
+	TxHash    string `json:"txHash"`
+}
+
+// Position 事件在链上的位置, 按 (区块号, 日志索引) 排序
+type Position struct {
+	Block    uint64
+	LogIndex uint
+}
+
+// FeeCollection 一次手续费收取
+type FeeCollection struct {
+	AuctionID int64  `json:"auctionId"`
+	Seller    string `json:"seller"`
+	Amount    string `json:"amount"`
+	Token     string `json:"token"`
+	Block     uint64 `json:"block"`
+	Time      uint64 `json:"time"`
+	TxHash    string `json:"txHash"`
+}
+
+// Fees 手续费汇总
+type Fees struct {
+	FeeRate       string            `json:"feeRate,omitempty"` // 最近一次 FeeRateUpdated 的费率 (基点), 从未修改时为空
+	Collected     map[string]string `json:"collected"`         // 按代币地址汇总的已收取手续费
+	WithdrawnETH  string            `json:"withdrawnEth"`      // 已提取的 ETH 手续费
+	WithdrawnUSDC string            `json:"withdrawnUsdc"`     // 已提取的 USDC 手续费
+}
+
+// auctionView 在拍卖表上附加出价次数、最高出价和结束事件, 并计算状态
+// 与合约一致, end_time 当秒仍可出价, 之后才是到期状态
+const auctionView = `
+SELECT *, CASE
+		WHEN closed_by IS NOT NULL THEN @statusEnded
+		WHEN end_time < @now THEN @statusExpired
+		ELSE @statusActive
+	END AS status
+FROM (
+	SELECT a.id, a.seller, a.nft_contract, a.token_id, a.bid_token, a.starting_price, a.start_time, a.end_time, a.block_number, a.tx_hash,
+		(SELECT count(*) FROM events e WHERE e.auction_id = a.id AND e.name = @bid) AS bids,
+		(SELECT e.args FROM events e WHERE e.auction_id = a.id AND e.name = @bid
+			ORDER BY e.block_number DESC, e.log_index DESC LIMIT 1) AS last_bid,
+		(SELECT e.name FROM events e WHERE e.auction_id = a.id AND e.name IN (@ended, @cancelled)
+			ORDER BY e.block_number, e.log_index LIMIT 1) AS closed_by,
+		(SELECT e.args FROM events e WHERE e.auction_id = a.id AND e.name = @ended
+			ORDER BY e.block_number, e.log_index LIMIT 1) AS ended_args
+	FROM auctions a
+)`
+
+// viewArgs 返回 auctionView 需要的参数, now 为判断拍卖是否到期的时间
+func viewArgs(now uint64) []any {
+	return []any{
+		sql.Named("statusEnded", auction.StatusEnded),
+		sql.Named("statusExpired", auction.StatusExpired),
+		sql.Named("statusActive", auction.StatusActive),
+		sql.Named("now", now),
+		sql.Named("bid", EventBidPlaced),
+		sql.Named("ended", EventAuctionEnded),
+		sql.Named("cancelled", EventAuctionCancelled),
+	}
+}
+
+// filterArgs 返回过滤条件的参数, 空字符串表示不过滤
+func filterArgs(filter AuctionFilter) []any {
+	seller := ""
+	if filter.Seller != nil {
+		seller = filter.Seller.Hex()
+	}
+	return []any{sql.Named("status", filter.Status), sql.Named("seller", seller)}
+}
+
+const auctionFilter = ` WHERE (@status = '' OR status = @status) AND (@seller = '' OR seller = @seller)`
+
+// Auctions 按拍卖 ID 从大到小返回一页拍卖和符合条件的总数, now 为判断拍卖是否到期的时间
+func (s *Store) Auctions(ctx context.Context, filter AuctionFilter, page Page, now uint64) ([]*AuctionView, int64, error) {
+	args := append(viewArgs(now), filterArgs(filter)...)
+	var total int64
+	if err := s.db.QueryRowContext(ctx, `SELECT count(*) FROM (`+auctionView+`)`+auctionFilter, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("查询拍卖数量失败: %w", err)
+	}
+	args = append(args, sql.Named("limit", page.Limit), sql.Named("offset", page.Offset))
+	rows, err := s.db.QueryContext(ctx, `SELECT * FROM (`+auctionView+`)`+auctionFilter+` ORDER BY id DESC LIMIT @limit OFFSET @offset`, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("查询拍卖失败: %w", err)
+	}
+	defer rows.Close()
+	auctions := []*AuctionView{}
+	for rows.Next() {
+		view, err := scanAuction(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		auctions = append(auctions, view)
+	}
+	return auctions, total, rows.Err()
+}
+
+// Auction 返回指定拍卖, 不存在时返回 nil
+func (s *Store) Auction(ctx context.Context, id int64, now uint64) (*AuctionView, error) {
+	args := append(viewArgs(now), sql.Named("id", id))
+	view, err := scanAuction(s.db.QueryRowContext(ctx, `SELECT * FROM (`+auctionView+`) WHERE id = @id`, args...))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return view, err
+}
+
+// StatusCounts 返回卖家各状态的拍卖数量
+func (s *Store) StatusCounts(ctx context.Context, seller common.Address, now uint64) (map[string]int64, error) {
+	args := append(viewArgs(now), filterArgs(AuctionFilter{Seller: &seller})...)
+	rows, err := s.db.QueryContext(ctx, `SELECT status, count(*) FROM (`+auctionView+`)`+auctionFilter+` GROUP BY status`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("统计卖家 %s 的拍卖失败: %w", seller.Hex(), err)
+	}
+	defer rows.Close()
+	counts := make(map[string]int64)
+	for _, status := range auction.Statuses() {
+		counts[status] = 0
+	}
+	for rows.Next() {
+		var status string
+		var count int64
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, fmt.Errorf("统计卖家 %s 的拍卖失败: %w", seller.Hex(), err)
+		}
+		counts[status] = count
+	}
+	return counts, rows.Err()
+}
+
+// scanAuction 读取 auctionView 的一行
+func scanAuction(row interface{ Scan(...any) error }) (*AuctionView, error) {
+	var view AuctionView
+	var lastBid, closedBy, endedArgs sql.NullString
+	err := row.Scan(&view.ID, &view.Seller, &view.NFTContract, &view.TokenID, &view.StartingToken, &view.StartingPrice,
+		&view.StartTime, &view.EndTime, &view.CreatedBlock, &view.CreatedTx, &view.Bids, &lastBid, &closedBy, &endedArgs, &view.Status)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取拍卖失败: %w", err)
+	}
+	view.HighestBid = "0"
+	view.BidToken = view.StartingToken
+	view.Cancelled = closedBy.String == EventAuctionCancelled
+	if lastBid.Valid {
+		args, err := parseArgs(lastBid.String)
+		if err != nil {
+			return nil, err
+		}
+		view.HighestBidder, view.HighestBid, view.BidToken = args["bidder"], args["amount"], args["bidToken"]
+	}
+	if endedArgs.Valid {
+		args, err := parseArgs(endedArgs.String)
+		if err != nil {
+			return nil, err
+		}
+		view.Winner, view.FinalPrice = args["winner"], args["finalPrice"]
+	}
+	return &view, nil
+}
+
+// Bids 按时间从新到旧返回拍卖的一页出价和出价总数
+func (s *Store) Bids(ctx context.Context, auctionID int64, page Page) ([]*Bid, int64, error) {
+	var total int64
+	if err := s.db.QueryRowContext(ctx, `SELECT count(*) FROM events WHERE name = ? AND auction_id = ?`,
+		EventBidPlaced, auctionID).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("查询拍卖 %d 的出价数量失败: %w", auctionID, err)
+	}
+	rows, err := s.db.QueryContext(ctx, `SELECT block_number, log_index, block_time, tx_hash, auction_id, args FROM events
+		WHERE name = ? AND auction_id = ? ORDER BY block_number DESC, log_index DESC LIMIT ? OFFSET ?`,
+		EventBidPlaced, auctionID, page.Limit, page.Offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("查询拍卖 %d 的出价失败: %w", auctionID, err)
+	}
+	bids, err := scanBids(rows)
+	return bids, total, err
+}
+
+// BidsAfter 按链上顺序返回 after 之后的最多 limit 个出价, auctionID 为 nil 时返回所有拍卖的出价
+func (s *Store) BidsAfter(ctx context.Context, auctionID *int64, after Position, limit int) ([]*Bid, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT block_number, log_index, block_time, tx_hash, auction_id, args FROM events
+		WHERE name = ? AND (? IS NULL OR auction_id = ?) AND (block_number, log_index) > (?, ?)
+		ORDER BY block_number, log_index LIMIT ?`,
+		EventBidPlaced, auctionID, auctionID, after.Block, after.LogIndex, limit)
+	if err != nil {
+		return nil, fmt.Errorf("查询新出价失败: %w", err)
+	}
+	return scanBids(rows)
+}
+
+// LastBid 返回最新出价的位置, 没有出价时返回零值
+func (s *Store) LastBid(ctx context.Context) (Position, error) {
+	var pos Position
+	err := s.db.QueryRowContext(ctx, `SELECT block_number, log_index FROM events WHERE name = ?
+		ORDER BY block_number DESC, log_index DESC LIMIT 1`, EventBidPlaced).Scan(&pos.Block, &pos.LogIndex)
+	if err != nil && err != sql.ErrNoRows {
+		return pos, fmt.Errorf("查询最新出价失败: %w", err)
+	}
+	return pos, nil
+}
+
+func scanBids(rows *sql.Rows) ([]*Bid, error) {
+	defer rows.Close()
+	bids := []*Bid{}
+	for rows.Next() {
+		var bid Bid
+		var raw string
+		if err := rows.Scan(&bid.Block, &bid.LogIndex, &bid.Time, &bid.TxHash, &bid.AuctionID, &raw); err != nil {
+			return nil, fmt.Errorf("读取出价失败: %w", err)
+		}
+		args, err := parseArgs(raw)
+		if err != nil {
+			return nil, err
+		}
+		bid.Bidder, bid.Amount, bid.BidToken = args["bidder"], args["amount"], args["bidToken"]
+		bids = append(bids, &bid)
+	}
+	return bids, rows.Err()
+}
+
+// Fees 返回手续费汇总, 金额在 Go 中用 big.Int 累加, 避免 SQLite 整数溢出
+func (s *Store) Fees(ctx context.Context) (*Fees, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT name, args FROM events WHERE name IN (?, ?, ?) ORDER BY block_number, log_index`,
+		EventFeeCollected, EventFeesWithdrawn, EventFeeRateUpdated)
+	if err != nil {
+		return nil, fmt.Errorf("查询手续费事件失败: %w", err)
+	}
+	defer rows.Close()
+	collected := make(map[string]*big.Int)
+	withdrawnETH, withdrawnUSDC := new(big.Int), new(big.Int)
+	fees := &Fees{Collected: make(map[string]string)}
+	for rows.Next() {
+		var name, raw string
+		if err := rows.Scan(&name, &raw); err != nil {
+			return nil, fmt.Errorf("读取手续费事件失败: %w", err)
+		}
+		args, err := parseArgs(raw)
+		if err != nil {
+			return nil, err
+		}
+		switch name {
+		case EventFeeCollected:
+			token := args["feeToken"]
+			if collected[token] == nil {
+				collected[token] = new(big.Int)
+			}
+			if err := addDecimal(collected[token], args["feeAmount"]); err != nil {
+				return nil, err
+			}
+		case EventFeesWithdrawn:
+			if err := addDecimal(withdrawnETH, args["ethAmount"]); err != nil {
+				return nil, err
+			}
+			if err := addDecimal(withdrawnUSDC, args["usdcAmount"]); err != nil {
+				return nil, err
+			}
+		case EventFeeRateUpdated:
+			fees.FeeRate = args["newFeeRate"]
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("读取手续费事件失败: %w", err)
+	}
+	for token, amount := range collected {
+		fees.Collected[token] = amount.String()
+	}
+	fees.WithdrawnETH, fees.WithdrawnUSDC = withdrawnETH.String(), withdrawnUSDC.String()
+	return fees, nil
+}
+
+// FeeCollections 按时间从新到旧返回一页手续费收取记录和总数
+func (s *Store) FeeCollections(ctx context.Context, page Page) ([]*FeeCollection, int64, error) {
+	var total int64
+	if err := s.db.QueryRowContext(ctx, `SELECT count(*) FROM events WHERE name = ?`, EventFeeCollected).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("查询手续费记录数量失败: %w", err)
+	}
+	rows, err := s.db.QueryContext(ctx, `SELECT block_number, block_time, tx_hash, auction_id, args FROM events
+		WHERE name = ? ORDER BY block_number DESC, log_index DESC LIMIT ? OFFSET ?`, EventFeeCollected, page.Limit, page.Offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("查询手续费记录失败: %w", err)
+	}
+	defer rows.Close()
+	collections := []*FeeCollection{}
+	for rows.Next() {
+		var fee FeeCollection
+		var raw string
+		if err := rows.Scan(&fee.Block, &fee.Time, &fee.TxHash, &fee.AuctionID, &raw); err != nil {
+			return nil, 0, fmt.Errorf("读取手续费记录失败: %w", err)
+		}
+		args, err := parseArgs(raw)
+		if err != nil {
+			return nil, 0, err
+		}
+		fee.Seller, fee.Amount, fee.Token = args["seller"], args["feeAmount"], args["feeToken"]
+		collections = append(collections, &fee)
+	}
+	return collections, total, rows.Err()
+}
+
+func parseArgs(raw string) (map[string]string, error) {
+	var args map[string]string
+	if err := json.Unmarshal([]byte(raw), &args); err != nil {
+		return nil, fmt.Errorf("事件参数 %q 格式错误: %w", raw, err)
+	}
+	return args, nil
+}
+
+func addDecimal(sum *big.Int, value string) error {
+	n, ok := new(big.Int).SetString(value, 10)
+	if !ok {
+		return fmt.Errorf("金额 %q 格式错误", value)
+	}
+	sum.Add(sum, n)
+	return nil
+}
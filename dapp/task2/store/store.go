@@ -0,0 +1,383 @@
+// Package store 将 NFTAuction 合约事件保存到 SQLite 数据库
+//
+// 每条记录都带有所在区块号, 发生重组时删除分叉点之后的所有记录即可回滚;
+// 索引进度 (下一个待索引区块) 和最近区块的哈希也保存在数据库中, 重启后从断点继续
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+	"task1/util"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// DefaultPath 默认的数据库文件
+const DefaultPath = "auction.db"
+
+// ErrMismatch 数据库已用于其他链或其他合约的索引
+var ErrMismatch = errors.New("数据库与索引目标不匹配")
+
+const schema = `
+CREATE TABLE IF NOT EXISTS meta (
+	key   TEXT PRIMARY KEY,
+	value TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS blocks (
+	number INTEGER PRIMARY KEY,
+	hash   TEXT NOT NULL,
+	time   INTEGER NOT NULL
+);
+CREATE TABLE IF NOT EXISTS events (
+	block_number INTEGER NOT NULL,
+	log_index    INTEGER NOT NULL,
+	block_time   INTEGER NOT NULL,
+	tx_hash      TEXT NOT NULL,
+	name         TEXT NOT NULL,
+	auction_id   INTEGER,
+	args         TEXT NOT NULL,
+	PRIMARY KEY (block_number, log_index)
+);
+CREATE INDEX IF NOT EXISTS events_auction ON events (auction_id, name);
+CREATE INDEX IF NOT EXISTS events_name ON events (name);
+CREATE TABLE IF NOT EXISTS auctions (
+	id             INTEGER PRIMARY KEY,
+	seller         TEXT NOT NULL,
+	nft_contract   TEXT NOT NULL,
+	token_id       TEXT NOT NULL,
+	bid_token      TEXT NOT NULL,
+	starting_price TEXT NOT NULL,
+	start_time     INTEGER NOT NULL,
+	end_time       INTEGER NOT NULL,
+	block_number   INTEGER NOT NULL,
+	tx_hash        TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS auctions_seller ON auctions (seller);
+CREATE TABLE IF NOT EXISTS reorgs (
+	id           INTEGER PRIMARY KEY AUTOINCREMENT,
+	block_number INTEGER NOT NULL,
+	time         INTEGER NOT NULL
+);
+`
+
+// 索引的拍卖合约事件名
+const (
+	EventAuctionCreated   = "AuctionCreated"
+	EventBidPlaced        = "BidPlaced"
+	EventAuctionEnded     = "AuctionEnded"
+	EventAuctionCancelled = "AuctionCancelled"
+	EventFeeCollected     = "FeeCollected"
+	EventFeesWithdrawn    = "FeesWithdrawn"
+	EventFeeRateUpdated   = "FeeRateUpdated"
+)
+
+// meta 表的键
+const (
+	metaChainID   = "chain_id"
+	metaContract  = "contract"
+	metaNextBlock = "next_block" // 下一个待索引的区块, 之前的区块已全部写入
+)
+
+// Block 已索引区块的哈希和时间, 哈希用于检测重组, 最新区块的时间用于判断拍卖是否到期
+type Block struct {
+	Number uint64
+	Hash   common.Hash
+	Time   uint64
+}
+
+// Event 解码后的拍卖合约事件, 参数中的金额和地址都保存为字符串
+type Event struct {
+	Block     uint64
+	Time      uint64
+	TxHash    common.Hash
+	LogIndex  uint
+	Name      string
+	AuctionID *big.Int // FeesWithdrawn、FeeRateUpdated 与具体拍卖无关, 为 nil
+	Args      map[string]string
+}
+
+// Auction 拍卖创建时的信息
+// 合约在每次出价时都会把拍卖的 bidToken 改为出价使用的代币, 这里保存的是创建时的代币, 当前代币由最近的 BidPlaced 事件得到
+type Auction struct {
+	ID            *big.Int
+	Seller        common.Address
+	NFTContract   common.Address
+	TokenID       *big.Int
+	BidToken      common.Address // 创建时的出价代币, 即起始价格的计价代币, 零地址表示 ETH
+	StartingPrice *big.Int
+	StartTime     uint64
+	EndTime       uint64
+	Block         uint64
+	TxHash        common.Hash
+}
+
+// Batch 一段区块范围的索引结果, 在一个事务中写入并推进索引进度
+type Batch struct {
+	To       Block // 范围内的最后一个区块, 写入后 To.Number+1 为下一个待索引区块
+	Blocks   []Block
+	Events   []*Event
+	Auctions []*Auction
+}
+
+// Store SQLite 事件数据库
+type Store struct {
+	db *sql.DB
+}
+
+// Open 打开或创建数据库文件, 使用 WAL 模式以便索引和查询并发进行
+func Open(path string) (*Store, error) {
+	if path == "" {
+		path = DefaultPath
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("%w: 创建数据库目录失败: %w", util.ErrConfig, err)
+		}
+	}
+	db, err := sql.Open("sqlite3", "file:"+path+"?_journal_mode=WAL&_busy_timeout=5000&_synchronous=NORMAL")
+	if err != nil {
+		return nil, fmt.Errorf("%w: 打开数据库 %s 失败: %w", util.ErrConfig, path, err)
+	}
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("%w: 初始化数据库 %s 失败: %w", util.ErrConfig, path, err)
+	}
+	return &Store{db: db}, nil
+}
+
+// Close 关闭数据库
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Bind 将数据库绑定到指定链上的合约, 首次使用时从 startBlock 开始索引
+// 数据库已用于其他链或其他合约时返回 ErrMismatch, 避免不同合约的事件混在一起
+func (s *Store) Bind(ctx context.Context, chainID *big.Int, contract common.Address, startBlock uint64) error {
+	meta, err := s.meta(ctx)
+	if err != nil {
+		return err
+	}
+	if len(meta) == 0 {
+		_, err := s.db.ExecContext(ctx, `INSERT INTO meta (key, value) VALUES (?, ?), (?, ?), (?, ?)`,
+			metaChainID, chainID.String(), metaContract, contract.Hex(), metaNextBlock, strconv.FormatUint(startBlock, 10))
+		if err != nil {
+			return fmt.Errorf("写入索引信息失败: %w", err)
+		}
+		return nil
+	}
+	if meta[metaChainID] != chainID.String() || meta[metaContract] != contract.Hex() {
+		return fmt.Errorf("%w: 数据库已用于链 %s 上的合约 %s, 当前为链 %s 上的合约 %s, 请使用其他数据库文件",
+			ErrMismatch, meta[metaChainID], meta[metaContract], chainID, contract.Hex())
+	}
+	return nil
+}
+
+// Status 索引状态
+type Status struct {
+	ChainID   string `json:"chainId"`
+	Contract  string `json:"contract"`
+	NextBlock uint64 `json:"nextBlock"`
+	Events    int64  `json:"events"`
+	Auctions  int64  `json:"auctions"`
+}
+
+// Status 返回索引状态, 数据库未绑定合约时返回 nil
+func (s *Store) Status(ctx context.Context) (*Status, error) {
+	meta, err := s.meta(ctx)
+	if err != nil || len(meta) == 0 {
+		return nil, err
+	}
+	status := &Status{ChainID: meta[metaChainID], Contract: meta[metaContract]}
+	if status.NextBlock, err = strconv.ParseUint(meta[metaNextBlock], 10, 64); err != nil {
+		return nil, fmt.Errorf("索引进度 %q 格式错误: %w", meta[metaNextBlock], err)
+	}
+	if err := s.db.QueryRowContext(ctx, `SELECT count(*) FROM events`).Scan(&status.Events); err != nil {
+		return nil, fmt.Errorf("查询事件数量失败: %w", err)
+	}
+	if err := s.db.QueryRowContext(ctx, `SELECT count(*) FROM auctions`).Scan(&status.Auctions); err != nil {
+		return nil, fmt.Errorf("查询拍卖数量失败: %w", err)
+	}
+	return status, nil
+}
+
+func (s *Store) meta(ctx context.Context) (map[string]string, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT key, value FROM meta`)
+	if err != nil {
+		return nil, fmt.Errorf("读取索引信息失败: %w", err)
+	}
+	defer rows.Close()
+	meta := make(map[string]string)
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return nil, fmt.Errorf("读取索引信息失败: %w", err)
+		}
+		meta[key] = value
+	}
+	return meta, rows.Err()
+}
+
+// Cursor 返回下一个待索引的区块, 数据库还没有绑定合约时返回 0
+func (s *Store) Cursor(ctx context.Context) (uint64, error) {
+	var value string
+	err := s.db.QueryRowContext(ctx, `SELECT value FROM meta WHERE key = ?`, metaNextBlock).Scan(&value)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("读取索引进度失败: %w", err)
+	}
+	next, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("索引进度 %q 格式错误: %w", value, err)
+	}
+	return next, nil
+}
+
+// RecentBlocks 按区块号从大到小返回最多 limit 个已保存哈希的区块
+func (s *Store) RecentBlocks(ctx context.Context, limit int) ([]Block, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT number, hash FROM blocks ORDER BY number DESC LIMIT ?`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("读取区块哈希失败: %w", err)
+	}
+	defer rows.Close()
+	var blocks []Block
+	for rows.Next() {
+		var block Block
+		var hash string
+		if err := rows.Scan(&block.Number, &hash); err != nil {
+			return nil, fmt.Errorf("读取区块哈希失败: %w", err)
+		}
+		block.Hash = common.HexToHash(hash)
+		blocks = append(blocks, block)
+	}
+	return blocks, rows.Err()
+}
+
+// HeadTime 返回已索引的最新区块的时间, 还没有索引任何区块时返回 0
+// 拍卖是否到期按该时间判断, 与合约使用的 block.timestamp 一致, 不受本机时钟影响, 索引落后时也与已索引的出价和结束事件保持一致
+func (s *Store) HeadTime(ctx context.Context) (uint64, error) {
+	var t uint64
+	err := s.db.QueryRowContext(ctx, `SELECT time FROM blocks ORDER BY number DESC LIMIT 1`).Scan(&t)
+	if err != nil && err != sql.ErrNoRows {
+		return 0, fmt.Errorf("读取最新区块时间失败: %w", err)
+	}
+	return t, nil
+}
+
+// Apply 在一个事务中写入一段区块范围的事件并推进索引进度
+func (s *Store) Apply(ctx context.Context, batch *Batch) error {
+	return s.tx(ctx, func(tx *sql.Tx) error {
+		for _, block := range append(batch.Blocks, batch.To) {
+			if _, err := tx.ExecContext(ctx, `INSERT OR REPLACE INTO blocks (number, hash, time) VALUES (?, ?, ?)`,
+				block.Number, block.Hash.Hex(), block.Time); err != nil {
+				return fmt.Errorf("写入区块 %d 失败: %w", block.Number, err)
+			}
+		}
+		for _, event := range batch.Events {
+			args, err := json.Marshal(event.Args)
+			if err != nil {
+				return err
+			}
+			var auctionID any
+			if event.AuctionID != nil {
+				auctionID = event.AuctionID.Int64()
+			}
+			if _, err := tx.ExecContext(ctx, `INSERT OR REPLACE INTO events (block_number, log_index, block_time, tx_hash, name, auction_id, args)
+				VALUES (?, ?, ?, ?, ?, ?, ?)`,
+				event.Block, event.LogIndex, event.Time, event.TxHash.Hex(), event.Name, auctionID, string(args)); err != nil {
+				return fmt.Errorf("写入区块 %d 的事件 %s 失败: %w", event.Block, event.Name, err)
+			}
+		}
+		for _, auction := range batch.Auctions {
+			if _, err := tx.ExecContext(ctx, `INSERT OR REPLACE INTO auctions (id, seller, nft_contract, token_id, bid_token, starting_price,
+				start_time, end_time, block_number, tx_hash) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+				auction.ID.Int64(), auction.Seller.Hex(), auction.NFTContract.Hex(), auction.TokenID.String(), auction.BidToken.Hex(),
+				auction.StartingPrice.String(), auction.StartTime, auction.EndTime, auction.Block, auction.TxHash.Hex()); err != nil {
+				return fmt.Errorf("写入拍卖 %s 失败: %w", auction.ID, err)
+			}
+		}
+		return setNextBlock(ctx, tx, batch.To.Number+1)
+	})
+}
+
+// Rollback 删除 number 之后区块的所有记录, 下一个待索引区块回到 number+1
+// 回滚记录保存在 reorgs 表中, 供推送新事件的客户端发现已推送的事件被回滚
+func (s *Store) Rollback(ctx context.Context, number uint64) error {
+	return s.tx(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO reorgs (block_number, time) VALUES (?, ?)`, number, time.Now().Unix()); err != nil {
+			return fmt.Errorf("记录回滚失败: %w", err)
+		}
+		for _, query := range []string{
+			`DELETE FROM events WHERE block_number > ?`,
+			`DELETE FROM auctions WHERE block_number > ?`,
+			`DELETE FROM blocks WHERE number > ?`,
+		} {
+			if _, err := tx.ExecContext(ctx, query, number); err != nil {
+				return fmt.Errorf("回滚区块 %d 之后的记录失败: %w", number, err)
+			}
+		}
+		return setNextBlock(ctx, tx, number+1)
+	})
+}
+
+// LastReorg 返回最近一次回滚的编号, 没有回滚过时返回 0
+func (s *Store) LastReorg(ctx context.Context) (int64, error) {
+	var id int64
+	if err := s.db.QueryRowContext(ctx, `SELECT coalesce(max(id), 0) FROM reorgs`).Scan(&id); err != nil {
+		return 0, fmt.Errorf("查询回滚记录失败: %w", err)
+	}
+	return id, nil
+}
+
+// ReorgsAfter 返回编号 after 之后的回滚中最新的编号和最小的共同区块, 没有新的回滚时 ok 为 false
+func (s *Store) ReorgsAfter(ctx context.Context, after int64) (id int64, number uint64, ok bool, err error) {
+	var lastID, block sql.NullInt64
+	if err := s.db.QueryRowContext(ctx, `SELECT max(id), min(block_number) FROM reorgs WHERE id > ?`, after).Scan(&lastID, &block); err != nil {
+		return 0, 0, false, fmt.Errorf("查询回滚记录失败: %w", err)
+	}
+	if !lastID.Valid {
+		return 0, 0, false, nil
+	}
+	return lastID.Int64, uint64(block.Int64), true, nil
+}
+
+// Prune 删除 number 之前的区块哈希, 这些区块已经足够深, 不再检查重组
+func (s *Store) Prune(ctx context.Context, number uint64) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM blocks WHERE number < ?`, number); err != nil {
+		return fmt.Errorf("清理区块哈希失败: %w", err)
+	}
+	return nil
+}
+
+func setNextBlock(ctx context.Context, tx *sql.Tx, next uint64) error {
+	if _, err := tx.ExecContext(ctx, `UPDATE meta SET value = ? WHERE key = ?`, strconv.FormatUint(next, 10), metaNextBlock); err != nil {
+		return fmt.Errorf("更新索引进度失败: %w", err)
+	}
+	return nil
+}
+
+// tx 在事务中执行 fn, fn 返回错误时回滚
+func (s *Store) tx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("开始数据库事务失败: %w", err)
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("提交数据库事务失败: %w", err)
+	}
+	return nil
+}